@@ -63,6 +63,112 @@ func WithClientLiteralURLs(b bool) ClientOption {
 	}
 }
 
+// WithClientJSONCamelCaseNames configures JSON clients to serialize requests
+// with the default proto3 JSON encoding (lowerCamelCase) rather than the
+// original proto field names, like WithServerJSONCamelCaseNames for servers.
+// Twirp servers accept both. Only used by JSON clients.
+// See: https://pkg.go.dev/google.golang.org/protobuf/encoding/protojson
+func WithClientJSONCamelCaseNames(jsonCamelCase bool) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.setOpt("jsonCamelCase", jsonCamelCase)
+	}
+}
+
+// WithClientJSONEmitDefaults configures JSON clients to include unpopulated
+// fields (default values) in requests. They are skipped by default, which
+// results in smaller requests. Only used by JSON clients.
+func WithClientJSONEmitDefaults(emitDefaults bool) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.setOpt("jsonEmitDefaults", emitDefaults)
+	}
+}
+
+// WithClientJSONEnumNumbers configures JSON clients to serialize enum values
+// in requests as numbers rather than names. Only used by JSON clients.
+func WithClientJSONEnumNumbers(enumNumbers bool) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.setOpt("jsonEnumNumbers", enumNumbers)
+	}
+}
+
+// WithClientJSONStrict configures JSON clients to fail with a twirp.Internal
+// error on responses with unknown fields. By default unknown fields are
+// discarded, which allows the server to use a newer version of the schema than
+// the client. Only used by JSON clients.
+func WithClientJSONStrict(strict bool) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.setOpt("jsonStrict", strict)
+	}
+}
+
+// WithClientMaxResponseBytes limits the size of response bodies read by the
+// client, including error responses. Responses with a larger body fail with
+// a twirp.ResourceExhausted error, and the Error hook is triggered. A value of
+// 0 or less (default) means no limit.
+func WithClientMaxResponseBytes(maxBytes int64) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.setOpt("maxResponseBytes", maxBytes)
+	}
+}
+
+// WithClientCompression enables compression with the given encodings
+// (e.g. "gzip"), in order of preference. The client advertises the encodings in
+// the Accept-Encoding header, and decompresses responses that use any of them.
+// Requests are compressed with the first encoding, only if they are at least
+// DefaultCompressionMinBytes long (see WithClientCompressionMinBytes), so the
+// server must be configured to accept that encoding with
+// twirp.WithServerCompression.
+//
+// The encodings must be registered with twirp.RegisterCompressor ("gzip" is
+// registered by default), otherwise this function panics.
+func WithClientCompression(encodings ...string) ClientOption {
+	compressors := mustLookupCompressors("WithClientCompression", encodings)
+	return func(opts *ClientOptions) {
+		opts.setOpt("compressors", compressors)
+	}
+}
+
+// WithClientCompressionMinBytes sets the minimum size of a request body to be
+// compressed, when compression is enabled with WithClientCompression.
+// Smaller requests are sent uncompressed. A negative value disables
+// compression of requests, while responses can still be compressed by the
+// server. If not specified, DefaultCompressionMinBytes is used.
+func WithClientCompressionMinBytes(minBytes int) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.setOpt("compressionMinBytes", minBytes)
+	}
+}
+
+// WithClientCodec makes the client serialize requests and responses with the
+// codec, and use its ContentType in the Content-Type and Accept headers,
+// instead of the Protobuf or JSON encoding of the client constructor. The
+// server must be configured to accept that content type with
+// twirp.WithServerCodecs (unless it is a built-in content type). Requests
+// are sent with POST, even with WithClientHTTPGet, if the codec has a content
+// type other than "application/protobuf" or "application/json", because query
+// strings can only encode those.
+// A nil codec restores the encoding of the client constructor.
+func WithClientCodec(codec Codec) ClientOption {
+	return func(opts *ClientOptions) {
+		if codec == nil {
+			delete(opts.m, "codec")
+			return
+		}
+		opts.setOpt("codec", codec)
+	}
+}
+
+// WithClientHTTPGet makes the client use HTTP GET requests for methods marked
+// with the option idempotency_level = NO_SIDE_EFFECTS. The request message is
+// encoded in the query string instead of the request body, which allows
+// responses to be cached by HTTP caches and CDNs. Other methods keep using
+// POST requests. Disabled by default.
+func WithClientHTTPGet(enabled bool) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.setOpt("httpGet", enabled)
+	}
+}
+
 // ClientHooks is a container for callbacks that can instrument a
 // Twirp-generated client. These callbacks all accept a context and some return
 // a context. They can use this to add to the context, appending values or
@@ -163,3 +163,18 @@ func TestWithClientLiteralURLs(t *testing.T) {
 		return
 	}
 }
+
+func TestWithClientCodec(t *testing.T) {
+	opts := &ClientOptions{}
+	WithClientCodec(testCodec("application/x-protobuf"))(opts)
+
+	var codec Codec
+	if ok := opts.ReadOpt("codec", &codec); !ok || codec != testCodec("application/x-protobuf") {
+		t.Errorf("option 'codec' expected to be set, ok: %v, val: %v", ok, codec)
+	}
+
+	WithClientCodec(nil)(opts)
+	if ok := opts.ReadOpt("codec", &codec); ok {
+		t.Errorf("option 'codec' expected to be removed by a nil codec")
+	}
+}
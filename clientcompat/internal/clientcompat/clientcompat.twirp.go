@@ -1,4 +1,4 @@
-// Code generated by protoc-gen-twirp v8.1.3, DO NOT EDIT.
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
 // source: clientcompat.proto
 
 package clientcompat
@@ -7,25 +7,18 @@ import context "context"
 import fmt "fmt"
 import http "net/http"
 import io "io"
-import json "encoding/json"
 import strconv "strconv"
-import strings "strings"
+import time "time"
 
-import protojson "google.golang.org/protobuf/encoding/protojson"
-import proto "google.golang.org/protobuf/proto"
 import twirp "github.com/twitchtv/twirp"
 import ctxsetters "github.com/twitchtv/twirp/ctxsetters"
-
-import bytes "bytes"
-import errors "errors"
-import path "path"
-import url "net/url"
+import twirpruntime "github.com/twitchtv/twirp/twirpruntime"
 
 // Version compatibility assertion.
 // If the constant is not defined in the package, that likely means
 // the package needs to be updated to work with this generated code.
 // See https://twitchtv.github.io/twirp/docs/version_matrix.html
-const _ = twirp.TwirpPackageMinVersion_8_1_0
+const _ = twirp.TwirpPackageMinVersion_8_3_0
 
 // =======================
 // CompatService Interface
@@ -42,17 +35,21 @@ type CompatService interface {
 // =============================
 
 type compatServiceProtobufClient struct {
-	client      HTTPClient
-	urls        [2]string
-	interceptor twirp.Interceptor
-	opts        twirp.ClientOptions
+	client            HTTPClient
+	urls              [2]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // Protobuf unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
 }
 
 // NewCompatServiceProtobufClient creates a Protobuf client that implements the CompatService interface.
 // It communicates using Protobuf and can be configured with a custom HTTPClient.
 func NewCompatServiceProtobufClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) CompatService {
 	if c, ok := client.(*http.Client); ok {
-		client = withoutRedirects(c)
+		client = twirpruntime.WithoutRedirects(c)
 	}
 
 	clientOpts := twirp.ClientOptions{}
@@ -67,20 +64,28 @@ func NewCompatServiceProtobufClient(baseURL string, client HTTPClient, opts ...t
 	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
 		pathPrefix = "/twirp" // default prefix
 	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ProtobufCodec
+	_ = clientOpts.ReadOpt("codec", &codec)
 
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
-	serviceURL := sanitizeBaseURL(baseURL)
-	serviceURL += baseServicePath(pathPrefix, "twirp.clientcompat", "CompatService")
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "twirp.clientcompat", "CompatService")
 	urls := [2]string{
 		serviceURL + "Method",
 		serviceURL + "NoopMethod",
 	}
 
 	return &compatServiceProtobufClient{
-		client:      client,
-		urls:        urls,
-		interceptor: twirp.ChainInterceptors(clientOpts.Interceptors...),
-		opts:        clientOpts,
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
 	}
 }
 
@@ -88,6 +93,7 @@ func (c *compatServiceProtobufClient) Method(ctx context.Context, in *Req) (*Res
 	ctx = ctxsetters.WithPackageName(ctx, "twirp.clientcompat")
 	ctx = ctxsetters.WithServiceName(ctx, "CompatService")
 	ctx = ctxsetters.WithMethodName(ctx, "Method")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Method"])
 	caller := c.callMethod
 	if c.interceptor != nil {
 		caller = func(ctx context.Context, req *Req) (*Resp, error) {
@@ -115,17 +121,17 @@ func (c *compatServiceProtobufClient) Method(ctx context.Context, in *Req) (*Res
 
 func (c *compatServiceProtobufClient) callMethod(ctx context.Context, in *Req) (*Resp, error) {
 	out := new(Resp)
-	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, false)
 	if err != nil {
 		twerr, ok := err.(twirp.Error)
 		if !ok {
 			twerr = twirp.InternalErrorWith(err)
 		}
-		callClientError(ctx, c.opts.Hooks, twerr)
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
 		return nil, err
 	}
 
-	callClientResponseReceived(ctx, c.opts.Hooks)
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
 
 	return out, nil
 }
@@ -134,6 +140,7 @@ func (c *compatServiceProtobufClient) NoopMethod(ctx context.Context, in *Empty)
 	ctx = ctxsetters.WithPackageName(ctx, "twirp.clientcompat")
 	ctx = ctxsetters.WithServiceName(ctx, "CompatService")
 	ctx = ctxsetters.WithMethodName(ctx, "NoopMethod")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["NoopMethod"])
 	caller := c.callNoopMethod
 	if c.interceptor != nil {
 		caller = func(ctx context.Context, req *Empty) (*Empty, error) {
@@ -161,37 +168,47 @@ func (c *compatServiceProtobufClient) NoopMethod(ctx context.Context, in *Empty)
 
 func (c *compatServiceProtobufClient) callNoopMethod(ctx context.Context, in *Empty) (*Empty, error) {
 	out := new(Empty)
-	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out, c.codec, c.maxResponseBytes, c.compression, false)
 	if err != nil {
 		twerr, ok := err.(twirp.Error)
 		if !ok {
 			twerr = twirp.InternalErrorWith(err)
 		}
-		callClientError(ctx, c.opts.Hooks, twerr)
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
 		return nil, err
 	}
 
-	callClientResponseReceived(ctx, c.opts.Hooks)
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
 
 	return out, nil
 }
 
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *compatServiceProtobufClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
 // =========================
 // CompatService JSON Client
 // =========================
 
 type compatServiceJSONClient struct {
-	client      HTTPClient
-	urls        [2]string
-	interceptor twirp.Interceptor
-	opts        twirp.ClientOptions
+	client            HTTPClient
+	urls              [2]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // JSON unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
 }
 
 // NewCompatServiceJSONClient creates a JSON client that implements the CompatService interface.
 // It communicates using JSON and can be configured with a custom HTTPClient.
 func NewCompatServiceJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) CompatService {
 	if c, ok := client.(*http.Client); ok {
-		client = withoutRedirects(c)
+		client = twirpruntime.WithoutRedirects(c)
 	}
 
 	clientOpts := twirp.ClientOptions{}
@@ -206,20 +223,28 @@ func NewCompatServiceJSONClient(baseURL string, client HTTPClient, opts ...twirp
 	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
 		pathPrefix = "/twirp" // default prefix
 	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ReadJSONCodec(&clientOpts)
+	_ = clientOpts.ReadOpt("codec", &codec)
 
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
-	serviceURL := sanitizeBaseURL(baseURL)
-	serviceURL += baseServicePath(pathPrefix, "twirp.clientcompat", "CompatService")
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "twirp.clientcompat", "CompatService")
 	urls := [2]string{
 		serviceURL + "Method",
 		serviceURL + "NoopMethod",
 	}
 
 	return &compatServiceJSONClient{
-		client:      client,
-		urls:        urls,
-		interceptor: twirp.ChainInterceptors(clientOpts.Interceptors...),
-		opts:        clientOpts,
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
 	}
 }
 
@@ -227,6 +252,7 @@ func (c *compatServiceJSONClient) Method(ctx context.Context, in *Req) (*Resp, e
 	ctx = ctxsetters.WithPackageName(ctx, "twirp.clientcompat")
 	ctx = ctxsetters.WithServiceName(ctx, "CompatService")
 	ctx = ctxsetters.WithMethodName(ctx, "Method")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Method"])
 	caller := c.callMethod
 	if c.interceptor != nil {
 		caller = func(ctx context.Context, req *Req) (*Resp, error) {
@@ -254,17 +280,17 @@ func (c *compatServiceJSONClient) Method(ctx context.Context, in *Req) (*Resp, e
 
 func (c *compatServiceJSONClient) callMethod(ctx context.Context, in *Req) (*Resp, error) {
 	out := new(Resp)
-	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, false)
 	if err != nil {
 		twerr, ok := err.(twirp.Error)
 		if !ok {
 			twerr = twirp.InternalErrorWith(err)
 		}
-		callClientError(ctx, c.opts.Hooks, twerr)
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
 		return nil, err
 	}
 
-	callClientResponseReceived(ctx, c.opts.Hooks)
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
 
 	return out, nil
 }
@@ -273,6 +299,7 @@ func (c *compatServiceJSONClient) NoopMethod(ctx context.Context, in *Empty) (*E
 	ctx = ctxsetters.WithPackageName(ctx, "twirp.clientcompat")
 	ctx = ctxsetters.WithServiceName(ctx, "CompatService")
 	ctx = ctxsetters.WithMethodName(ctx, "NoopMethod")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["NoopMethod"])
 	caller := c.callNoopMethod
 	if c.interceptor != nil {
 		caller = func(ctx context.Context, req *Empty) (*Empty, error) {
@@ -300,68 +327,105 @@ func (c *compatServiceJSONClient) NoopMethod(ctx context.Context, in *Empty) (*E
 
 func (c *compatServiceJSONClient) callNoopMethod(ctx context.Context, in *Empty) (*Empty, error) {
 	out := new(Empty)
-	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out, c.codec, c.maxResponseBytes, c.compression, false)
 	if err != nil {
 		twerr, ok := err.(twirp.Error)
 		if !ok {
 			twerr = twirp.InternalErrorWith(err)
 		}
-		callClientError(ctx, c.opts.Hooks, twerr)
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
 		return nil, err
 	}
 
-	callClientResponseReceived(ctx, c.opts.Hooks)
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
 
 	return out, nil
 }
 
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *compatServiceJSONClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
 // ============================
 // CompatService Server Handler
 // ============================
 
 type compatServiceServer struct {
 	CompatService
-	interceptor      twirp.Interceptor
-	hooks            *twirp.ServerHooks
-	pathPrefix       string // prefix for routing
-	jsonSkipDefaults bool   // do not include unpopulated fields (default values) in the response
-	jsonCamelCase    bool   // JSON fields are serialized as lowerCamelCase rather than keeping the original proto names
+	interceptor           twirp.Interceptor
+	hooks                 *twirp.ServerHooks
+	pathPrefix            string                                                 // prefix for routing
+	maxRequestBytes       int64                                                  // limit for request bodies, no limit if 0 or less
+	methodMaxRequestBytes map[string]int64                                       // per-method overrides of maxRequestBytes
+	compression           twirpruntime.Compression                               // request decompression and response compression
+	requestTimeouts       bool                                                   // apply timeouts from the Twirp-Timeout request header
+	maxRequestTimeout     time.Duration                                          // limit for timeouts from the Twirp-Timeout request header
+	panicHandler          func(context.Context, interface{}, []byte) twirp.Error // handles panics in methods, re-panic if nil
+	errorTransformer      func(context.Context, error) twirp.Error               // maps errors before they are sent, optional
+	codecs                map[string]twirp.Codec                                 // codecs by content type, including the built-in Protobuf and JSON codecs
+	methodDescriptors     twirpruntime.MethodDescriptors                         // added to request contexts
 }
 
 // NewCompatServiceServer builds a TwirpServer that can be used as an http.Handler to handle
 // HTTP requests that are routed to the right method in the provided svc implementation.
 // The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
 func NewCompatServiceServer(svc CompatService, opts ...interface{}) TwirpServer {
-	serverOpts := newServerOpts(opts)
+	serverOpts := twirpruntime.NewServerOptions(opts)
 
 	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
-	jsonSkipDefaults := false
-	_ = serverOpts.ReadOpt("jsonSkipDefaults", &jsonSkipDefaults)
-	jsonCamelCase := false
-	_ = serverOpts.ReadOpt("jsonCamelCase", &jsonCamelCase)
+	var maxRequestBytes int64
+	_ = serverOpts.ReadOpt("maxRequestBytes", &maxRequestBytes)
+	var methodMaxRequestBytes map[string]int64
+	_ = serverOpts.ReadOpt("methodMaxRequestBytes", &methodMaxRequestBytes)
+	requestTimeouts := true
+	_ = serverOpts.ReadOpt("requestTimeouts", &requestTimeouts)
+	var maxRequestTimeout time.Duration
+	_ = serverOpts.ReadOpt("maxRequestTimeout", &maxRequestTimeout)
+	var panicHandler func(context.Context, interface{}, []byte) twirp.Error
+	_ = serverOpts.ReadOpt("panicHandler", &panicHandler)
+	var errorTransformer func(context.Context, error) twirp.Error
+	_ = serverOpts.ReadOpt("errorTransformer", &errorTransformer)
 	var pathPrefix string
 	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
 		pathPrefix = "/twirp" // default prefix
 	}
 
 	return &compatServiceServer{
-		CompatService:    svc,
-		hooks:            serverOpts.Hooks,
-		interceptor:      twirp.ChainInterceptors(serverOpts.Interceptors...),
-		pathPrefix:       pathPrefix,
-		jsonSkipDefaults: jsonSkipDefaults,
-		jsonCamelCase:    jsonCamelCase,
+		CompatService:         svc,
+		hooks:                 serverOpts.Hooks,
+		interceptor:           twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:            pathPrefix,
+		maxRequestBytes:       maxRequestBytes,
+		methodMaxRequestBytes: methodMaxRequestBytes,
+		compression:           twirpruntime.ReadCompression(serverOpts),
+		requestTimeouts:       requestTimeouts,
+		maxRequestTimeout:     maxRequestTimeout,
+		panicHandler:          panicHandler,
+		errorTransformer:      errorTransformer,
+		codecs:                twirpruntime.ReadServerCodecs(serverOpts),
+		methodDescriptors:     twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
 	}
 }
 
 // writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// The error is first mapped by the error transformer, if any (see twirp.WithServerErrorTransformer).
 // If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
 func (s *compatServiceServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
-	writeError(ctx, resp, err, s.hooks)
+	twirpruntime.WriteError(ctx, resp, twirpruntime.TransformError(ctx, err, s.errorTransformer), s.hooks)
 }
 
 // handleRequestBodyError is used to handle error when the twirp server cannot read request
 func (s *compatServiceServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if twerr, ok := err.(twirp.Error); ok {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if twerr := twirpruntime.BodyTooLargeTwirpError(err, "request "); twerr != nil {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
 	if context.Canceled == ctx.Err() {
 		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
 		return
@@ -370,7 +434,17 @@ func (s *compatServiceServer) handleRequestBodyError(ctx context.Context, resp h
 		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
 		return
 	}
-	s.writeError(ctx, resp, twirp.WrapError(malformedRequestError(msg), err))
+	s.writeError(ctx, resp, twirp.WrapError(twirpruntime.MalformedRequestError(msg), err))
+}
+
+// requestBody returns the request body, decompressed if it has a Content-Encoding,
+// and limited to the max size configured for the method. It must be closed after reading.
+func (s *compatServiceServer) requestBody(req *http.Request, method string) (io.ReadCloser, error) {
+	maxBytes := s.maxRequestBytes
+	if n, ok := s.methodMaxRequestBytes[method]; ok {
+		maxBytes = n
+	}
+	return twirpruntime.RequestBody(req, maxBytes, s.compression)
 }
 
 // CompatServicePathPrefix is a convenience constant that may identify URL paths.
@@ -381,12 +455,20 @@ const CompatServicePathPrefix = "/twirp/twirp.clientcompat.CompatService/"
 
 func (s *compatServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
+	if hooks, interceptor, ok := twirpruntime.MuxServerOptions(ctx); ok {
+		// Served by a twirp.Mux: apply the shared hooks and interceptors before the ones from this server
+		shared := *s
+		shared.hooks = twirp.ChainHooks(hooks, s.hooks)
+		shared.interceptor = twirp.ChainInterceptors(interceptor, s.interceptor)
+		s = &shared
+	}
 	ctx = ctxsetters.WithPackageName(ctx, "twirp.clientcompat")
 	ctx = ctxsetters.WithServiceName(ctx, "CompatService")
 	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	ctx = twirpruntime.WithErrorDetailsAccepted(ctx, req) // only clients that send Twirp-Error-Details get error details
 
 	var err error
-	ctx, err = callRequestReceived(ctx, s.hooks)
+	ctx, err = twirpruntime.CallRequestReceived(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
 		return
@@ -394,20 +476,20 @@ func (s *compatServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Requ
 
 	if req.Method != "POST" {
 		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
 		return
 	}
 
 	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
-	prefix, pkgService, method := parseTwirpPath(req.URL.Path)
+	prefix, pkgService, method := twirpruntime.ParseTwirpPath(req.URL.Path)
 	if pkgService != "twirp.clientcompat.CompatService" {
 		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
 		return
 	}
 	if prefix != s.pathPrefix {
 		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
 		return
 	}
 
@@ -420,129 +502,58 @@ func (s *compatServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Requ
 		return
 	default:
 		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
 		return
 	}
 }
 
 func (s *compatServiceServer) serveMethod(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
-	header := req.Header.Get("Content-Type")
-	i := strings.Index(header, ";")
-	if i == -1 {
-		i = len(header)
-	}
-	switch strings.TrimSpace(strings.ToLower(header[:i])) {
-	case "application/json":
-		s.serveMethodJSON(ctx, resp, req)
-	case "application/protobuf":
-		s.serveMethodProtobuf(ctx, resp, req)
-	default:
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
-		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
 		s.writeError(ctx, resp, twerr)
+		return
 	}
+	s.serveMethodCodec(ctx, resp, req, codec)
 }
 
-func (s *compatServiceServer) serveMethodJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *compatServiceServer) serveMethodCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "Method")
-	ctx, err = callRequestRouted(ctx, s.hooks)
-	if err != nil {
-		s.writeError(ctx, resp, err)
-		return
-	}
-
-	d := json.NewDecoder(req.Body)
-	rawReqBody := json.RawMessage{}
-	if err := d.Decode(&rawReqBody); err != nil {
-		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
-		return
-	}
-	reqContent := new(Req)
-	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
-	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
-		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
-		return
-	}
-
-	handler := s.CompatService.Method
-	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *Req) (*Resp, error) {
-			resp, err := s.interceptor(
-				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*Req)
-					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*Req) when calling interceptor")
-					}
-					return s.CompatService.Method(ctx, typedReq)
-				},
-			)(ctx, req)
-			if resp != nil {
-				typedResp, ok := resp.(*Resp)
-				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*Resp) when calling interceptor")
-				}
-				return typedResp, err
-			}
-			return nil, err
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
 		}
 	}
 
-	// Call service method
-	var respContent *Resp
-	func() {
-		defer ensurePanicResponses(ctx, resp, s.hooks)
-		respContent, err = handler(ctx, reqContent)
-	}()
-
+	ctx = ctxsetters.WithMethodName(ctx, "Method")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["Method"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
 		return
 	}
-	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *Resp and nil error while calling Method. nil responses are not supported"))
-		return
-	}
-
-	ctx = callResponsePrepared(ctx, s.hooks)
-
-	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
-	respBytes, err := marshaler.Marshal(respContent)
-	if err != nil {
-		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
-		return
-	}
-
-	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
-	resp.Header().Set("Content-Type", "application/json")
-	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
-	resp.WriteHeader(http.StatusOK)
-
-	if n, err := resp.Write(respBytes); err != nil {
-		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
-		twerr := twirp.NewError(twirp.Unknown, msg)
-		ctx = callError(ctx, s.hooks, twerr)
-	}
-	callResponseSent(ctx, s.hooks)
-}
 
-func (s *compatServiceServer) serveMethodProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
-	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "Method")
-	ctx, err = callRequestRouted(ctx, s.hooks)
+	reqBody, err := s.requestBody(req, "Method")
 	if err != nil {
-		s.writeError(ctx, resp, err)
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
 		return
 	}
+	defer func() { _ = reqBody.Close() }()
 
-	buf, err := io.ReadAll(req.Body)
+	buf, err := io.ReadAll(reqBody)
 	if err != nil {
-		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
 		return
 	}
 	reqContent := new(Req)
-	if err = proto.Unmarshal(buf, reqContent); err != nil {
-		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
 		return
 	}
 
@@ -572,8 +583,11 @@ func (s *compatServiceServer) serveMethodProtobuf(ctx context.Context, resp http
 	// Call service method
 	var respContent *Resp
 	func() {
-		defer ensurePanicResponses(ctx, resp, s.hooks)
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
 		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
 	}()
 
 	if err != nil {
@@ -585,144 +599,80 @@ func (s *compatServiceServer) serveMethodProtobuf(ctx context.Context, resp http
 		return
 	}
 
-	ctx = callResponsePrepared(ctx, s.hooks)
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
 
-	respBytes, err := proto.Marshal(respContent)
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
 	if err != nil {
-		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
 		return
 	}
 
 	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
-	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
 	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
 	resp.WriteHeader(http.StatusOK)
 	if n, err := resp.Write(respBytes); err != nil {
 		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
 		twerr := twirp.NewError(twirp.Unknown, msg)
-		ctx = callError(ctx, s.hooks, twerr)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
 	}
-	callResponseSent(ctx, s.hooks)
+	twirpruntime.CallResponseSent(ctx, s.hooks)
 }
 
 func (s *compatServiceServer) serveNoopMethod(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
-	header := req.Header.Get("Content-Type")
-	i := strings.Index(header, ";")
-	if i == -1 {
-		i = len(header)
-	}
-	switch strings.TrimSpace(strings.ToLower(header[:i])) {
-	case "application/json":
-		s.serveNoopMethodJSON(ctx, resp, req)
-	case "application/protobuf":
-		s.serveNoopMethodProtobuf(ctx, resp, req)
-	default:
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
-		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
 		s.writeError(ctx, resp, twerr)
+		return
 	}
+	s.serveNoopMethodCodec(ctx, resp, req, codec)
 }
 
-func (s *compatServiceServer) serveNoopMethodJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *compatServiceServer) serveNoopMethodCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "NoopMethod")
-	ctx, err = callRequestRouted(ctx, s.hooks)
-	if err != nil {
-		s.writeError(ctx, resp, err)
-		return
-	}
-
-	d := json.NewDecoder(req.Body)
-	rawReqBody := json.RawMessage{}
-	if err := d.Decode(&rawReqBody); err != nil {
-		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
-		return
-	}
-	reqContent := new(Empty)
-	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
-	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
-		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
-		return
-	}
-
-	handler := s.CompatService.NoopMethod
-	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *Empty) (*Empty, error) {
-			resp, err := s.interceptor(
-				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*Empty)
-					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*Empty) when calling interceptor")
-					}
-					return s.CompatService.NoopMethod(ctx, typedReq)
-				},
-			)(ctx, req)
-			if resp != nil {
-				typedResp, ok := resp.(*Empty)
-				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*Empty) when calling interceptor")
-				}
-				return typedResp, err
-			}
-			return nil, err
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
 		}
 	}
 
-	// Call service method
-	var respContent *Empty
-	func() {
-		defer ensurePanicResponses(ctx, resp, s.hooks)
-		respContent, err = handler(ctx, reqContent)
-	}()
-
+	ctx = ctxsetters.WithMethodName(ctx, "NoopMethod")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["NoopMethod"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
 		return
 	}
-	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *Empty and nil error while calling NoopMethod. nil responses are not supported"))
-		return
-	}
-
-	ctx = callResponsePrepared(ctx, s.hooks)
-
-	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
-	respBytes, err := marshaler.Marshal(respContent)
-	if err != nil {
-		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
-		return
-	}
-
-	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
-	resp.Header().Set("Content-Type", "application/json")
-	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
-	resp.WriteHeader(http.StatusOK)
-
-	if n, err := resp.Write(respBytes); err != nil {
-		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
-		twerr := twirp.NewError(twirp.Unknown, msg)
-		ctx = callError(ctx, s.hooks, twerr)
-	}
-	callResponseSent(ctx, s.hooks)
-}
 
-func (s *compatServiceServer) serveNoopMethodProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
-	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "NoopMethod")
-	ctx, err = callRequestRouted(ctx, s.hooks)
+	reqBody, err := s.requestBody(req, "NoopMethod")
 	if err != nil {
-		s.writeError(ctx, resp, err)
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
 		return
 	}
+	defer func() { _ = reqBody.Close() }()
 
-	buf, err := io.ReadAll(req.Body)
+	buf, err := io.ReadAll(reqBody)
 	if err != nil {
-		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
 		return
 	}
 	reqContent := new(Empty)
-	if err = proto.Unmarshal(buf, reqContent); err != nil {
-		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
 		return
 	}
 
@@ -752,8 +702,11 @@ func (s *compatServiceServer) serveNoopMethodProtobuf(ctx context.Context, resp
 	// Call service method
 	var respContent *Empty
 	func() {
-		defer ensurePanicResponses(ctx, resp, s.hooks)
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
 		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
 	}()
 
 	if err != nil {
@@ -765,24 +718,31 @@ func (s *compatServiceServer) serveNoopMethodProtobuf(ctx context.Context, resp
 		return
 	}
 
-	ctx = callResponsePrepared(ctx, s.hooks)
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
 
-	respBytes, err := proto.Marshal(respContent)
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
 	if err != nil {
-		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
 		return
 	}
 
 	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
-	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
 	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
 	resp.WriteHeader(http.StatusOK)
 	if n, err := resp.Write(respBytes); err != nil {
 		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
 		twerr := twirp.NewError(twirp.Unknown, msg)
-		ctx = callError(ctx, s.hooks, twerr)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
 	}
-	callResponseSent(ctx, s.hooks)
+	twirpruntime.CallResponseSent(ctx, s.hooks)
 }
 
 func (s *compatServiceServer) ServiceDescriptor() ([]byte, int) {
@@ -790,14 +750,17 @@ func (s *compatServiceServer) ServiceDescriptor() ([]byte, int) {
 }
 
 func (s *compatServiceServer) ProtocGenTwirpVersion() string {
-	return "v8.1.3"
+	return "v8.3.0"
 }
 
+// AppliesMuxServerOptions marks servers that apply the hooks and interceptors shared by a twirp.Mux.
+func (s *compatServiceServer) AppliesMuxServerOptions() {}
+
 // PathPrefix returns the base service path, in the form: "/<prefix>/<package>.<Service>/"
 // that is everything in a Twirp route except for the <Method>. This can be used for routing,
 // for example to identify the requests that are targeted to this service in a mux.
 func (s *compatServiceServer) PathPrefix() string {
-	return baseServicePath(s.pathPrefix, "twirp.clientcompat", "CompatService")
+	return twirpruntime.BaseServicePath(s.pathPrefix, "twirp.clientcompat", "CompatService")
 }
 
 // =====
@@ -810,8 +773,7 @@ func (s *compatServiceServer) PathPrefix() string {
 //
 // HTTPClient implementations should not follow redirects. Redirects are
 // automatically disabled if *(net/http).Client is passed to client
-// constructors. See the withoutRedirects function in this file for more
-// details.
+// constructors. See twirpruntime.WithoutRedirects for more details.
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -844,529 +806,15 @@ type TwirpServer interface {
 	PathPrefix() string
 }
 
-func newServerOpts(opts []interface{}) *twirp.ServerOptions {
-	serverOpts := &twirp.ServerOptions{}
-	for _, opt := range opts {
-		switch o := opt.(type) {
-		case twirp.ServerOption:
-			o(serverOpts)
-		case *twirp.ServerHooks: // backwards compatibility, allow to specify hooks as an argument
-			twirp.WithServerHooks(o)(serverOpts)
-		case nil: // backwards compatibility, allow nil value for the argument
-			continue
-		default:
-			panic(fmt.Sprintf("Invalid option type %T, please use a twirp.ServerOption", o))
-		}
-	}
-	return serverOpts
-}
-
 // WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).
 // Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.
 // If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
 func WriteError(resp http.ResponseWriter, err error) {
-	writeError(context.Background(), resp, err, nil)
-}
-
-// writeError writes Twirp errors in the response and triggers hooks.
-func writeError(ctx context.Context, resp http.ResponseWriter, err error, hooks *twirp.ServerHooks) {
-	// Convert to a twirp.Error. Non-twirp errors are converted to internal errors.
-	var twerr twirp.Error
-	if !errors.As(err, &twerr) {
-		twerr = twirp.InternalErrorWith(err)
-	}
-
-	statusCode := twirp.ServerHTTPStatusFromErrorCode(twerr.Code())
-	ctx = ctxsetters.WithStatusCode(ctx, statusCode)
-	ctx = callError(ctx, hooks, twerr)
-
-	respBody := marshalErrorToJSON(twerr)
-
-	resp.Header().Set("Content-Type", "application/json") // Error responses are always JSON
-	resp.Header().Set("Content-Length", strconv.Itoa(len(respBody)))
-	resp.WriteHeader(statusCode) // set HTTP status code and send response
-
-	_, writeErr := resp.Write(respBody)
-	if writeErr != nil {
-		// We have three options here. We could log the error, call the Error
-		// hook, or just silently ignore the error.
-		//
-		// Logging is unacceptable because we don't have a user-controlled
-		// logger; writing out to stderr without permission is too rude.
-		//
-		// Calling the Error hook would confuse users: it would mean the Error
-		// hook got called twice for one request, which is likely to lead to
-		// duplicated log messages and metrics, no matter how well we document
-		// the behavior.
-		//
-		// Silently ignoring the error is our least-bad option. It's highly
-		// likely that the connection is broken and the original 'err' says
-		// so anyway.
-		_ = writeErr
-	}
-
-	callResponseSent(ctx, hooks)
-}
-
-// sanitizeBaseURL parses the baseURL, and adds the "http" scheme if needed.
-// If the URL is unparsable, the baseURL is returned unchanged.
-func sanitizeBaseURL(baseURL string) string {
-	u, err := url.Parse(baseURL)
-	if err != nil {
-		return baseURL // invalid URL will fail later when making requests
-	}
-	if u.Scheme == "" {
-		u.Scheme = "http"
-	}
-	return u.String()
-}
-
-// baseServicePath composes the path prefix for the service (without <Method>).
-// e.g.: baseServicePath("/twirp", "my.pkg", "MyService")
-//
-//	returns => "/twirp/my.pkg.MyService/"
-//
-// e.g.: baseServicePath("", "", "MyService")
-//
-//	returns => "/MyService/"
-func baseServicePath(prefix, pkg, service string) string {
-	fullServiceName := service
-	if pkg != "" {
-		fullServiceName = pkg + "." + service
-	}
-	return path.Join("/", prefix, fullServiceName) + "/"
-}
-
-// parseTwirpPath extracts path components form a valid Twirp route.
-// Expected format: "[<prefix>]/<package>.<Service>/<Method>"
-// e.g.: prefix, pkgService, method := parseTwirpPath("/twirp/pkg.Svc/MakeHat")
-func parseTwirpPath(path string) (string, string, string) {
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 {
-		return "", "", ""
-	}
-	method := parts[len(parts)-1]
-	pkgService := parts[len(parts)-2]
-	prefix := strings.Join(parts[0:len(parts)-2], "/")
-	return prefix, pkgService, method
-}
-
-// getCustomHTTPReqHeaders retrieves a copy of any headers that are set in
-// a context through the twirp.WithHTTPRequestHeaders function.
-// If there are no headers set, or if they have the wrong type, nil is returned.
-func getCustomHTTPReqHeaders(ctx context.Context) http.Header {
-	header, ok := twirp.HTTPRequestHeaders(ctx)
-	if !ok || header == nil {
-		return nil
-	}
-	copied := make(http.Header)
-	for k, vv := range header {
-		if vv == nil {
-			copied[k] = nil
-			continue
-		}
-		copied[k] = make([]string, len(vv))
-		copy(copied[k], vv)
-	}
-	return copied
-}
-
-// newRequest makes an http.Request from a client, adding common headers.
-func newRequest(ctx context.Context, url string, reqBody io.Reader, contentType string) (*http.Request, error) {
-	req, err := http.NewRequest("POST", url, reqBody)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	if customHeader := getCustomHTTPReqHeaders(ctx); customHeader != nil {
-		req.Header = customHeader
-	}
-	req.Header.Set("Accept", contentType)
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Twirp-Version", "v8.1.3")
-	return req, nil
-}
-
-// JSON serialization for errors
-type twerrJSON struct {
-	Code string            `json:"code"`
-	Msg  string            `json:"msg"`
-	Meta map[string]string `json:"meta,omitempty"`
-}
-
-// marshalErrorToJSON returns JSON from a twirp.Error, that can be used as HTTP error response body.
-// If serialization fails, it will use a descriptive Internal error instead.
-func marshalErrorToJSON(twerr twirp.Error) []byte {
-	// make sure that msg is not too large
-	msg := twerr.Msg()
-	if len(msg) > 1e6 {
-		msg = msg[:1e6]
-	}
-
-	tj := twerrJSON{
-		Code: string(twerr.Code()),
-		Msg:  msg,
-		Meta: twerr.MetaMap(),
-	}
-
-	buf, err := json.Marshal(&tj)
-	if err != nil {
-		buf = []byte("{\"type\": \"" + twirp.Internal + "\", \"msg\": \"There was an error but it could not be serialized into JSON\"}") // fallback
-	}
-
-	return buf
-}
-
-// errorFromResponse builds a twirp.Error from a non-200 HTTP response.
-// If the response has a valid serialized Twirp error, then it's returned.
-// If not, the response status code is used to generate a similar twirp
-// error. See twirpErrorFromIntermediary for more info on intermediary errors.
-func errorFromResponse(resp *http.Response) twirp.Error {
-	statusCode := resp.StatusCode
-	statusText := http.StatusText(statusCode)
-
-	if isHTTPRedirect(statusCode) {
-		// Unexpected redirect: it must be an error from an intermediary.
-		// Twirp clients don't follow redirects automatically, Twirp only handles
-		// POST requests, redirects should only happen on GET and HEAD requests.
-		location := resp.Header.Get("Location")
-		msg := fmt.Sprintf("unexpected HTTP status code %d %q received, Location=%q", statusCode, statusText, location)
-		return twirpErrorFromIntermediary(statusCode, msg, location)
-	}
-
-	respBodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return wrapInternal(err, "failed to read server error response body")
-	}
-
-	var tj twerrJSON
-	dec := json.NewDecoder(bytes.NewReader(respBodyBytes))
-	dec.DisallowUnknownFields()
-	if err := dec.Decode(&tj); err != nil || tj.Code == "" {
-		// Invalid JSON response; it must be an error from an intermediary.
-		msg := fmt.Sprintf("Error from intermediary with HTTP status code %d %q", statusCode, statusText)
-		return twirpErrorFromIntermediary(statusCode, msg, string(respBodyBytes))
-	}
-
-	errorCode := twirp.ErrorCode(tj.Code)
-	if !twirp.IsValidErrorCode(errorCode) {
-		msg := "invalid type returned from server error response: " + tj.Code
-		return twirp.InternalError(msg).WithMeta("body", string(respBodyBytes))
-	}
-
-	twerr := twirp.NewError(errorCode, tj.Msg)
-	for k, v := range tj.Meta {
-		twerr = twerr.WithMeta(k, v)
-	}
-	return twerr
-}
-
-// twirpErrorFromIntermediary maps HTTP errors from non-twirp sources to twirp errors.
-// The mapping is similar to gRPC: https://github.com/grpc/grpc/blob/master/doc/http-grpc-status-mapping.md.
-// Returned twirp Errors have some additional metadata for inspection.
-func twirpErrorFromIntermediary(status int, msg string, bodyOrLocation string) twirp.Error {
-	var code twirp.ErrorCode
-	if isHTTPRedirect(status) { // 3xx
-		code = twirp.Internal
-	} else {
-		switch status {
-		case 400: // Bad Request
-			code = twirp.Internal
-		case 401: // Unauthorized
-			code = twirp.Unauthenticated
-		case 403: // Forbidden
-			code = twirp.PermissionDenied
-		case 404: // Not Found
-			code = twirp.BadRoute
-		case 429: // Too Many Requests
-			code = twirp.ResourceExhausted
-		case 502, 503, 504: // Bad Gateway, Service Unavailable, Gateway Timeout
-			code = twirp.Unavailable
-		default: // All other codes
-			code = twirp.Unknown
-		}
-	}
-
-	twerr := twirp.NewError(code, msg)
-	twerr = twerr.WithMeta("http_error_from_intermediary", "true") // to easily know if this error was from intermediary
-	twerr = twerr.WithMeta("status_code", strconv.Itoa(status))
-	if isHTTPRedirect(status) {
-		twerr = twerr.WithMeta("location", bodyOrLocation)
-	} else {
-		twerr = twerr.WithMeta("body", bodyOrLocation)
-	}
-	return twerr
-}
-
-func isHTTPRedirect(status int) bool {
-	return status >= 300 && status <= 399
-}
-
-// wrapInternal wraps an error with a prefix as an Internal error.
-// The original error cause is accessible by github.com/pkg/errors.Cause.
-func wrapInternal(err error, prefix string) twirp.Error {
-	return twirp.InternalErrorWith(&wrappedError{prefix: prefix, cause: err})
-}
-
-type wrappedError struct {
-	prefix string
-	cause  error
-}
-
-func (e *wrappedError) Error() string { return e.prefix + ": " + e.cause.Error() }
-func (e *wrappedError) Unwrap() error { return e.cause } // for go1.13 + errors.Is/As
-func (e *wrappedError) Cause() error  { return e.cause } // for github.com/pkg/errors
-
-// ensurePanicResponses makes sure that rpc methods causing a panic still result in a Twirp Internal
-// error response (status 500), and error hooks are properly called with the panic wrapped as an error.
-// The panic is re-raised so it can be handled normally with middleware.
-func ensurePanicResponses(ctx context.Context, resp http.ResponseWriter, hooks *twirp.ServerHooks) {
-	if r := recover(); r != nil {
-		// Wrap the panic as an error so it can be passed to error hooks.
-		// The original error is accessible from error hooks, but not visible in the response.
-		err := errFromPanic(r)
-		twerr := &internalWithCause{msg: "Internal service panic", cause: err}
-		// Actually write the error
-		writeError(ctx, resp, twerr, hooks)
-		// If possible, flush the error to the wire.
-		f, ok := resp.(http.Flusher)
-		if ok {
-			f.Flush()
-		}
-
-		panic(r)
-	}
-}
-
-// errFromPanic returns the typed error if the recovered panic is an error, otherwise formats as error.
-func errFromPanic(p interface{}) error {
-	if err, ok := p.(error); ok {
-		return err
-	}
-	return fmt.Errorf("panic: %v", p)
-}
-
-// internalWithCause is a Twirp Internal error wrapping an original error cause,
-// but the original error message is not exposed on Msg(). The original error
-// can be checked with go1.13+ errors.Is/As, and also by (github.com/pkg/errors).Unwrap
-type internalWithCause struct {
-	msg   string
-	cause error
-}
-
-func (e *internalWithCause) Unwrap() error                               { return e.cause } // for go1.13 + errors.Is/As
-func (e *internalWithCause) Cause() error                                { return e.cause } // for github.com/pkg/errors
-func (e *internalWithCause) Error() string                               { return e.msg + ": " + e.cause.Error() }
-func (e *internalWithCause) Code() twirp.ErrorCode                       { return twirp.Internal }
-func (e *internalWithCause) Msg() string                                 { return e.msg }
-func (e *internalWithCause) Meta(key string) string                      { return "" }
-func (e *internalWithCause) MetaMap() map[string]string                  { return nil }
-func (e *internalWithCause) WithMeta(key string, val string) twirp.Error { return e }
-
-// malformedRequestError is used when the twirp server cannot unmarshal a request
-func malformedRequestError(msg string) twirp.Error {
-	return twirp.NewError(twirp.Malformed, msg)
-}
-
-// badRouteError is used when the twirp server cannot route a request
-func badRouteError(msg string, method, url string) twirp.Error {
-	err := twirp.NewError(twirp.BadRoute, msg)
-	err = err.WithMeta("twirp_invalid_route", method+" "+url)
-	return err
-}
-
-// withoutRedirects makes sure that the POST request can not be redirected.
-// The standard library will, by default, redirect requests (including POSTs) if it gets a 302 or
-// 303 response, and also 301s in go1.8. It redirects by making a second request, changing the
-// method to GET and removing the body. This produces very confusing error messages, so instead we
-// set a redirect policy that always errors. This stops Go from executing the redirect.
-//
-// We have to be a little careful in case the user-provided http.Client has its own CheckRedirect
-// policy - if so, we'll run through that policy first.
-//
-// Because this requires modifying the http.Client, we make a new copy of the client and return it.
-func withoutRedirects(in *http.Client) *http.Client {
-	copy := *in
-	copy.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		if in.CheckRedirect != nil {
-			// Run the input's redirect if it exists, in case it has side effects, but ignore any error it
-			// returns, since we want to use ErrUseLastResponse.
-			err := in.CheckRedirect(req, via)
-			_ = err // Silly, but this makes sure generated code passes errcheck -blank, which some people use.
-		}
-		return http.ErrUseLastResponse
-	}
-	return &copy
-}
-
-// doProtobufRequest makes a Protobuf request to the remote Twirp service.
-func doProtobufRequest(ctx context.Context, client HTTPClient, hooks *twirp.ClientHooks, url string, in, out proto.Message) (_ context.Context, err error) {
-	reqBodyBytes, err := proto.Marshal(in)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to marshal proto request")
-	}
-	reqBody := bytes.NewBuffer(reqBodyBytes)
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	req, err := newRequest(ctx, url, reqBody, "application/protobuf")
-	if err != nil {
-		return ctx, wrapInternal(err, "could not build request")
-	}
-	ctx, err = callClientRequestPrepared(ctx, hooks, req)
-	if err != nil {
-		return ctx, err
-	}
-
-	req = req.WithContext(ctx)
-	resp, err := client.Do(req)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to do request")
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	if resp.StatusCode != 200 {
-		return ctx, errorFromResponse(resp)
-	}
-
-	respBodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to read response body")
-	}
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	if err = proto.Unmarshal(respBodyBytes, out); err != nil {
-		return ctx, wrapInternal(err, "failed to unmarshal proto response")
-	}
-	return ctx, nil
-}
-
-// doJSONRequest makes a JSON request to the remote Twirp service.
-func doJSONRequest(ctx context.Context, client HTTPClient, hooks *twirp.ClientHooks, url string, in, out proto.Message) (_ context.Context, err error) {
-	marshaler := &protojson.MarshalOptions{UseProtoNames: true}
-	reqBytes, err := marshaler.Marshal(in)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to marshal json request")
-	}
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	req, err := newRequest(ctx, url, bytes.NewReader(reqBytes), "application/json")
-	if err != nil {
-		return ctx, wrapInternal(err, "could not build request")
-	}
-	ctx, err = callClientRequestPrepared(ctx, hooks, req)
-	if err != nil {
-		return ctx, err
-	}
-
-	req = req.WithContext(ctx)
-	resp, err := client.Do(req)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to do request")
-	}
-
-	defer func() {
-		cerr := resp.Body.Close()
-		if err == nil && cerr != nil {
-			err = wrapInternal(cerr, "failed to close response body")
-		}
-	}()
-
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	if resp.StatusCode != 200 {
-		return ctx, errorFromResponse(resp)
-	}
-
-	d := json.NewDecoder(resp.Body)
-	rawRespBody := json.RawMessage{}
-	if err := d.Decode(&rawRespBody); err != nil {
-		return ctx, wrapInternal(err, "failed to unmarshal json response")
-	}
-	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
-	if err = unmarshaler.Unmarshal(rawRespBody, out); err != nil {
-		return ctx, wrapInternal(err, "failed to unmarshal json response")
-	}
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-	return ctx, nil
-}
-
-// Call twirp.ServerHooks.RequestReceived if the hook is available
-func callRequestReceived(ctx context.Context, h *twirp.ServerHooks) (context.Context, error) {
-	if h == nil || h.RequestReceived == nil {
-		return ctx, nil
-	}
-	return h.RequestReceived(ctx)
-}
-
-// Call twirp.ServerHooks.RequestRouted if the hook is available
-func callRequestRouted(ctx context.Context, h *twirp.ServerHooks) (context.Context, error) {
-	if h == nil || h.RequestRouted == nil {
-		return ctx, nil
-	}
-	return h.RequestRouted(ctx)
-}
-
-// Call twirp.ServerHooks.ResponsePrepared if the hook is available
-func callResponsePrepared(ctx context.Context, h *twirp.ServerHooks) context.Context {
-	if h == nil || h.ResponsePrepared == nil {
-		return ctx
-	}
-	return h.ResponsePrepared(ctx)
-}
-
-// Call twirp.ServerHooks.ResponseSent if the hook is available
-func callResponseSent(ctx context.Context, h *twirp.ServerHooks) {
-	if h == nil || h.ResponseSent == nil {
-		return
-	}
-	h.ResponseSent(ctx)
-}
-
-// Call twirp.ServerHooks.Error if the hook is available
-func callError(ctx context.Context, h *twirp.ServerHooks, err twirp.Error) context.Context {
-	if h == nil || h.Error == nil {
-		return ctx
-	}
-	return h.Error(ctx, err)
-}
-
-func callClientResponseReceived(ctx context.Context, h *twirp.ClientHooks) {
-	if h == nil || h.ResponseReceived == nil {
-		return
-	}
-	h.ResponseReceived(ctx)
-}
-
-func callClientRequestPrepared(ctx context.Context, h *twirp.ClientHooks, req *http.Request) (context.Context, error) {
-	if h == nil || h.RequestPrepared == nil {
-		return ctx, nil
-	}
-	return h.RequestPrepared(ctx, req)
-}
-
-func callClientError(ctx context.Context, h *twirp.ClientHooks, err twirp.Error) {
-	if h == nil || h.Error == nil {
-		return
-	}
-	h.Error(ctx, err)
+	twirpruntime.WriteError(context.Background(), resp, err, nil)
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 285 bytes of a gzipped FileDescriptorProto
+	// 282 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xc1, 0x4a, 0xc3, 0x40,
 	0x10, 0x86, 0x5d, 0xdb, 0xa6, 0x3a, 0xd4, 0x5a, 0x36, 0xa2, 0x6b, 0x4f, 0x21, 0x17, 0x03, 0x42,
 	0x0a, 0xf5, 0xd8, 0x93, 0xad, 0x05, 0x2f, 0x69, 0x64, 0xeb, 0xc9, 0x8b, 0xc4, 0x64, 0xd0, 0x40,
@@ -1384,5 +832,5 @@ var twirpFileDescriptor0 = []byte{
 	0xe9, 0xa6, 0x33, 0xb0, 0x9a, 0x89, 0xb3, 0xb6, 0x3d, 0x39, 0x16, 0x63, 0xd6, 0x0e, 0xb4, 0xa4,
 	0x73, 0x80, 0x95, 0x10, 0xb2, 0x11, 0x9c, 0xb7, 0xf5, 0x55, 0xff, 0x3f, 0xde, 0x8d, 0xe6, 0xec,
 	0xe1, 0x74, 0x92, 0xe6, 0x06, 0x55, 0x1e, 0x6d, 0x26, 0xdb, 0xfc, 0xc9, 0xaa, 0x0e, 0x7b, 0xf5,
-	0x15, 0x00, 0x00, 0xff, 0xff, 0x89, 0x54, 0x2a, 0x1e, 0xee, 0x01, 0x00, 0x00,
+	0x35, 0x00, 0x89, 0x54, 0x2a, 0x1e, 0xee, 0x01, 0x00, 0x00,
 }
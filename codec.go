@@ -0,0 +1,37 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirp
+
+// Codec serializes request and response messages for a content type, in
+// addition to the built-in "application/protobuf" and "application/json"
+// encodings. Codecs are enabled on servers with WithServerCodecs and on
+// clients with WithClientCodec, for example to use a faster marshaler
+// (e.g. vtprotobuf's MarshalVT), to accept aliases like
+// "application/x-protobuf", or to use an alternative JSON encoder.
+//
+// The messages passed to Marshal and Unmarshal are the generated Go types of
+// the request and response messages (they implement proto.Message).
+//
+// Codecs must be safe for concurrent use.
+type Codec interface {
+	// ContentType is the media type used in the Content-Type and Accept
+	// headers, e.g. "application/x-protobuf".
+	ContentType() string
+
+	// Marshal returns the serialized message.
+	Marshal(msg interface{}) ([]byte, error)
+
+	// Unmarshal parses data into msg, which is a pointer to an empty message.
+	Unmarshal(data []byte, msg interface{}) error
+}
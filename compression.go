@@ -0,0 +1,105 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirp
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+	"sync"
+)
+
+// DefaultCompressionMinBytes is the minimum size of a request or response body
+// to be compressed, when compression is enabled on a client or server and the
+// size is not configured with WithServerCompressionMinBytes or
+// WithClientCompressionMinBytes. Smaller bodies are sent uncompressed, because
+// compressing them usually takes more time than the bytes saved over the wire.
+const DefaultCompressionMinBytes = 1024
+
+// Compressor implements an HTTP content-coding (e.g. "gzip") that can be used
+// by Twirp servers and clients to compress request and response bodies.
+// Compressors are registered with RegisterCompressor and enabled by name with
+// WithServerCompression and WithClientCompression.
+//
+// Compressors must be safe for concurrent use.
+type Compressor interface {
+	// Name is the content-coding token used in the Content-Encoding and
+	// Accept-Encoding headers, e.g. "gzip".
+	Name() string
+
+	// Compress returns a writer that compresses the bytes written into w.
+	// The writer is closed after the full body was written.
+	Compress(w io.Writer) (io.WriteCloser, error)
+
+	// Decompress returns a reader with the decompressed contents of r.
+	// The reader is closed after the full body was read.
+	Decompress(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[string]Compressor{
+		"gzip": gzipCompressor{},
+	}
+)
+
+// RegisterCompressor makes a Compressor available by name to
+// WithServerCompression and WithClientCompression. Registering a Compressor
+// with the same name as an existing one replaces it. The "gzip" compressor is
+// registered by default.
+//
+// RegisterCompressor is meant to be called from init functions, before
+// building any servers or clients that use the Compressor.
+func RegisterCompressor(c Compressor) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	compressors[strings.ToLower(c.Name())] = c
+}
+
+// LookupCompressor returns the registered Compressor with the given name.
+// Names are case-insensitive. Returns (nil, false) if there is no such Compressor.
+func LookupCompressor(name string) (Compressor, bool) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	c, ok := compressors[strings.ToLower(name)]
+	return c, ok
+}
+
+// mustLookupCompressors returns the registered Compressors with the given names,
+// panicking if any of them is not registered. It is used by options, where an
+// unknown name is a programming error that should be detected on startup.
+func mustLookupCompressors(optionName string, names []string) []Compressor {
+	found := make([]Compressor, 0, len(names))
+	for _, name := range names {
+		c, ok := LookupCompressor(name)
+		if !ok {
+			panic("twirp." + optionName + ": unknown encoding " + name + ", it must be registered with twirp.RegisterCompressor")
+		}
+		found = append(found, c)
+	}
+	return found
+}
+
+// gzipCompressor implements the "gzip" content-coding with the standard library.
+type gzipCompressor struct{}
+
+func (gzipCompressor) Name() string { return "gzip" }
+
+func (gzipCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCompressor) Decompress(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
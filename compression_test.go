@@ -0,0 +1,108 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirp
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"testing"
+)
+
+type deflateCompressor struct{}
+
+func (deflateCompressor) Name() string { return "deflate" }
+
+func (deflateCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return flate.NewWriter(w, flate.DefaultCompression)
+}
+
+func (deflateCompressor) Decompress(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(r), nil
+}
+
+func TestRegisterCompressor(t *testing.T) {
+	if _, ok := LookupCompressor("deflate"); ok {
+		t.Fatalf("deflate compressor is not expected to be registered by default")
+	}
+
+	RegisterCompressor(deflateCompressor{})
+	defer func() {
+		compressorsMu.Lock()
+		delete(compressors, "deflate")
+		compressorsMu.Unlock()
+	}()
+
+	c, ok := LookupCompressor("DEFLATE") // names are case-insensitive
+	if !ok {
+		t.Fatalf("deflate compressor expected to be registered")
+	}
+	if c.Name() != "deflate" {
+		t.Errorf("unexpected compressor name, have: %q, want: %q", c.Name(), "deflate")
+	}
+
+	opts := &ServerOptions{}
+	WithServerCompression("deflate", "gzip")(opts)
+	var found []Compressor
+	if ok := opts.ReadOpt("compressors", &found); !ok || len(found) != 2 {
+		t.Fatalf("option 'compressors' expected to have 2 compressors, ok: %v, val: %v", ok, found)
+	}
+	if found[0].Name() != "deflate" || found[1].Name() != "gzip" {
+		t.Errorf("option 'compressors' has unexpected order: %q, %q", found[0].Name(), found[1].Name())
+	}
+}
+
+func TestGzipCompressor(t *testing.T) {
+	c, ok := LookupCompressor("gzip")
+	if !ok {
+		t.Fatalf("gzip compressor expected to be registered by default")
+	}
+
+	body := bytes.Repeat([]byte("twirp "), 100)
+	var buf bytes.Buffer
+	w, err := c.Compress(&buf)
+	if err != nil {
+		t.Fatalf("Compress: %v", err)
+	}
+	if _, err = w.Write(body); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if buf.Len() >= len(body) {
+		t.Errorf("expected compressed body to be smaller than %d bytes, have %d bytes", len(body), buf.Len())
+	}
+
+	r, err := c.Decompress(&buf)
+	if err != nil {
+		t.Fatalf("Decompress: %v", err)
+	}
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !bytes.Equal(decompressed, body) {
+		t.Errorf("decompressed body does not match the original body")
+	}
+}
+
+func TestWithCompressionUnknownEncoding(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic with unknown encoding")
+		}
+	}()
+	WithClientCompression("bogus")
+}
@@ -48,6 +48,8 @@ func PackageName(ctx context.Context) (string, bool) {
 }
 
 // StatusCode retrieves the status code of the response (as string like "200").
+// In client hooks, it is only known if a response was received, so it is not
+// known if the request failed, for example because the server is unreachable.
 // If it is known returns (status, true).
 // If it is not known, it returns ("", false).
 func StatusCode(ctx context.Context) (string, bool) {
@@ -76,6 +78,9 @@ func WithHTTPRequestHeaders(ctx context.Context, h http.Header) (context.Context
 	if _, ok := h["Twirp-Version"]; ok {
 		return nil, errors.New("provided header cannot set Twirp-Version")
 	}
+	if _, ok := h["Twirp-Timeout"]; ok {
+		return nil, errors.New("provided header cannot set Twirp-Timeout, use a context deadline instead")
+	}
 
 	copied := make(http.Header, len(h))
 	for k, vv := range h {
@@ -13,7 +13,7 @@
 
 // Package ctxsetters is an implementation detail for twirp generated code, used
 // by the generated servers to set values in contexts for later access with the
-// twirp package's accessors.
+// accessors of the twirp and descriptors packages.
 //
 // Do not use ctxsetters outside of twirp's generated code.
 package ctxsetters
@@ -23,6 +23,8 @@ import (
 	"net/http"
 	"strconv"
 
+	"google.golang.org/protobuf/reflect/protoreflect"
+
 	"github.com/twitchtv/twirp/internal/contextkeys"
 )
 
@@ -30,6 +32,13 @@ func WithMethodName(ctx context.Context, name string) context.Context {
 	return context.WithValue(ctx, contextkeys.MethodNameKey, name)
 }
 
+func WithMethodDescriptor(ctx context.Context, md protoreflect.MethodDescriptor) context.Context {
+	if md == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextkeys.MethodDescriptorKey, md)
+}
+
 func WithServiceName(ctx context.Context, name string) context.Context {
 	return context.WithValue(ctx, contextkeys.ServiceNameKey, name)
 }
@@ -0,0 +1,190 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package descriptors provides the protobuf descriptors of generated Twirp
+// services and clients, for runtime reflection. Descriptors are resolved
+// through the global protobuf registry (protoregistry.GlobalFiles), where they
+// are registered by the Go packages generated with protoc-gen-go.
+//
+// Generated clients are returned as the service interface; use a type
+// assertion to describe them:
+//
+//	client := haberdasher.NewHaberdasherProtobufClient(addr, http.DefaultClient)
+//	sd, err := descriptors.ServiceDescriptor(client.(descriptors.DescribableService))
+package descriptors
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/twitchtv/twirp/internal/contextkeys"
+	"github.com/twitchtv/twirp/internal/gen/stringutils"
+)
+
+// DefaultPathPrefix is the path prefix used by Twirp routes, unless the server
+// and client are configured with a different one.
+const DefaultPathPrefix = "/twirp"
+
+// A DescribableService provides a gzipped, protobuf-encoded
+// FileDescriptorProto, and an int which indexes into the File to provide the
+// address of a ServiceDescriptorProto describing a service.
+//
+// This interface is implemented by servers (TwirpServer) and clients
+// generated by protoc-gen-twirp.
+type DescribableService interface {
+	ServiceDescriptor() ([]byte, int)
+}
+
+// Method describes a method of a Twirp service.
+type Method struct {
+	// Descriptor of the method.
+	Descriptor protoreflect.MethodDescriptor
+
+	// Input and Output are the descriptors of the request and response messages.
+	Input  protoreflect.MessageDescriptor
+	Output protoreflect.MessageDescriptor
+
+	// Route is the URL path of the method, in the form:
+	// "/<prefix>/<package>.<Service>/<Method>".
+	Route string
+}
+
+// UnpackFile reads gz as a gzipped, protobuf-encoded FileDescriptorProto. This
+// is the format used to store descriptors in protoc-gen-go and
+// protoc-gen-twirp.
+func UnpackFile(gz []byte) (*descriptorpb.FileDescriptorProto, error) {
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip reader: %w", err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to uncompress descriptor: %w", err)
+	}
+
+	fd := new(descriptorpb.FileDescriptorProto)
+	if err := proto.Unmarshal(b, fd); err != nil {
+		return nil, fmt.Errorf("malformed FileDescriptorProto: %w", err)
+	}
+	return fd, nil
+}
+
+// ServiceDescriptor returns the descriptor of a generated Twirp service,
+// resolved through protoregistry.GlobalFiles. If the file that defines the
+// service is not registered, the descriptor is built from the
+// FileDescriptorProto embedded in the generated code, with its dependencies
+// resolved through protoregistry.GlobalFiles.
+func ServiceDescriptor(svc DescribableService) (protoreflect.ServiceDescriptor, error) {
+	gz, index := svc.ServiceDescriptor()
+	fdProto, err := UnpackFile(gz)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unpack service descriptor: %w", err)
+	}
+	if index < 0 || index >= len(fdProto.GetService()) {
+		return nil, fmt.Errorf("service index %d out of bounds on file %q", index, fdProto.GetName())
+	}
+
+	name := protoreflect.FullName(fdProto.GetService()[index].GetName())
+	if pkg := fdProto.GetPackage(); pkg != "" {
+		name = protoreflect.FullName(pkg).Append(protoreflect.Name(name))
+	}
+	if d, err := protoregistry.GlobalFiles.FindDescriptorByName(name); err == nil {
+		if sd, ok := d.(protoreflect.ServiceDescriptor); ok {
+			return sd, nil
+		}
+	}
+
+	fd, err := protodesc.NewFile(fdProto, protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build descriptor for file %q: %w", fdProto.GetName(), err)
+	}
+	return fd.Services().Get(index), nil
+}
+
+// MethodDescriptor returns the descriptor of the method of a generated Twirp
+// service with the given name. The name can be the name defined in the
+// .proto file, or its CamelCased version, like in Twirp routes.
+func MethodDescriptor(svc DescribableService, method string) (protoreflect.MethodDescriptor, error) {
+	sd, err := ServiceDescriptor(svc)
+	if err != nil {
+		return nil, err
+	}
+	if md := sd.Methods().ByName(protoreflect.Name(method)); md != nil {
+		return md, nil
+	}
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		if md := methods.Get(i); stringutils.CamelCase(string(md.Name())) == method {
+			return md, nil
+		}
+	}
+	return nil, fmt.Errorf("method %q not found in service %q", method, sd.FullName())
+}
+
+// MethodDescriptorFromContext returns the descriptor of the method being handled
+// or called in the given context, set by generated Twirp servers and clients
+// before calling interceptors and hooks. It can be used to read custom options of
+// the method:
+//
+//	md, ok := descriptors.MethodDescriptorFromContext(ctx)
+//	if ok && proto.HasExtension(md.Options(), authpb.E_Scope) { ... }
+//
+// If it is not known, it returns (nil, false).
+func MethodDescriptorFromContext(ctx context.Context) (protoreflect.MethodDescriptor, bool) {
+	md, ok := ctx.Value(contextkeys.MethodDescriptorKey).(protoreflect.MethodDescriptor)
+	return md, ok
+}
+
+// Methods returns the methods of a generated Twirp service, with their routes
+// under the given path prefix (e.g. DefaultPathPrefix).
+func Methods(svc DescribableService, pathPrefix string) ([]Method, error) {
+	sd, err := ServiceDescriptor(svc)
+	if err != nil {
+		return nil, err
+	}
+	methods := make([]Method, sd.Methods().Len())
+	for i := range methods {
+		md := sd.Methods().Get(i)
+		methods[i] = Method{
+			Descriptor: md,
+			Input:      md.Input(),
+			Output:     md.Output(),
+			Route:      Route(pathPrefix, md),
+		}
+	}
+	return methods, nil
+}
+
+// Route returns the URL path of a method under the given path prefix, in the
+// form: "/<prefix>/<package>.<Service>/<Method>". Service and method names are
+// CamelCased, like in the routes used by generated clients.
+func Route(pathPrefix string, md protoreflect.MethodDescriptor) string {
+	sd := md.Parent()
+	serviceName := stringutils.CamelCase(string(sd.Name()))
+	if pkg := sd.ParentFile().Package(); pkg != "" {
+		serviceName = string(pkg) + "." + serviceName
+	}
+	return path.Join("/", pathPrefix, serviceName) + "/" + stringutils.CamelCase(string(md.Name()))
+}
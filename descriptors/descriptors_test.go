@@ -0,0 +1,154 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package descriptors_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/descriptors"
+	"github.com/twitchtv/twirp/internal/twirptest"
+	"github.com/twitchtv/twirp/internal/twirptest/snake_case_names"
+)
+
+func TestServiceDescriptor(t *testing.T) {
+	server := twirptest.NewHaberdasherServer(twirptest.NoopHatmaker())
+	client := twirptest.NewHaberdasherJSONClient("http://localhost", http.DefaultClient)
+
+	for name, svc := range map[string]descriptors.DescribableService{
+		"server": server,
+		"client": client.(descriptors.DescribableService),
+	} {
+		sd, err := descriptors.ServiceDescriptor(svc)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if have, want := sd.FullName(), "twirp.internal.twirptest.Haberdasher"; string(have) != want {
+			t.Errorf("%s: unexpected service name, have: %q, want: %q", name, have, want)
+		}
+		if have, want := sd.ParentFile().Path(), "service.proto"; have != want {
+			t.Errorf("%s: unexpected file, have: %q, want: %q", name, have, want)
+		}
+	}
+}
+
+func TestMethodDescriptor(t *testing.T) {
+	server := snake_case_names.NewHaberdasherV1Server(nil)
+
+	for _, name := range []string{"MakeHat_v1", "MakeHatV1"} {
+		md, err := descriptors.MethodDescriptor(server, name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if have, want := md.Name(), "MakeHat_v1"; string(have) != want {
+			t.Errorf("unexpected method name, have: %q, want: %q", have, want)
+		}
+	}
+
+	if _, err := descriptors.MethodDescriptor(server, "Unknown"); err == nil {
+		t.Errorf("expected an error for unknown method")
+	}
+}
+
+func TestMethods(t *testing.T) {
+	server := snake_case_names.NewHaberdasherV1Server(nil, twirp.WithServerPathPrefix("/custom"))
+
+	methods, err := descriptors.Methods(server, "/custom")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(methods) != 1 {
+		t.Fatalf("expected 1 method, have: %d", len(methods))
+	}
+	m := methods[0]
+	if have, want := m.Route, server.PathPrefix()+"MakeHatV1"; have != want {
+		t.Errorf("unexpected route, have: %q, want: %q", have, want)
+	}
+	if have, want := m.Input.FullName(), "twirp.internal.twirptest.snake_case_names.MakeHatArgs_v1.Size_v1"; string(have) != want {
+		t.Errorf("unexpected input type, have: %q, want: %q", have, want)
+	}
+	if have, want := m.Output.FullName(), "twirp.internal.twirptest.snake_case_names.MakeHatArgs_v1.Hat_v1"; string(have) != want {
+		t.Errorf("unexpected output type, have: %q, want: %q", have, want)
+	}
+
+	if have, want := descriptors.Route(descriptors.DefaultPathPrefix, m.Descriptor), "/twirp/twirp.internal.twirptest.snake_case_names.HaberdasherV1/MakeHatV1"; have != want {
+		t.Errorf("unexpected default route, have: %q, want: %q", have, want)
+	}
+}
+
+func TestMethodDescriptorFromContext(t *testing.T) {
+	if _, ok := descriptors.MethodDescriptorFromContext(context.Background()); ok {
+		t.Errorf("expected no method descriptor in a background context")
+	}
+
+	var seen []string
+	record := func(where string) func(context.Context) {
+		return func(ctx context.Context) {
+			md, ok := descriptors.MethodDescriptorFromContext(ctx)
+			if !ok {
+				t.Errorf("%s: expected a method descriptor in the context", where)
+				return
+			}
+			if have, want := md.FullName(), "twirp.internal.twirptest.snake_case_names.Haberdasher_v1.MakeHat_v1"; string(have) != want {
+				t.Errorf("%s: unexpected method, have: %q, want: %q", where, have, want)
+			}
+			seen = append(seen, where)
+		}
+	}
+	interceptor := func(record func(context.Context)) twirp.Interceptor {
+		return func(next twirp.Method) twirp.Method {
+			return func(ctx context.Context, req interface{}) (interface{}, error) {
+				record(ctx)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	hooks := &twirp.ServerHooks{
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			record("server hook")(ctx)
+			return ctx, nil
+		},
+	}
+	server := snake_case_names.NewHaberdasherV1Server(hatmaker{},
+		twirp.WithServerHooks(hooks),
+		twirp.WithServerInterceptors(interceptor(record("server interceptor"))),
+	)
+	s := httptest.NewServer(server)
+	defer s.Close()
+
+	for name, client := range map[string]snake_case_names.HaberdasherV1{
+		"protobuf": snake_case_names.NewHaberdasherV1ProtobufClient(s.URL, http.DefaultClient,
+			twirp.WithClientInterceptors(interceptor(record("client interceptor")))),
+		"json": snake_case_names.NewHaberdasherV1JSONClient(s.URL, http.DefaultClient,
+			twirp.WithClientInterceptors(interceptor(record("client interceptor")))),
+	} {
+		seen = nil
+		if _, err := client.MakeHatV1(context.Background(), &snake_case_names.MakeHatArgsV1_SizeV1{}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(seen) != 3 {
+			t.Errorf("%s: expected the method descriptor in client interceptors, server hooks and interceptors, have: %v", name, seen)
+		}
+	}
+}
+
+type hatmaker struct{}
+
+func (hatmaker) MakeHatV1(context.Context, *snake_case_names.MakeHatArgsV1_SizeV1) (*snake_case_names.MakeHatArgsV1_HatV1, error) {
+	return &snake_case_names.MakeHatArgsV1_HatV1{}, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirp
+
+import (
+	"errors"
+)
+
+// WithErrorDetails returns a copy of err with the given typed details attached,
+// after any details that were already attached to it. Details are usually
+// protobuf messages (proto.Message), like field violations or quota
+// information, that clients can read with ErrorDetails.
+//
+// Details are serialized as a list of google.protobuf.Any messages (JSON
+// format) in the "details" field of the error response, only for clients that
+// send the "Twirp-Error-Details: true" header, like generated Go clients since
+// v8.3.0. Other clients get the same error without details. WriteError never
+// writes details, because it does not know the client.
+func WithErrorDetails(err Error, details ...interface{}) Error {
+	if len(details) == 0 {
+		return err
+	}
+	prev := ErrorDetails(err)
+	all := make([]interface{}, 0, len(prev)+len(details))
+	all = append(all, prev...)
+	all = append(all, details...)
+	return withErrorDetails(err, all)
+}
+
+func withErrorDetails(err Error, details []interface{}) Error {
+	switch e := err.(type) {
+	case *twerr:
+		newErr := *e
+		newErr.details = details
+		return &newErr
+	case *wrappedErr:
+		return &wrappedErr{
+			wrapper: withErrorDetails(e.wrapper, details),
+			cause:   e.cause,
+		}
+	default: // other implementations of twirp.Error, keep them as the cause
+		return &wrappedErr{
+			wrapper: &twerr{code: e.Code(), msg: e.Msg(), meta: e.MetaMap(), details: details},
+			cause:   e,
+		}
+	}
+}
+
+// ErrorDetails returns the typed details attached to a twirp.Error (or an
+// error wrapping one) with WithErrorDetails. On errors returned by Twirp
+// clients, details are protobuf messages (proto.Message) that can be
+// inspected with a type switch, as long as the message type is linked into
+// the client binary. Details of unknown types are returned as their raw JSON
+// representation (json.RawMessage).
+//
+// For example:
+//
+//     for _, detail := range twirp.ErrorDetails(err) {
+//         switch d := detail.(type) {
+//         case *pb.FieldViolation:
+//             log.Printf("invalid field %s: %s", d.Field, d.Description)
+//         }
+//     }
+//
+func ErrorDetails(err error) []interface{} {
+	var twerr Error
+	if !errors.As(err, &twerr) {
+		return nil
+	}
+	return errorDetailsOf(twerr)
+}
+
+func errorDetailsOf(err Error) []interface{} {
+	switch e := err.(type) {
+	case *twerr:
+		return e.details
+	case *wrappedErr:
+		return errorDetailsOf(e.wrapper)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/twitchtv/twirp"
+)
+
+// fooError is a custom implementation of twirp.Error
+type fooError struct{ code twirp.ErrorCode }
+
+func (e fooError) Code() twirp.ErrorCode                { return e.code }
+func (e fooError) Msg() string                          { return "foo" }
+func (e fooError) WithMeta(key, val string) twirp.Error { return e }
+func (e fooError) Meta(key string) string               { return "" }
+func (e fooError) MetaMap() map[string]string           { return nil }
+func (e fooError) Error() string                        { return "foo error" }
+
+func TestWithErrorDetails(t *testing.T) {
+	detail1 := json.RawMessage(`{"@type":"type.googleapis.com/foo.Bar"}`)
+	detail2 := "detail2"
+
+	twerr := twirp.NewError(twirp.InvalidArgument, "bad request")
+	twerr = twirp.WithErrorDetails(twerr, detail1)
+	twerr = twerr.WithMeta("foo", "bar") // keeps details
+	twerr = twirp.WithErrorDetails(twerr, detail2)
+	if have, want := twirp.ErrorDetails(twerr), []interface{}{detail1, detail2}; !reflect.DeepEqual(have, want) {
+		t.Errorf("unexpected details, have: %v, want: %v", have, want)
+	}
+	assertTwirpError(t, twerr, twirp.InvalidArgument, "bad request")
+	assertTwirpErrorMeta(t, twerr, "foo", "bar")
+
+	// Wrapped errors
+	cause := errors.New("cause")
+	wrapped := twirp.WithErrorDetails(twirp.WrapError(twirp.NewError(twirp.Internal, "wrapped"), cause), detail2)
+	if have, want := twirp.ErrorDetails(fmt.Errorf("wrapped again: %w", wrapped)), []interface{}{detail2}; !reflect.DeepEqual(have, want) {
+		t.Errorf("unexpected details, have: %v, want: %v", have, want)
+	}
+	if !errors.Is(wrapped, cause) {
+		t.Errorf("expected the cause to be preserved")
+	}
+
+	// Other twirp.Error implementations
+	custom := fooError{code: twirp.NotFound}
+	withDetails := twirp.WithErrorDetails(custom, detail1)
+	if have, want := twirp.ErrorDetails(withDetails), []interface{}{detail1}; !reflect.DeepEqual(have, want) {
+		t.Errorf("unexpected details, have: %v, want: %v", have, want)
+	}
+	assertTwirpError(t, withDetails, twirp.NotFound, "foo")
+	var fooErr fooError
+	if !errors.As(withDetails, &fooErr) {
+		t.Errorf("expected the original error to be preserved")
+	}
+
+	if details := twirp.ErrorDetails(errors.New("not a twirp error")); details != nil {
+		t.Errorf("expected no details, have: %v", details)
+	}
+}
+
+func TestWriteError_WithoutDetails(t *testing.T) {
+	detail := json.RawMessage(`{"@type":"type.googleapis.com/foo.Bar","baz":1}`)
+	twerr := twirp.WithErrorDetails(twirp.NewError(twirp.InvalidArgument, "bad request"), detail)
+
+	resp := httptest.NewRecorder()
+	if err := twirp.WriteError(resp, twerr); err != nil {
+		t.Fatalf("got an error from WriteError when not expecting one: %s", err)
+	}
+
+	var gotTwerrJSON twerrJSON
+	if err := json.NewDecoder(resp.Body).Decode(&gotTwerrJSON); err != nil {
+		t.Fatalf("got an error decoding response body: %s", err)
+	}
+	if twirp.ErrorCode(gotTwerrJSON.Code) != twirp.InvalidArgument {
+		t.Errorf("got wrong error code. have=%s, want=%s", gotTwerrJSON.Code, twirp.InvalidArgument)
+	}
+	// WriteError does not know if the client can read details
+	if len(gotTwerrJSON.Details) != 0 {
+		t.Errorf("unexpected details: %s", gotTwerrJSON.Details)
+	}
+}
@@ -316,9 +316,10 @@ func IsValidErrorCode(code ErrorCode) bool {
 
 // twirp.Error implementation
 type twerr struct {
-	code ErrorCode
-	msg  string
-	meta map[string]string
+	code    ErrorCode
+	msg     string
+	meta    map[string]string
+	details []interface{}
 }
 
 func (e *twerr) Code() ErrorCode { return e.code }
@@ -333,9 +334,10 @@ func (e *twerr) Meta(key string) string {
 
 func (e *twerr) WithMeta(key string, value string) Error {
 	newErr := &twerr{
-		code: e.code,
-		msg:  e.msg,
-		meta: make(map[string]string, len(e.meta)),
+		code:    e.code,
+		msg:     e.msg,
+		meta:    make(map[string]string, len(e.meta)),
+		details: e.details,
 	}
 	for k, v := range e.meta {
 		newErr.meta[k] = v
@@ -167,9 +167,10 @@ func (errorResponseWriter) Write([]byte) (int, error) {
 }
 
 type twerrJSON struct {
-	Code string            `json:"code"`
-	Msg  string            `json:"msg"`
-	Meta map[string]string `json:"meta,omitempty"`
+	Code    string            `json:"code"`
+	Msg     string            `json:"msg"`
+	Meta    map[string]string `json:"meta,omitempty"`
+	Details []json.RawMessage `json:"details,omitempty"`
 }
 
 func TestWriteError(t *testing.T) {
@@ -0,0 +1,153 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/twitchtv/twirp"
+)
+
+// NewInterceptor returns an interceptor that adds the request and response sizes
+// to the records of NewServerHooks and NewClientHooks, and the messages with
+// WithPayloads. Sizes are the length of the protobuf encoding of messages, for
+// both Protobuf and JSON requests. Use it with the hooks on the same server or
+// client; other options than WithPayloads are ignored.
+func NewInterceptor(opts ...Option) twirp.Interceptor {
+	c := newConfig(opts)
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			// Servers run hooks before the interceptor, clients after
+			call, ok := getRPCCall(ctx)
+			if !ok {
+				ctx, call = withRPCCall(ctx)
+				call.intercepted = true
+			}
+			if msg, ok := req.(proto.Message); ok {
+				call.reqSize = proto.Size(msg)
+				if c.payloads {
+					call.req = formatPayload(msg)
+				}
+			}
+
+			resp, err := next(ctx, req)
+			if msg, ok := resp.(proto.Message); ok && err == nil {
+				call.respSize = proto.Size(msg)
+				if c.payloads {
+					call.resp = formatPayload(msg)
+				}
+			}
+
+			call.interceptorDone = true // streams of clients are still open
+			if call.write != nil {
+				call.write(ctx, call)
+			}
+			return resp, err
+		}
+	}
+}
+
+func formatPayload(msg proto.Message) string {
+	if !msg.ProtoReflect().IsValid() {
+		return "" // nil message
+	}
+	b, err := protojson.Marshal(redact(msg, isRedacted))
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// redact returns a copy of msg without the fields, including in nested messages,
+// for which isRedacted returns true.
+func redact(msg proto.Message, isRedacted func(protoreflect.FieldDescriptor) bool) proto.Message {
+	msg = proto.Clone(msg)
+	redactMessage(msg.ProtoReflect(), isRedacted)
+	return msg
+}
+
+func redactMessage(m protoreflect.Message, isRedacted func(protoreflect.FieldDescriptor) bool) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isRedacted(fd):
+			m.Clear(fd)
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					redactMessage(v.Message(), isRedacted)
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len(); i++ {
+					redactMessage(v.List().Get(i).Message(), isRedacted)
+				}
+			}
+		case fd.Message() != nil:
+			redactMessage(v.Message(), isRedacted)
+		}
+		return true
+	})
+}
+
+// debugRedactFieldNumber is the number of the debug_redact field of
+// google.protobuf.FieldOptions.
+const debugRedactFieldNumber = 16
+
+// isRedacted reports whether the field has the option [debug_redact = true].
+func isRedacted(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(proto.Message)
+	if !ok {
+		return false
+	}
+	m := opts.ProtoReflect()
+	if f := m.Descriptor().Fields().ByNumber(debugRedactFieldNumber); f != nil {
+		return m.Get(f).Bool()
+	}
+
+	// With a descriptor.proto older than debug_redact, the option is an unknown field
+	redacted := false
+	b := m.GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+		if num == debugRedactFieldNumber && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return false
+			}
+			redacted = v != 0
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+	}
+	return redacted
+}
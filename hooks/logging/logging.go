@@ -0,0 +1,329 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+// Package logging provides Twirp hooks that write one structured access log
+// record per RPC with log/slog.
+//
+// Records have the service ("<package>.<Service>"), method, Twirp error code
+// ("ok" if the request succeeded), HTTP status (unless a client request got no
+// response), duration, and the error message of failed requests. Extra attributes are available with:
+//
+//   - NewHandler: the peer address and headers of server requests (see WithHeaders).
+//   - NewInterceptor: the request and response sizes and, with WithPayloads,
+//     the request and response messages.
+//
+// Server example:
+//
+//	server := haberdasher.NewHaberdasherServer(svc,
+//		twirp.WithServerHooks(logging.NewServerHooks(slog.Default())),
+//		twirp.WithServerInterceptors(logging.NewInterceptor()),
+//	)
+//	http.ListenAndServe(":8080", logging.NewHandler(server))
+//
+// Client example:
+//
+//	client := haberdasher.NewHaberdasherProtobufClient(url, http.DefaultClient,
+//		twirp.WithClientHooks(logging.NewClientHooks(slog.Default())),
+//		twirp.WithClientInterceptors(logging.NewInterceptor()),
+//	)
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/twitchtv/twirp"
+)
+
+// CodeOK is the value of the code attribute for requests that succeeded.
+const CodeOK = "ok"
+
+// Option configures the hooks and interceptor of this package.
+type Option func(*config)
+
+type config struct {
+	levels     map[twirp.ErrorCode]slog.Level
+	sampleRate float64
+	headers    []string
+	payloads   bool
+	random     func() float64 // for sampling
+}
+
+// WithLevels sets the level of records by error code, with twirp.NoError for
+// requests that succeeded. Codes that are not in the map keep their default
+// level: Info for requests that succeeded, Error for errors with a 5xx HTTP
+// status (like internal or unavailable), and Warn for other errors.
+func WithLevels(levels map[twirp.ErrorCode]slog.Level) Option {
+	return func(c *config) {
+		c.levels = levels
+	}
+}
+
+// WithSampleRate logs only a fraction of records with a level below Warn, so
+// that failed requests are always logged, but only rate (between 0 and 1) of
+// successful requests. Default is 1, all records are logged.
+func WithSampleRate(rate float64) Option {
+	return func(c *config) {
+		c.sampleRate = rate
+	}
+}
+
+// WithHeaders adds the values of the named headers to records, in a "headers"
+// group. Client records have the request headers, and server records have the
+// request headers if the server is wrapped with NewHandler.
+// Avoid sensitive headers like Authorization.
+func WithHeaders(names ...string) Option {
+	return func(c *config) {
+		c.headers = names
+	}
+}
+
+// WithPayloads makes NewInterceptor add the request and response messages to
+// records, as JSON. Fields with the option [debug_redact = true] are omitted.
+func WithPayloads(enabled bool) Option {
+	return func(c *config) {
+		c.payloads = enabled
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		sampleRate: 1,
+		random:     rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// level returns the level of a record for the error code.
+func (c *config) level(code twirp.ErrorCode) slog.Level {
+	if level, ok := c.levels[code]; ok {
+		return level
+	}
+	switch {
+	case code == twirp.NoError:
+		return slog.LevelInfo
+	case twirp.ServerHTTPStatusFromErrorCode(code) >= 500:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// rpcCall holds the attributes of a record, stored in the context by hooks and
+// the interceptor.
+type rpcCall struct {
+	start    time.Time
+	peer     string
+	header   http.Header
+	status   int // 0 if no response was received
+	twerr    twirp.Error
+	reqSize  int // -1 if unknown
+	respSize int // -1 if unknown
+	req      string
+	resp     string
+
+	// The interceptor of clients runs after the hooks, so it writes the record
+	// with the response size and message. Streams end after the interceptor
+	// returned, then the hooks write the record.
+	intercepted     bool
+	interceptorDone bool
+	write           func(context.Context, *rpcCall)
+}
+
+var rpcCallKey = new(int)
+
+func getRPCCall(ctx context.Context) (*rpcCall, bool) {
+	call, ok := ctx.Value(rpcCallKey).(*rpcCall)
+	return call, ok
+}
+
+func withRPCCall(ctx context.Context) (context.Context, *rpcCall) {
+	call := &rpcCall{reqSize: -1, respSize: -1}
+	return context.WithValue(ctx, rpcCallKey, call), call
+}
+
+// peerInfo is stored in the context by NewHandler.
+type peerInfo struct {
+	addr   string
+	header http.Header
+}
+
+var peerInfoKey = new(int)
+
+// NewHandler wraps a Twirp server (or any http.Handler) to make the peer address
+// and request headers available to the hooks of NewServerHooks.
+func NewHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), peerInfoKey, &peerInfo{addr: r.RemoteAddr, header: r.Header})
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// NewServerHooks returns server hooks that write a record to logger when a
+// response is sent.
+func NewServerHooks(logger *slog.Logger, opts ...Option) *twirp.ServerHooks {
+	c := newConfig(opts)
+	return &twirp.ServerHooks{
+		RequestReceived: func(ctx context.Context) (context.Context, error) {
+			ctx, call := withRPCCall(ctx)
+			call.start = time.Now()
+			if peer, ok := ctx.Value(peerInfoKey).(*peerInfo); ok {
+				call.peer = peer.addr
+				call.header = peer.header
+			}
+			return ctx, nil
+		},
+		Error: func(ctx context.Context, twerr twirp.Error) context.Context {
+			if call, ok := getRPCCall(ctx); ok {
+				call.twerr = twerr
+			}
+			return ctx
+		},
+		ResponseSent: func(ctx context.Context) {
+			call, ok := getRPCCall(ctx)
+			if !ok {
+				return
+			}
+			call.setStatus(ctx)
+			c.write(ctx, logger, "twirp server request", call)
+		},
+	}
+}
+
+// NewClientHooks returns client hooks that write a record to logger when a
+// response is received, or when the request fails. The peer address is the
+// host of the request URL.
+func NewClientHooks(logger *slog.Logger, opts ...Option) *twirp.ClientHooks {
+	c := newConfig(opts)
+	write := func(ctx context.Context, call *rpcCall) {
+		c.write(ctx, logger, "twirp client request", call)
+	}
+	done := func(ctx context.Context, call *rpcCall) {
+		if call.intercepted && !call.interceptorDone {
+			call.write = write // called by the interceptor
+		} else {
+			write(ctx, call)
+		}
+	}
+
+	return &twirp.ClientHooks{
+		RequestPrepared: func(ctx context.Context, req *http.Request) (context.Context, error) {
+			call, ok := getRPCCall(ctx)
+			if !ok {
+				ctx, call = withRPCCall(ctx)
+			}
+			call.start = time.Now()
+			call.peer = req.URL.Host
+			call.header = req.Header
+			return ctx, nil
+		},
+		ResponseReceived: func(ctx context.Context) {
+			if call, ok := getRPCCall(ctx); ok {
+				call.setStatus(ctx)
+				done(ctx, call)
+			}
+		},
+		Error: func(ctx context.Context, twerr twirp.Error) {
+			call, ok := getRPCCall(ctx)
+			if !ok || call.start.IsZero() {
+				return // failed before the request was prepared
+			}
+			call.twerr = twerr
+			call.setStatus(ctx) // unknown if no response was received
+			done(ctx, call)
+		},
+	}
+}
+
+// setStatus sets the HTTP status of the response from twirp.StatusCode, if any.
+func (call *rpcCall) setStatus(ctx context.Context) {
+	if status, ok := twirp.StatusCode(ctx); ok {
+		call.status, _ = strconv.Atoi(status)
+	}
+}
+
+// write writes the record of a call, unless it is sampled out.
+func (c *config) write(ctx context.Context, logger *slog.Logger, msg string, call *rpcCall) {
+	code := twirp.NoError
+	if call.twerr != nil {
+		code = call.twerr.Code()
+	}
+	level := c.level(code)
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	if level < slog.LevelWarn && c.sampleRate < 1 && c.random() >= c.sampleRate {
+		return
+	}
+
+	pkg, _ := twirp.PackageName(ctx)
+	service, _ := twirp.ServiceName(ctx)
+	method, _ := twirp.MethodName(ctx)
+	if pkg != "" {
+		service = pkg + "." + service
+	}
+	codeValue := string(code)
+	if code == twirp.NoError {
+		codeValue = CodeOK
+	}
+
+	attrs := []slog.Attr{
+		slog.String("service", service),
+		slog.String("method", method),
+		slog.String("code", codeValue),
+	}
+	if call.status != 0 {
+		attrs = append(attrs, slog.Int("http_status", call.status))
+	}
+	attrs = append(attrs, slog.Duration("duration", time.Since(call.start)))
+	if call.twerr != nil {
+		attrs = append(attrs, slog.String("error", call.twerr.Msg()))
+	}
+	if call.peer != "" {
+		attrs = append(attrs, slog.String("peer", call.peer))
+	}
+	if call.reqSize >= 0 {
+		attrs = append(attrs, slog.Int("request_size", call.reqSize))
+	}
+	if call.respSize >= 0 {
+		attrs = append(attrs, slog.Int("response_size", call.respSize))
+	}
+	if len(c.headers) > 0 && call.header != nil {
+		var headers []any
+		for _, name := range c.headers {
+			if value := call.header.Get(name); value != "" {
+				headers = append(headers, slog.String(http.CanonicalHeaderKey(name), value))
+			}
+		}
+		if len(headers) > 0 {
+			attrs = append(attrs, slog.Group("headers", headers...))
+		}
+	}
+	if call.req != "" {
+		attrs = append(attrs, slog.String("request", call.req))
+	}
+	if call.resp != "" {
+		attrs = append(attrs, slog.String("response", call.resp))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
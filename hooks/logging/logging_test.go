@@ -0,0 +1,344 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/internal/twirptest"
+	"github.com/twitchtv/twirp/internal/twirptest/server_streaming"
+)
+
+// recordWriter receives the records of a slog.JSONHandler, which writes each
+// record with one call to Write.
+type recordWriter chan map[string]interface{}
+
+func (w recordWriter) Write(b []byte) (int, error) {
+	var record map[string]interface{}
+	if err := json.Unmarshal(b, &record); err != nil {
+		return 0, err
+	}
+	w <- record
+	return len(b), nil
+}
+
+func (w recordWriter) next(t *testing.T) map[string]interface{} {
+	t.Helper()
+	select {
+	case record := <-w:
+		return record
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for a record")
+		return nil
+	}
+}
+
+func (w recordWriter) none(t *testing.T) {
+	t.Helper()
+	select {
+	case record := <-w:
+		t.Fatalf("unexpected record %v", record)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func newLogger() (*slog.Logger, recordWriter) {
+	w := make(recordWriter, 10)
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug})), w
+}
+
+// serverAndClient logs records of a Haberdasher server and client.
+func serverAndClient(h twirptest.Haberdasher, opts ...Option) (server, client recordWriter, c twirptest.Haberdasher, close func()) {
+	serverLogger, server := newLogger()
+	clientLogger, client := newLogger()
+	s, c := twirptest.ServerAndClientWithOptions(h,
+		[]twirp.ServerOption{
+			twirp.WithServerHooks(NewServerHooks(serverLogger, opts...)),
+			twirp.WithServerInterceptors(NewInterceptor(opts...)),
+		},
+		[]twirp.ClientOption{
+			twirp.WithClientHooks(NewClientHooks(clientLogger, opts...)),
+			twirp.WithClientInterceptors(NewInterceptor(opts...)),
+		},
+		NewHandler,
+	)
+	return server, client, c, s.Close
+}
+
+func TestRecords(t *testing.T) {
+	server, client, c, close := serverAndClient(twirptest.NoopHatmaker(), WithHeaders("X-Request-Id", "Missing"))
+	defer close()
+
+	header := make(http.Header)
+	header.Set("X-Request-ID", "abc")
+	ctx, err := twirp.WithHTTPRequestHeaders(context.Background(), header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.MakeHat(ctx, &twirptest.Size{Inches: 1}); err != nil {
+		t.Fatalf("MakeHat err=%v", err)
+	}
+
+	for _, tc := range []struct {
+		w   recordWriter
+		msg string
+	}{{server, "twirp server request"}, {client, "twirp client request"}} {
+		record := tc.w.next(t)
+		want := map[string]interface{}{
+			"level":        "INFO",
+			"msg":          tc.msg,
+			"service":      "twirp.internal.twirptest.Haberdasher",
+			"method":       "MakeHat",
+			"code":         "ok",
+			"http_status":  float64(200),
+			"request_size": float64(2),
+			"headers":      map[string]interface{}{"X-Request-Id": "abc"},
+		}
+		for k, v := range want {
+			if have, _ := json.Marshal(record[k]); string(have) != mustMarshal(t, v) {
+				t.Errorf("%s: %s=%s, want %s", tc.msg, k, have, mustMarshal(t, v))
+			}
+		}
+		for _, k := range []string{"duration", "peer", "response_size"} {
+			if _, ok := record[k]; !ok {
+				t.Errorf("%s: missing %s", tc.msg, k)
+			}
+		}
+		if _, ok := record["request"]; ok {
+			t.Errorf("%s: unexpected request payload without WithPayloads", tc.msg)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestErrorLevels(t *testing.T) {
+	testcase := func(code twirp.ErrorCode, wantLevel string, opts ...Option) {
+		t.Run(string(code), func(t *testing.T) {
+			server, client, c, close := serverAndClient(twirptest.ErroringHatmaker(twirp.NewError(code, "oops")), opts...)
+			defer close()
+
+			if _, err := c.MakeHat(context.Background(), &twirptest.Size{}); err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, record := range []map[string]interface{}{server.next(t), client.next(t)} {
+				if record["level"] != wantLevel || record["code"] != string(code) || record["error"] != "oops" {
+					t.Errorf("record level=%v code=%v error=%v, want %s %s oops", record["level"], record["code"], record["error"], wantLevel, code)
+				}
+				if have, want := record["http_status"], float64(twirp.ServerHTTPStatusFromErrorCode(code)); have != want {
+					t.Errorf("http_status=%v, want %v", have, want)
+				}
+			}
+		})
+	}
+	testcase(twirp.NotFound, "WARN")
+	testcase(twirp.Internal, "ERROR")
+	testcase(twirp.Unavailable, "ERROR")
+	testcase(twirp.NotFound, "DEBUG", WithLevels(map[twirp.ErrorCode]slog.Level{twirp.NotFound: slog.LevelDebug}))
+}
+
+func TestUnreachableServer(t *testing.T) {
+	logger, client := newLogger()
+	s := httptest.NewServer(http.NotFoundHandler())
+	url := s.URL
+	s.Close() // connections are refused, no response is received
+	c := twirptest.NewHaberdasherProtobufClient(url, http.DefaultClient,
+		twirp.WithClientHooks(NewClientHooks(logger)),
+		twirp.WithClientInterceptors(NewInterceptor()),
+	)
+
+	if _, err := c.MakeHat(context.Background(), &twirptest.Size{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	record := client.next(t)
+	if record["code"] != string(twirp.Internal) {
+		t.Errorf("code=%v, want internal", record["code"])
+	}
+	if status, ok := record["http_status"]; ok {
+		t.Errorf("unexpected http_status=%v without a response", status)
+	}
+}
+
+// counter streams the numbers from 1 to CountReq.To.
+type counter struct {
+	server_streaming.UnimplementedCounterServer
+}
+
+func (counter) Count(ctx context.Context, req *server_streaming.CountReq, stream server_streaming.CounterCountServerStream) error {
+	for i := int32(1); i <= req.To; i++ {
+		if err := stream.Send(&server_streaming.Number{Value: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestStreamRecords(t *testing.T) {
+	serverLogger, server := newLogger()
+	clientLogger, client := newLogger()
+	s := httptest.NewServer(NewHandler(server_streaming.NewCounterServer(counter{},
+		twirp.WithServerHooks(NewServerHooks(serverLogger)),
+		twirp.WithServerInterceptors(NewInterceptor()),
+	)))
+	defer s.Close()
+	c := server_streaming.NewCounterProtobufClient(s.URL, http.DefaultClient,
+		twirp.WithClientHooks(NewClientHooks(clientLogger)),
+		twirp.WithClientInterceptors(NewInterceptor()),
+	)
+
+	stream, err := c.Count(context.Background(), &server_streaming.CountReq{To: 3})
+	if err != nil {
+		t.Fatalf("Count err=%v", err)
+	}
+	for stream.Next() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream err=%v", err)
+	}
+
+	for _, tc := range []struct {
+		w   recordWriter
+		msg string
+	}{{server, "twirp server request"}, {client, "twirp client request"}} {
+		record := tc.w.next(t)
+		if record["msg"] != tc.msg || record["method"] != "Count" || record["code"] != "ok" {
+			t.Errorf("record msg=%v method=%v code=%v, want %s Count ok", record["msg"], record["method"], record["code"], tc.msg)
+		}
+		if have := record["request_size"]; have != float64(2) {
+			t.Errorf("%s: request_size=%v, want 2", tc.msg, have)
+		}
+		tc.w.none(t)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	server, client, c, close := serverAndClient(twirptest.NoopHatmaker(), WithSampleRate(0))
+	defer close()
+
+	if _, err := c.MakeHat(context.Background(), &twirptest.Size{}); err != nil {
+		t.Fatalf("MakeHat err=%v", err)
+	}
+	server.none(t)
+	client.none(t)
+
+	// Errors are always logged
+	server, client, c, close = serverAndClient(twirptest.ErroringHatmaker(twirp.InternalError("oops")), WithSampleRate(0))
+	defer close()
+	if _, err := c.MakeHat(context.Background(), &twirptest.Size{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	server.next(t)
+	client.next(t)
+}
+
+func TestPayloads(t *testing.T) {
+	h := twirptest.HaberdasherFunc(func(ctx context.Context, s *twirptest.Size) (*twirptest.Hat, error) {
+		return &twirptest.Hat{Size: s.Inches, Color: "blue"}, nil
+	})
+	server, client, c, close := serverAndClient(h, WithPayloads(true))
+	defer close()
+
+	if _, err := c.MakeHat(context.Background(), &twirptest.Size{Inches: 3}); err != nil {
+		t.Fatalf("MakeHat err=%v", err)
+	}
+	for _, record := range []map[string]interface{}{server.next(t), client.next(t)} {
+		// protojson output is unstable, compare decoded values (sorted keys)
+		for k, want := range map[string]string{"request": `{"inches":3}`, "response": `{"color":"blue","size":3}`} {
+			payload, _ := record[k].(string)
+			var have interface{}
+			if err := json.Unmarshal([]byte(payload), &have); err != nil {
+				t.Fatalf("%s=%q is not JSON: %v", k, payload, err)
+			}
+			if mustMarshal(t, have) != want {
+				t.Errorf("%s=%s, want %s", k, payload, want)
+			}
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	hat := &twirptest.Hat{Size: 3, Color: "blue", Name: "cap"}
+	redacted := redact(hat, func(fd protoreflect.FieldDescriptor) bool {
+		return fd.Name() == "color"
+	})
+	if want := (&twirptest.Hat{Size: 3, Name: "cap"}); !proto.Equal(redacted, want) {
+		t.Errorf("redacted=%v, want %v", redacted, want)
+	}
+	if hat.Color != "blue" {
+		t.Error("the original message should not be modified")
+	}
+}
+
+func TestIsRedacted(t *testing.T) {
+	// descriptor.proto of the vendored protobuf version has no debug_redact field,
+	// so the option is set as an unknown field.
+	redactOpts := &descriptorpb.FieldOptions{}
+	b := protowire.AppendTag(nil, debugRedactFieldNumber, protowire.VarintType)
+	redactOpts.ProtoReflect().SetUnknown(protowire.AppendVarint(b, 1))
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:   proto.String("redact.proto"),
+		Syntax: proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Login"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("user"),
+				JsonName: proto.String("user"),
+				Number:   proto.Int32(1),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			}, {
+				Name:     proto.String("password"),
+				JsonName: proto.String("password"),
+				Number:   proto.Int32(2),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Options:  redactOpts,
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewFile err=%v", err)
+	}
+
+	fields := file.Messages().ByName("Login").Fields()
+	if isRedacted(fields.ByName("user")) {
+		t.Error("user should not be redacted")
+	}
+	if !isRedacted(fields.ByName("password")) {
+		t.Error("password should be redacted")
+	}
+}
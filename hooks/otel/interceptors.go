@@ -0,0 +1,76 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+package otel
+
+import (
+	"context"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+)
+
+// NewServerInterceptor returns a server interceptor that records the size of request
+// and response messages, in the rpc.server.request.size and rpc.server.response.size
+// metrics. Sizes are the length of the protobuf encoding, for both Protobuf and JSON
+// requests.
+func NewServerInterceptor(opts ...Option) twirp.Interceptor {
+	c := newConfig(opts)
+	return newSizeInterceptor(c.meterProvider, "rpc.server")
+}
+
+// NewClientInterceptor returns a client interceptor that records the size of request
+// and response messages, in the rpc.client.request.size and rpc.client.response.size
+// metrics. See NewServerInterceptor.
+func NewClientInterceptor(opts ...Option) twirp.Interceptor {
+	c := newConfig(opts)
+	return newSizeInterceptor(c.meterProvider, "rpc.client")
+}
+
+func newSizeInterceptor(provider metric.MeterProvider, prefix string) twirp.Interceptor {
+	meter := provider.Meter(instrumentationName)
+	requestSize, err := meter.Int64Histogram(prefix+".request.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Measures the size of RPC request messages (uncompressed)."),
+	)
+	if err != nil {
+		otelapi.Handle(err)
+	}
+	responseSize, err := meter.Int64Histogram(prefix+".response.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Measures the size of RPC response messages (uncompressed)."),
+	)
+	if err != nil {
+		otelapi.Handle(err)
+	}
+
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			attrs := metric.WithAttributes(rpcAttributes(ctx)...)
+			if msg, ok := req.(proto.Message); ok {
+				requestSize.Record(ctx, int64(proto.Size(msg)), attrs)
+			}
+			resp, err := next(ctx, req)
+			if msg, ok := resp.(proto.Message); ok && err == nil {
+				responseSize.Record(ctx, int64(proto.Size(msg)), attrs)
+			}
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+// Package otel provides Twirp hooks and interceptors for OpenTelemetry tracing
+// and metrics.
+//
+// Spans are named "<package>.<Service>/<Method>", and metrics follow the
+// OpenTelemetry semantic conventions for RPC: rpc.server.duration,
+// rpc.server.request.size, rpc.server.response.size and their rpc.client.*
+// equivalents. Durations are recorded by the hooks, and message sizes by the
+// interceptors.
+//
+// Server example:
+//
+//	server := haberdasher.NewHaberdasherServer(svc,
+//		twirp.WithServerHooks(otel.NewServerHooks()),
+//		twirp.WithServerInterceptors(otel.NewServerInterceptor()),
+//	)
+//	// Continue traces from incoming W3C trace context headers
+//	http.ListenAndServe(":8080", otel.NewHandler(server))
+//
+// Client example:
+//
+//	client := haberdasher.NewHaberdasherProtobufClient(url, http.DefaultClient,
+//		twirp.WithClientHooks(otel.NewClientHooks()),
+//		twirp.WithClientInterceptors(otel.NewClientInterceptor()),
+//	)
+package otel
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/twitchtv/twirp"
+)
+
+// instrumentationName identifies this package as the instrumentation library
+// of tracers and meters.
+const instrumentationName = "github.com/twitchtv/twirp/hooks/otel"
+
+// Attribute keys, from the semantic conventions for RPC and HTTP.
+const (
+	rpcSystemKey      = attribute.Key("rpc.system")
+	rpcServiceKey     = attribute.Key("rpc.service")
+	rpcMethodKey      = attribute.Key("rpc.method")
+	errorCodeKey      = attribute.Key("rpc.twirp.error_code")
+	httpStatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// Option configures the hooks, interceptors and handler of this package.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider of the tracer used to create spans.
+// Default is the global provider, see go.opentelemetry.io/otel.GetTracerProvider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the provider of the meter used to record metrics.
+// Default is the global provider, see go.opentelemetry.io/otel.GetMeterProvider.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to send and receive the trace context in
+// HTTP headers. Default is W3C Trace Context and Baggage.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otelapi.GetTracerProvider(),
+		meterProvider:  otelapi.GetMeterProvider(),
+		propagator:     propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NewHandler wraps a Twirp server (or any http.Handler) to extract the trace context
+// from incoming request headers, so spans created by NewServerHooks continue the
+// trace of the client.
+func NewHandler(handler http.Handler, opts ...Option) http.Handler {
+	c := newConfig(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := c.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// rpcCall holds the span and start time of a call, stored in the context by hooks.
+type rpcCall struct {
+	span  trace.Span
+	start time.Time
+	attrs []attribute.KeyValue // rpc attributes for metrics
+	code  twirp.ErrorCode      // set by the Error hook
+}
+
+var rpcCallKey = new(int)
+
+func getRPCCall(ctx context.Context) (*rpcCall, bool) {
+	call, ok := ctx.Value(rpcCallKey).(*rpcCall)
+	return call, ok
+}
+
+// NewServerHooks returns server hooks that create a span for each request, and
+// record the rpc.server.duration metric. Use NewHandler to continue traces from
+// clients.
+func NewServerHooks(opts ...Option) *twirp.ServerHooks {
+	c := newConfig(opts)
+	tracer := c.tracerProvider.Tracer(instrumentationName)
+	duration := newDurationHistogram(c.meterProvider, "rpc.server.duration", "Measures the duration of inbound RPC.")
+
+	return &twirp.ServerHooks{
+		RequestReceived: func(ctx context.Context) (context.Context, error) {
+			// The method is unknown until the request is routed, see RequestRouted
+			attrs := rpcAttributes(ctx)
+			ctx, span := tracer.Start(ctx, spanName(ctx),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attrs...),
+			)
+			call := &rpcCall{span: span, start: time.Now(), attrs: attrs}
+			return context.WithValue(ctx, rpcCallKey, call), nil
+		},
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			if call, ok := getRPCCall(ctx); ok {
+				call.attrs = rpcAttributes(ctx)
+				call.span.SetName(spanName(ctx))
+				call.span.SetAttributes(call.attrs...)
+			}
+			return ctx, nil
+		},
+		Error: func(ctx context.Context, twerr twirp.Error) context.Context {
+			if call, ok := getRPCCall(ctx); ok {
+				call.setError(twerr)
+			}
+			return ctx
+		},
+		ResponseSent: func(ctx context.Context) {
+			call, ok := getRPCCall(ctx)
+			if !ok {
+				return
+			}
+			call.setStatusCode(ctx)
+			call.end(ctx, duration)
+		},
+	}
+}
+
+// NewClientHooks returns client hooks that create a span for each request, send
+// the trace context in the request headers, and record the rpc.client.duration metric.
+func NewClientHooks(opts ...Option) *twirp.ClientHooks {
+	c := newConfig(opts)
+	tracer := c.tracerProvider.Tracer(instrumentationName)
+	duration := newDurationHistogram(c.meterProvider, "rpc.client.duration", "Measures the duration of outbound RPC.")
+
+	return &twirp.ClientHooks{
+		RequestPrepared: func(ctx context.Context, req *http.Request) (context.Context, error) {
+			attrs := rpcAttributes(ctx)
+			ctx, span := tracer.Start(ctx, spanName(ctx),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+			call := &rpcCall{span: span, start: time.Now(), attrs: attrs}
+			return context.WithValue(ctx, rpcCallKey, call), nil
+		},
+		ResponseReceived: func(ctx context.Context) {
+			if call, ok := getRPCCall(ctx); ok {
+				call.setStatusCode(ctx)
+				call.end(ctx, duration)
+			}
+		},
+		Error: func(ctx context.Context, twerr twirp.Error) {
+			call, ok := getRPCCall(ctx)
+			if !ok {
+				return // failed before the request was prepared
+			}
+			call.setError(twerr)
+			call.setStatusCode(ctx) // unknown if no response was received
+			call.end(ctx, duration)
+		},
+	}
+}
+
+// setStatusCode sets the HTTP status code of the response from twirp.StatusCode, if any.
+func (call *rpcCall) setStatusCode(ctx context.Context) {
+	if status, ok := twirp.StatusCode(ctx); ok {
+		if code, err := strconv.Atoi(status); err == nil {
+			call.span.SetAttributes(httpStatusCodeKey.Int(code))
+		}
+	}
+}
+
+func (call *rpcCall) setError(twerr twirp.Error) {
+	call.code = twerr.Code()
+	call.span.SetAttributes(errorCodeKey.String(string(twerr.Code())))
+	call.span.SetStatus(codes.Error, twerr.Msg())
+}
+
+// end ends the span and records the duration of the call.
+func (call *rpcCall) end(ctx context.Context, duration metric.Float64Histogram) {
+	call.span.End()
+	attrs := call.attrs
+	if call.code != "" {
+		attrs = append(attrs[:len(attrs):len(attrs)], errorCodeKey.String(string(call.code)))
+	}
+	elapsed := float64(time.Since(call.start)) / float64(time.Millisecond)
+	duration.Record(ctx, elapsed, metric.WithAttributes(attrs...))
+}
+
+// spanName returns "<package>.<Service>/<Method>" from the names in the context.
+func spanName(ctx context.Context) string {
+	method, _ := twirp.MethodName(ctx)
+	return fullServiceName(ctx) + "/" + method
+}
+
+func fullServiceName(ctx context.Context) string {
+	pkg, _ := twirp.PackageName(ctx)
+	service, _ := twirp.ServiceName(ctx)
+	if pkg == "" {
+		return service
+	}
+	return pkg + "." + service
+}
+
+// rpcAttributes returns the rpc.* attributes from the names in the context.
+func rpcAttributes(ctx context.Context) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		rpcSystemKey.String("twirp"),
+		rpcServiceKey.String(fullServiceName(ctx)),
+	}
+	if method, ok := twirp.MethodName(ctx); ok {
+		attrs = append(attrs, rpcMethodKey.String(method))
+	}
+	return attrs
+}
+
+func newDurationHistogram(provider metric.MeterProvider, name, description string) metric.Float64Histogram {
+	histogram, err := provider.Meter(instrumentationName).Float64Histogram(name,
+		metric.WithUnit("ms"),
+		metric.WithDescription(description),
+	)
+	if err != nil {
+		otelapi.Handle(err)
+	}
+	return histogram
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/internal/twirptest"
+)
+
+const wantSpanName = "twirp.internal.twirptest.Haberdasher/MakeHat"
+
+type telemetry struct {
+	spans   *tracetest.InMemoryExporter
+	metrics *sdkmetric.ManualReader
+	opts    []Option
+}
+
+func newTelemetry() *telemetry {
+	spans := tracetest.NewInMemoryExporter()
+	metrics := sdkmetric.NewManualReader()
+	return &telemetry{
+		spans:   spans,
+		metrics: metrics,
+		opts: []Option{
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))),
+			WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metrics))),
+		},
+	}
+}
+
+// serverAndClient instruments a Haberdasher server and client with separate telemetry.
+func serverAndClient(h twirptest.Haberdasher) (server, client *telemetry, c twirptest.Haberdasher, close func()) {
+	server, client = newTelemetry(), newTelemetry()
+	s, c := twirptest.ServerAndClientWithOptions(h,
+		[]twirp.ServerOption{
+			twirp.WithServerHooks(NewServerHooks(server.opts...)),
+			twirp.WithServerInterceptors(NewServerInterceptor(server.opts...)),
+		},
+		[]twirp.ClientOption{
+			twirp.WithClientHooks(NewClientHooks(client.opts...)),
+			twirp.WithClientInterceptors(NewClientInterceptor(client.opts...)),
+		},
+		func(handler http.Handler) http.Handler { return NewHandler(handler, server.opts...) },
+	)
+	return server, client, c, s.Close
+}
+
+func (tel *telemetry) span(t *testing.T) tracetest.SpanStub {
+	t.Helper()
+	spans := tel.spans.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+func (tel *telemetry) histogram(t *testing.T, name string) metricdata.HistogramDataPoint[float64] {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := tel.metrics.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect metrics: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				return data.DataPoints[0]
+			case metricdata.Histogram[int64]:
+				p := data.DataPoints[0]
+				return metricdata.HistogramDataPoint[float64]{Attributes: p.Attributes, Count: p.Count, Sum: float64(p.Sum)}
+			}
+		}
+	}
+	t.Fatalf("metric %q not found", name)
+	return metricdata.HistogramDataPoint[float64]{}
+}
+
+func attr(attrs []attribute.KeyValue, key attribute.Key) attribute.Value {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSpans(t *testing.T) {
+	server, client, c, close := serverAndClient(twirptest.NoopHatmaker())
+	defer close()
+
+	_, err := c.MakeHat(context.Background(), &twirptest.Size{Inches: 1})
+	if err != nil {
+		t.Fatalf("MakeHat err=%v", err)
+	}
+
+	serverSpan, clientSpan := server.span(t), client.span(t)
+	for _, tc := range []struct {
+		span tracetest.SpanStub
+		kind trace.SpanKind
+	}{{serverSpan, trace.SpanKindServer}, {clientSpan, trace.SpanKindClient}} {
+		if tc.span.Name != wantSpanName {
+			t.Errorf("span name=%q, want %q", tc.span.Name, wantSpanName)
+		}
+		if tc.span.SpanKind != tc.kind {
+			t.Errorf("span kind=%v, want %v", tc.span.SpanKind, tc.kind)
+		}
+		if have := attr(tc.span.Attributes, rpcSystemKey).AsString(); have != "twirp" {
+			t.Errorf("rpc.system=%q, want twirp", have)
+		}
+		if have := attr(tc.span.Attributes, rpcServiceKey).AsString(); have != "twirp.internal.twirptest.Haberdasher" {
+			t.Errorf("rpc.service=%q", have)
+		}
+		if have := attr(tc.span.Attributes, rpcMethodKey).AsString(); have != "MakeHat" {
+			t.Errorf("rpc.method=%q, want MakeHat", have)
+		}
+		if have := attr(tc.span.Attributes, httpStatusCodeKey).AsInt64(); have != 200 {
+			t.Errorf("http.response.status_code=%d, want 200", have)
+		}
+		if tc.span.Status.Code != codes.Unset {
+			t.Errorf("span status=%v, want Unset", tc.span.Status.Code)
+		}
+	}
+
+	// The trace context is propagated from client to server
+	if serverSpan.SpanContext.TraceID() != clientSpan.SpanContext.TraceID() {
+		t.Errorf("server trace id %s, want client trace id %s", serverSpan.SpanContext.TraceID(), clientSpan.SpanContext.TraceID())
+	}
+	if serverSpan.Parent.SpanID() != clientSpan.SpanContext.SpanID() {
+		t.Errorf("server span parent %s, want client span %s", serverSpan.Parent.SpanID(), clientSpan.SpanContext.SpanID())
+	}
+	if !serverSpan.Parent.IsRemote() {
+		t.Error("server span parent should be remote")
+	}
+}
+
+func TestErrorSpans(t *testing.T) {
+	twerr := twirp.NewError(twirp.PermissionDenied, "not allowed")
+	server, client, c, close := serverAndClient(twirptest.ErroringHatmaker(twerr))
+	defer close()
+
+	_, err := c.MakeHat(context.Background(), &twirptest.Size{Inches: 1})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, span := range []tracetest.SpanStub{server.span(t), client.span(t)} {
+		if have := attr(span.Attributes, errorCodeKey).AsString(); have != "permission_denied" {
+			t.Errorf("rpc.twirp.error_code=%q, want permission_denied", have)
+		}
+		if have := attr(span.Attributes, httpStatusCodeKey).AsInt64(); have != 403 {
+			t.Errorf("http.response.status_code=%d, want 403", have)
+		}
+		if span.Status.Code != codes.Error || span.Status.Description != "not allowed" {
+			t.Errorf("span status=%+v, want Error with the error message", span.Status)
+		}
+	}
+
+	for _, tc := range []struct {
+		tel  *telemetry
+		name string
+	}{{server, "rpc.server.duration"}, {client, "rpc.client.duration"}} {
+		p := tc.tel.histogram(t, tc.name)
+		if v, _ := p.Attributes.Value(errorCodeKey); v.AsString() != "permission_denied" {
+			t.Errorf("%s rpc.twirp.error_code=%q, want permission_denied", tc.name, v.AsString())
+		}
+	}
+}
+
+func TestUnreachableServerSpan(t *testing.T) {
+	client := newTelemetry()
+	s := httptest.NewServer(http.NotFoundHandler())
+	url := s.URL
+	s.Close() // connections are refused, no response is received
+	c := twirptest.NewHaberdasherProtobufClient(url, http.DefaultClient,
+		twirp.WithClientHooks(NewClientHooks(client.opts...)))
+
+	_, err := c.MakeHat(context.Background(), &twirptest.Size{Inches: 1})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	span := client.span(t)
+	if have := attr(span.Attributes, errorCodeKey).AsString(); have != "internal" {
+		t.Errorf("rpc.twirp.error_code=%q, want internal", have)
+	}
+	for _, kv := range span.Attributes {
+		if kv.Key == httpStatusCodeKey {
+			t.Errorf("unexpected http.response.status_code=%d without a response", kv.Value.AsInt64())
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	server, client, c, close := serverAndClient(twirptest.NoopHatmaker())
+	defer close()
+
+	req := &twirptest.Size{Inches: 1}
+	for i := 0; i < 2; i++ {
+		if _, err := c.MakeHat(context.Background(), req); err != nil {
+			t.Fatalf("MakeHat err=%v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		tel  *telemetry
+		name string
+		sum  float64 // -1 to skip
+	}{
+		{server, "rpc.server.duration", -1},
+		{client, "rpc.client.duration", -1},
+		{server, "rpc.server.request.size", 4}, // inches field: 2 bytes, twice
+		{client, "rpc.client.request.size", 4},
+		{server, "rpc.server.response.size", -1},
+		{client, "rpc.client.response.size", -1},
+	} {
+		p := tc.tel.histogram(t, tc.name)
+		if p.Count != 2 {
+			t.Errorf("%s count=%d, want 2", tc.name, p.Count)
+		}
+		if tc.sum >= 0 && p.Sum != tc.sum {
+			t.Errorf("%s sum=%v, want %v", tc.name, p.Sum, tc.sum)
+		}
+		if v, _ := p.Attributes.Value(rpcMethodKey); v.AsString() != "MakeHat" {
+			t.Errorf("%s rpc.method=%q, want MakeHat", tc.name, v.AsString())
+		}
+		if v, _ := p.Attributes.Value(rpcServiceKey); v.AsString() != "twirp.internal.twirptest.Haberdasher" {
+			t.Errorf("%s rpc.service=%q", tc.name, v.AsString())
+		}
+	}
+}
+
+func TestBadRouteSpan(t *testing.T) {
+	server := newTelemetry()
+	handler := twirptest.NewHaberdasherServer(twirptest.NoopHatmaker(),
+		twirp.WithServerHooks(NewServerHooks(server.opts...)))
+
+	req := httptest.NewRequest("POST", "/twirp/twirp.internal.twirptest.Haberdasher/Unknown", nil)
+	req.Header.Set("Content-Type", "application/protobuf")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	span := server.span(t)
+	if have := attr(span.Attributes, errorCodeKey).AsString(); have != "bad_route" {
+		t.Errorf("rpc.twirp.error_code=%q, want bad_route", have)
+	}
+	if have := attr(span.Attributes, httpStatusCodeKey).AsInt64(); have != 404 {
+		t.Errorf("http.response.status_code=%d, want 404", have)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+package prometheus
+
+import (
+	"context"
+	"net/http"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/twitchtv/twirp"
+)
+
+// ClientMetrics records metrics of Twirp clients.
+type ClientMetrics struct {
+	m *metrics
+}
+
+// NewClientMetrics creates client metrics and registers them on reg. It panics if
+// the metrics can't be registered, see NewServerMetrics.
+func NewClientMetrics(reg prom.Registerer, opts ...Option) *ClientMetrics {
+	return &ClientMetrics{m: newMetrics(reg, "twirp_client_", "client", opts)}
+}
+
+// NewClientHooks is a shortcut for NewClientMetrics(reg, opts...).Hooks(), for
+// clients that don't need size metrics.
+func NewClientHooks(reg prom.Registerer, opts ...Option) *twirp.ClientHooks {
+	return NewClientMetrics(reg, opts...).Hooks()
+}
+
+// Hooks returns client hooks that record the requests, duration and in-flight metrics.
+// Calls are timed from RequestPrepared to ResponseReceived or Error.
+func (c *ClientMetrics) Hooks() *twirp.ClientHooks {
+	return &twirp.ClientHooks{
+		RequestPrepared: func(ctx context.Context, _ *http.Request) (context.Context, error) {
+			ctx, call := newRPCCall(ctx)
+			c.m.startInFlight(call)
+			return ctx, nil
+		},
+		ResponseReceived: func(ctx context.Context) {
+			if call, ok := getRPCCall(ctx); ok {
+				c.m.observe(call)
+			}
+		},
+		Error: func(ctx context.Context, twerr twirp.Error) {
+			call, ok := getRPCCall(ctx)
+			if !ok {
+				// Failed before the request was prepared
+				ctx, call = newRPCCall(ctx)
+			}
+			call.code = string(twerr.Code())
+			c.m.observe(call)
+		},
+	}
+}
+
+// Interceptor returns a client interceptor that records the request and response
+// size metrics.
+func (c *ClientMetrics) Interceptor() twirp.Interceptor {
+	return c.m.interceptor()
+}
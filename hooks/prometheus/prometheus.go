@@ -0,0 +1,208 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+// Package prometheus provides Twirp hooks and interceptors that record Prometheus
+// metrics, labelled by service, method and Twirp error code.
+//
+// Server example:
+//
+//	metrics := prometheus.NewServerMetrics(promclient.DefaultRegisterer)
+//	server := haberdasher.NewHaberdasherServer(svc,
+//		twirp.WithServerHooks(metrics.Hooks()),
+//		twirp.WithServerInterceptors(metrics.Interceptor()), // optional, for size metrics
+//	)
+//
+// The server records these metrics:
+//
+//	twirp_requests_total{service,method,code}            counter
+//	twirp_request_duration_seconds{service,method,code}  histogram
+//	twirp_requests_in_flight{service,method}             gauge
+//	twirp_request_size_bytes{service,method}             histogram (interceptor)
+//	twirp_response_size_bytes{service,method}            histogram (interceptor)
+//
+// Clients record the same metrics with a twirp_client_ prefix, for example
+// twirp_client_requests_total. The service label is the fully qualified name
+// "<package>.<Service>". The code label is the Twirp error code, or "ok" if the
+// request succeeded. Sizes are the length of the protobuf encoding of messages,
+// for both Protobuf and JSON requests.
+package prometheus
+
+import (
+	"context"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+)
+
+// CodeOK is the value of the code label for requests that succeeded.
+const CodeOK = "ok"
+
+// DefaultSizeBuckets are the default buckets of size histograms, from 32 bytes to 8MB.
+var DefaultSizeBuckets = prom.ExponentialBuckets(32, 4, 10)
+
+// Option configures the metrics of this package.
+type Option func(*config)
+
+type config struct {
+	durationBuckets []float64
+	sizeBuckets     []float64
+	constLabels     prom.Labels
+}
+
+// WithDurationBuckets sets the buckets of duration histograms, in seconds.
+// Default is prometheus.DefBuckets.
+func WithDurationBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.durationBuckets = buckets
+	}
+}
+
+// WithSizeBuckets sets the buckets of size histograms, in bytes.
+// Default is DefaultSizeBuckets.
+func WithSizeBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.sizeBuckets = buckets
+	}
+}
+
+// WithConstLabels adds labels with fixed values to all metrics. This can be used
+// to tell apart metrics of multiple servers or clients in the same registry.
+func WithConstLabels(labels prom.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// metrics is the set of collectors shared by server and client metrics.
+type metrics struct {
+	requests     *prom.CounterVec
+	duration     *prom.HistogramVec
+	inFlight     *prom.GaugeVec
+	requestSize  *prom.HistogramVec
+	responseSize *prom.HistogramVec
+}
+
+// newMetrics creates and registers metrics named with the prefix, which is "twirp_"
+// for servers and "twirp_client_" for clients. It panics if registration fails, like
+// prometheus.MustRegister.
+func newMetrics(reg prom.Registerer, prefix, side string, opts []Option) *metrics {
+	c := &config{
+		durationBuckets: prom.DefBuckets,
+		sizeBuckets:     DefaultSizeBuckets,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	m := &metrics{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Name:        prefix + "requests_total",
+			Help:        "Total number of RPCs completed by the " + side + ".",
+			ConstLabels: c.constLabels,
+		}, []string{"service", "method", "code"}),
+		duration: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:        prefix + "request_duration_seconds",
+			Help:        "Duration of RPCs completed by the " + side + ", in seconds.",
+			Buckets:     c.durationBuckets,
+			ConstLabels: c.constLabels,
+		}, []string{"service", "method", "code"}),
+		inFlight: prom.NewGaugeVec(prom.GaugeOpts{
+			Name:        prefix + "requests_in_flight",
+			Help:        "Number of RPCs currently in progress on the " + side + ".",
+			ConstLabels: c.constLabels,
+		}, []string{"service", "method"}),
+		requestSize: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:        prefix + "request_size_bytes",
+			Help:        "Size of request messages of the " + side + ", in bytes.",
+			Buckets:     c.sizeBuckets,
+			ConstLabels: c.constLabels,
+		}, []string{"service", "method"}),
+		responseSize: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:        prefix + "response_size_bytes",
+			Help:        "Size of response messages of the " + side + ", in bytes.",
+			Buckets:     c.sizeBuckets,
+			ConstLabels: c.constLabels,
+		}, []string{"service", "method"}),
+	}
+	reg.MustRegister(m.requests, m.duration, m.inFlight, m.requestSize, m.responseSize)
+	return m
+}
+
+// rpcCall holds the state of a call, stored in the context by hooks.
+type rpcCall struct {
+	start    time.Time
+	service  string
+	method   string
+	code     string // set by the Error hook
+	inFlight bool   // the in-flight gauge was incremented
+}
+
+var rpcCallKey = new(int)
+
+func getRPCCall(ctx context.Context) (*rpcCall, bool) {
+	call, ok := ctx.Value(rpcCallKey).(*rpcCall)
+	return call, ok
+}
+
+func newRPCCall(ctx context.Context) (context.Context, *rpcCall) {
+	call := &rpcCall{start: time.Now(), code: CodeOK}
+	call.service, call.method = labels(ctx)
+	return context.WithValue(ctx, rpcCallKey, call), call
+}
+
+func (m *metrics) startInFlight(call *rpcCall) {
+	m.inFlight.WithLabelValues(call.service, call.method).Inc()
+	call.inFlight = true
+}
+
+// observe records a completed call.
+func (m *metrics) observe(call *rpcCall) {
+	if call.inFlight {
+		m.inFlight.WithLabelValues(call.service, call.method).Dec()
+	}
+	m.requests.WithLabelValues(call.service, call.method, call.code).Inc()
+	m.duration.WithLabelValues(call.service, call.method, call.code).Observe(time.Since(call.start).Seconds())
+}
+
+func (m *metrics) interceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			service, method := labels(ctx)
+			if msg, ok := req.(proto.Message); ok {
+				m.requestSize.WithLabelValues(service, method).Observe(float64(proto.Size(msg)))
+			}
+			resp, err := next(ctx, req)
+			if msg, ok := resp.(proto.Message); ok && err == nil {
+				m.responseSize.WithLabelValues(service, method).Observe(float64(proto.Size(msg)))
+			}
+			return resp, err
+		}
+	}
+}
+
+// labels returns the service and method labels from the names in the context.
+func labels(ctx context.Context) (service, method string) {
+	pkg, _ := twirp.PackageName(ctx)
+	service, _ = twirp.ServiceName(ctx)
+	method, _ = twirp.MethodName(ctx)
+	if pkg != "" {
+		service = pkg + "." + service
+	}
+	return service, method
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+package prometheus
+
+import (
+	"context"
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/internal/twirptest"
+)
+
+const service = "twirp.internal.twirptest.Haberdasher"
+
+// serverAndClient instruments a Haberdasher server and client, with metrics
+// registered on the same registry.
+func serverAndClient(h twirptest.Haberdasher) (*prom.Registry, twirptest.Haberdasher, func()) {
+	reg := prom.NewRegistry()
+	serverMetrics := NewServerMetrics(reg)
+	clientMetrics := NewClientMetrics(reg)
+	s, c := twirptest.ServerAndClientWithOptions(h,
+		[]twirp.ServerOption{
+			twirp.WithServerHooks(serverMetrics.Hooks()),
+			twirp.WithServerInterceptors(serverMetrics.Interceptor()),
+		},
+		[]twirp.ClientOption{
+			twirp.WithClientHooks(clientMetrics.Hooks()),
+			twirp.WithClientInterceptors(clientMetrics.Interceptor()),
+		},
+	)
+	return reg, c, s.Close
+}
+
+// findMetric returns the metric of the family with the given labels.
+func findMetric(t *testing.T, reg *prom.Registry, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather err=%v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range family.GetMetric() {
+			have := map[string]string{}
+			for _, l := range m.GetLabel() {
+				have[l.GetName()] = l.GetValue()
+			}
+			for k, v := range labels {
+				if have[k] != v {
+					continue metrics
+				}
+			}
+			return m
+		}
+	}
+	t.Fatalf("metric %s%v not found", name, labels)
+	return nil
+}
+
+func TestMetrics(t *testing.T) {
+	reg, client, close := serverAndClient(twirptest.NoopHatmaker())
+	defer close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.MakeHat(context.Background(), &twirptest.Size{Inches: 1}); err != nil {
+			t.Fatalf("MakeHat err=%v", err)
+		}
+	}
+
+	methodLabels := map[string]string{"service": service, "method": "MakeHat"}
+	labels := map[string]string{"service": service, "method": "MakeHat", "code": CodeOK}
+	for _, prefix := range []string{"twirp_", "twirp_client_"} {
+		if have := findMetric(t, reg, prefix+"requests_total", labels).GetCounter().GetValue(); have != 2 {
+			t.Errorf("%srequests_total=%v, want 2", prefix, have)
+		}
+		if have := findMetric(t, reg, prefix+"request_duration_seconds", labels).GetHistogram().GetSampleCount(); have != 2 {
+			t.Errorf("%srequest_duration_seconds count=%v, want 2", prefix, have)
+		}
+		if have := findMetric(t, reg, prefix+"requests_in_flight", methodLabels).GetGauge().GetValue(); have != 0 {
+			t.Errorf("%srequests_in_flight=%v, want 0", prefix, have)
+		}
+		requestSize := findMetric(t, reg, prefix+"request_size_bytes", methodLabels).GetHistogram()
+		if requestSize.GetSampleCount() != 2 || requestSize.GetSampleSum() != 4 { // inches field: 2 bytes
+			t.Errorf("%srequest_size_bytes count=%v sum=%v, want 2 and 4", prefix, requestSize.GetSampleCount(), requestSize.GetSampleSum())
+		}
+		if have := findMetric(t, reg, prefix+"response_size_bytes", methodLabels).GetHistogram().GetSampleCount(); have != 2 {
+			t.Errorf("%sresponse_size_bytes count=%v, want 2", prefix, have)
+		}
+	}
+}
+
+func TestErrorMetrics(t *testing.T) {
+	reg, client, close := serverAndClient(twirptest.ErroringHatmaker(twirp.NewError(twirp.PermissionDenied, "no")))
+	defer close()
+
+	if _, err := client.MakeHat(context.Background(), &twirptest.Size{Inches: 1}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	labels := map[string]string{"service": service, "method": "MakeHat", "code": "permission_denied"}
+	for _, prefix := range []string{"twirp_", "twirp_client_"} {
+		if have := findMetric(t, reg, prefix+"requests_total", labels).GetCounter().GetValue(); have != 1 {
+			t.Errorf("%srequests_total=%v, want 1", prefix, have)
+		}
+		if have := findMetric(t, reg, prefix+"request_duration_seconds", labels).GetHistogram().GetSampleCount(); have != 1 {
+			t.Errorf("%srequest_duration_seconds count=%v, want 1", prefix, have)
+		}
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	reg := prom.NewRegistry()
+	inFlight := make(chan float64, 1)
+	h := twirptest.HaberdasherFunc(func(ctx context.Context, s *twirptest.Size) (*twirptest.Hat, error) {
+		labels := map[string]string{"service": service, "method": "MakeHat"}
+		inFlight <- findMetric(t, reg, "twirp_requests_in_flight", labels).GetGauge().GetValue()
+		return &twirptest.Hat{}, nil
+	})
+	s, client := twirptest.ServerAndClient(h, NewServerHooks(reg))
+	defer s.Close()
+
+	if _, err := client.MakeHat(context.Background(), &twirptest.Size{}); err != nil {
+		t.Fatalf("MakeHat err=%v", err)
+	}
+	if have := <-inFlight; have != 1 {
+		t.Errorf("twirp_requests_in_flight during the request=%v, want 1", have)
+	}
+}
+
+func TestConstLabels(t *testing.T) {
+	reg := prom.NewRegistry()
+	NewServerHooks(reg, WithConstLabels(prom.Labels{"server": "a"}))
+	NewServerHooks(reg, WithConstLabels(prom.Labels{"server": "b"}))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic registering the same metrics twice")
+		}
+	}()
+	NewServerHooks(reg, WithConstLabels(prom.Labels{"server": "a"}))
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+package prometheus
+
+import (
+	"context"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/twitchtv/twirp"
+)
+
+// ServerMetrics records metrics of Twirp servers.
+type ServerMetrics struct {
+	m *metrics
+}
+
+// NewServerMetrics creates server metrics and registers them on reg. It panics if
+// the metrics can't be registered, for example if server metrics were already
+// registered on reg without different WithConstLabels.
+func NewServerMetrics(reg prom.Registerer, opts ...Option) *ServerMetrics {
+	return &ServerMetrics{m: newMetrics(reg, "twirp_", "server", opts)}
+}
+
+// NewServerHooks is a shortcut for NewServerMetrics(reg, opts...).Hooks(), for
+// servers that don't need size metrics.
+func NewServerHooks(reg prom.Registerer, opts ...Option) *twirp.ServerHooks {
+	return NewServerMetrics(reg, opts...).Hooks()
+}
+
+// Hooks returns server hooks that record the requests, duration and in-flight metrics.
+func (s *ServerMetrics) Hooks() *twirp.ServerHooks {
+	return &twirp.ServerHooks{
+		RequestReceived: func(ctx context.Context) (context.Context, error) {
+			ctx, _ = newRPCCall(ctx)
+			return ctx, nil
+		},
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			if call, ok := getRPCCall(ctx); ok {
+				call.service, call.method = labels(ctx)
+				s.m.startInFlight(call)
+			}
+			return ctx, nil
+		},
+		Error: func(ctx context.Context, twerr twirp.Error) context.Context {
+			if call, ok := getRPCCall(ctx); ok {
+				call.code = string(twerr.Code())
+			}
+			return ctx
+		},
+		ResponseSent: func(ctx context.Context) {
+			if call, ok := getRPCCall(ctx); ok {
+				s.m.observe(call)
+			}
+		},
+	}
+}
+
+// Interceptor returns a server interceptor that records the request and response
+// size metrics.
+func (s *ServerMetrics) Interceptor() twirp.Interceptor {
+	return s.m.interceptor()
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package statsd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/twitchtv/twirp"
+)
+
+// NewStatsdClientHooks provides a twirp.ClientHooks struct which
+// sends data to statsd. Requests are timed from RequestPrepared to
+// ResponseReceived or Error.
+func NewStatsdClientHooks(stats Statter) *twirp.ClientHooks {
+	hooks := &twirp.ClientHooks{}
+	// RequestPrepared:
+	// - inc twirp.client.total.requests
+	// - inc twirp.client.<method>.requests
+	hooks.RequestPrepared = func(ctx context.Context, _ *http.Request) (context.Context, error) {
+		ctx = markReqStart(ctx)
+		stats.Inc("twirp.client.total.requests", 1, 1.0)
+		if method, ok := twirp.MethodName(ctx); ok {
+			stats.Inc("twirp.client."+sanitize(method)+".requests", 1, 1.0)
+		}
+		return ctx, nil
+	}
+
+	// ResponseReceived and Error, same metrics as the server ResponseSent
+	// hook with a twirp.client prefix, without status codes if no response
+	// was received:
+	// - inc twirp.client.total.responses
+	// - inc twirp.client.<method>.responses
+	// - inc twirp.client.status_codes.total.<code>
+	// - inc twirp.client.status_codes.<method>.<code>
+	// - time twirp.client.all_methods.response
+	// - time twirp.client.<method>.response
+	// - time twirp.client.status_codes.all_methods.<code>
+	// - time twirp.client.status_codes.<method>.<code>
+	hooks.ResponseReceived = func(ctx context.Context) {
+		recordResponse(ctx, stats, "twirp.client.", strconv.Itoa(http.StatusOK), true)
+	}
+	hooks.Error = func(ctx context.Context, twerr twirp.Error) {
+		status, haveStatus := twirp.StatusCode(ctx)
+		recordResponse(ctx, stats, "twirp.client.", status, haveStatus)
+	}
+	return hooks
+}
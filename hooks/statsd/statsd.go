@@ -68,55 +68,51 @@ func NewStatsdServerHooks(stats Statter) *twirp.ServerHooks {
 	// - time twirp.status_codes.all_methods.<code>
 	// - time twirp.status_codes.<method>.<code>
 	hooks.ResponseSent = func(ctx context.Context) {
-		// Three pieces of data to get, none are guaranteed to be present:
-		// - time that the request started
-		// - method that was called
-		// - status code of response
-		var (
-			start  time.Time
-			method string
-			status string
+		status, haveStatus := twirp.StatusCode(ctx)
+		recordResponse(ctx, stats, "twirp.", status, haveStatus)
+	}
+	return hooks
+}
 
-			haveStart  bool
-			haveMethod bool
-			haveStatus bool
-		)
+// recordResponse records the responses and timings of a request, with metric names
+// starting with prefix. Three pieces of data are needed, none are guaranteed to be
+// present:
+// - time that the request started
+// - method that was called
+// - status code of response
+func recordResponse(ctx context.Context, stats Statter, prefix string, status string, haveStatus bool) {
+	start, haveStart := getReqStart(ctx)
+	method, haveMethod := twirp.MethodName(ctx)
 
-		start, haveStart = getReqStart(ctx)
-		method, haveMethod = twirp.MethodName(ctx)
-		status, haveStatus = twirp.StatusCode(ctx)
+	method = sanitize(method)
+	status = sanitize(status)
 
-		method = sanitize(method)
-		status = sanitize(status)
+	stats.Inc(prefix+"total.responses", 1, 1.0)
+
+	if haveMethod {
+		stats.Inc(prefix+method+".responses", 1, 1.0)
+	}
+	if haveStatus {
+		stats.Inc(prefix+"status_codes.total."+status, 1, 1.0)
+	}
+	if haveMethod && haveStatus {
+		stats.Inc(prefix+"status_codes."+method+"."+status, 1, 1.0)
+	}
 
-		stats.Inc("twirp.total.responses", 1, 1.0)
+	if haveStart {
+		dur := time.Now().Sub(start)
+		stats.TimingDuration(prefix+"all_methods.response", dur, 1.0)
 
 		if haveMethod {
-			stats.Inc("twirp."+method+".responses", 1, 1.0)
+			stats.TimingDuration(prefix+method+".response", dur, 1.0)
 		}
 		if haveStatus {
-			stats.Inc("twirp.status_codes.total."+status, 1, 1.0)
+			stats.TimingDuration(prefix+"status_codes.all_methods."+status, dur, 1.0)
 		}
 		if haveMethod && haveStatus {
-			stats.Inc("twirp.status_codes."+method+"."+status, 1, 1.0)
-		}
-
-		if haveStart {
-			dur := time.Now().Sub(start)
-			stats.TimingDuration("twirp.all_methods.response", dur, 1.0)
-
-			if haveMethod {
-				stats.TimingDuration("twirp."+method+".response", dur, 1.0)
-			}
-			if haveStatus {
-				stats.TimingDuration("twirp.status_codes.all_methods."+status, dur, 1.0)
-			}
-			if haveMethod && haveStatus {
-				stats.TimingDuration("twirp.status_codes."+method+"."+status, dur, 1.0)
-			}
+			stats.TimingDuration(prefix+"status_codes."+method+"."+status, dur, 1.0)
 		}
 	}
-	return hooks
 }
 
 func sanitize(s string) string {
@@ -15,7 +15,10 @@ package statsd
 
 import (
 	"context"
+	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -82,6 +85,149 @@ func TestTimingHooks(t *testing.T) {
 	}
 }
 
+func TestClientTimingHooks(t *testing.T) {
+	statter := &fakeStatter{}
+	server, _ := serverAndClient(nil)
+	defer server.Close()
+
+	client := twirptest.NewHaberdasherProtobufClient(server.URL, http.DefaultClient,
+		twirp.WithClientHooks(NewStatsdClientHooks(statter)))
+	_, err := client.MakeHat(context.Background(), &twirptest.Size{})
+	if err != nil {
+		t.Fatalf("twirptest Client err=%q", err)
+	}
+
+	expectedIncrements := []string{
+		"twirp.client.total.requests",
+		"twirp.client.MakeHat.requests",
+		"twirp.client.total.responses",
+		"twirp.client.MakeHat.responses",
+		"twirp.client.status_codes.total.200",
+		"twirp.client.status_codes.MakeHat.200",
+	}
+	for _, inc := range expectedIncrements {
+		if !statter.receivedInc(inc) {
+			t.Errorf("statter did not receive increment %q", inc)
+		}
+	}
+	expectedTimers := []string{
+		"twirp.client.all_methods.response",
+		"twirp.client.MakeHat.response",
+		"twirp.client.status_codes.all_methods.200",
+		"twirp.client.status_codes.MakeHat.200",
+	}
+	for _, tim := range expectedTimers {
+		if !statter.receivedTiming(tim) {
+			t.Errorf("statter did not receive timing %q", tim)
+		}
+	}
+}
+
+func TestClientErrorHooks(t *testing.T) {
+	statter := &fakeStatter{}
+	server, _ := twirptest.ServerAndClient(twirptest.ErroringHatmaker(twirp.NotFoundError("no hat")), nil)
+	defer server.Close()
+
+	client := twirptest.NewHaberdasherProtobufClient(server.URL, http.DefaultClient,
+		twirp.WithClientHooks(NewStatsdClientHooks(statter)))
+	_, err := client.MakeHat(context.Background(), &twirptest.Size{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, inc := range []string{"twirp.client.total.responses", "twirp.client.status_codes.MakeHat.404"} {
+		if !statter.receivedInc(inc) {
+			t.Errorf("statter did not receive increment %q", inc)
+		}
+	}
+	if !statter.receivedTiming("twirp.client.status_codes.MakeHat.404") {
+		t.Errorf("statter did not receive timing %q", "twirp.client.status_codes.MakeHat.404")
+	}
+}
+
+func TestTaggedHooks(t *testing.T) {
+	serverStatter := &fakeTaggedStatter{}
+	clientStatter := &fakeTaggedStatter{}
+	server, _ := serverAndClient(NewTaggedStatsdServerHooks(serverStatter))
+	defer server.Close()
+
+	client := twirptest.NewHaberdasherProtobufClient(server.URL, http.DefaultClient,
+		twirp.WithClientHooks(NewTaggedStatsdClientHooks(clientStatter)))
+	_, err := client.MakeHat(context.Background(), &twirptest.Size{})
+	if err != nil {
+		t.Fatalf("twirptest Client err=%q", err)
+	}
+
+	rpcTags := []string{"service:Haberdasher", "package:twirp.internal.twirptest", "method:MakeHat"}
+	responseTags := append(rpcTags[:3:3], "code:ok", "status:200")
+	for _, tc := range []struct {
+		statter *fakeTaggedStatter
+		prefix  string
+	}{{serverStatter, "twirp."}, {clientStatter, "twirp.client."}} {
+		if tags, ok := tc.statter.incTags[tc.prefix+"requests"]; !ok || !reflect.DeepEqual(tags, rpcTags) {
+			t.Errorf("%srequests tags=%q, want %q", tc.prefix, tags, rpcTags)
+		}
+		if tags, ok := tc.statter.incTags[tc.prefix+"responses"]; !ok || !reflect.DeepEqual(tags, responseTags) {
+			t.Errorf("%sresponses tags=%q, want %q", tc.prefix, tags, responseTags)
+		}
+		if tags, ok := tc.statter.timingTags[tc.prefix+"response"]; !ok || !reflect.DeepEqual(tags, responseTags) {
+			t.Errorf("%sresponse timing tags=%q, want %q", tc.prefix, tags, responseTags)
+		}
+	}
+}
+
+func TestTaggedHooksError(t *testing.T) {
+	serverStatter := &fakeTaggedStatter{}
+	clientStatter := &fakeTaggedStatter{}
+	server, _ := twirptest.ServerAndClient(twirptest.ErroringHatmaker(twirp.NotFoundError("no hat")),
+		NewTaggedStatsdServerHooks(serverStatter))
+	defer server.Close()
+
+	client := twirptest.NewHaberdasherProtobufClient(server.URL, http.DefaultClient,
+		twirp.WithClientHooks(NewTaggedStatsdClientHooks(clientStatter)))
+	_, err := client.MakeHat(context.Background(), &twirptest.Size{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	responseTags := []string{"service:Haberdasher", "package:twirp.internal.twirptest", "method:MakeHat", "code:not_found", "status:404"}
+	for _, tc := range []struct {
+		statter *fakeTaggedStatter
+		prefix  string
+	}{{serverStatter, "twirp."}, {clientStatter, "twirp.client."}} {
+		if tags, ok := tc.statter.incTags[tc.prefix+"responses"]; !ok || !reflect.DeepEqual(tags, responseTags) {
+			t.Errorf("%sresponses tags=%q, want %q", tc.prefix, tags, responseTags)
+		}
+	}
+}
+
+func TestClientHooksTransportError(t *testing.T) {
+	server, _ := serverAndClient(nil)
+	server.Close() // requests fail without a response
+
+	statter := &fakeStatter{}
+	taggedStatter := &fakeTaggedStatter{}
+	client := twirptest.NewHaberdasherProtobufClient(server.URL, http.DefaultClient,
+		twirp.WithClientHooks(twirp.ChainClientHooks(NewStatsdClientHooks(statter), NewTaggedStatsdClientHooks(taggedStatter))))
+	_, err := client.MakeHat(context.Background(), &twirptest.Size{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if !statter.receivedInc("twirp.client.MakeHat.responses") {
+		t.Errorf("statter did not receive increment %q", "twirp.client.MakeHat.responses")
+	}
+	for _, inc := range statter.incs {
+		if strings.Contains(inc.metric, "status_codes") {
+			t.Errorf("statter received increment %q, want no status code", inc.metric)
+		}
+	}
+	responseTags := []string{"service:Haberdasher", "package:twirp.internal.twirptest", "method:MakeHat", "code:internal"}
+	if tags, ok := taggedStatter.incTags["twirp.client.responses"]; !ok || !reflect.DeepEqual(tags, responseTags) {
+		t.Errorf("twirp.client.responses tags=%q, want %q", tags, responseTags)
+	}
+}
+
 func serverAndClient(hooks *twirp.ServerHooks) (*httptest.Server, twirptest.Haberdasher) {
 	return twirptest.ServerAndClient(twirptest.NoopHatmaker(), hooks)
 }
@@ -129,3 +275,25 @@ func (s *fakeStatter) receivedTiming(metric string) bool {
 	}
 	return false
 }
+
+// fakeTaggedStatter records the tags of the last increment and timing of each metric.
+type fakeTaggedStatter struct {
+	incTags    map[string][]string
+	timingTags map[string][]string
+}
+
+func (s *fakeTaggedStatter) IncTags(metric string, val int64, rate float32, tags []string) error {
+	if s.incTags == nil {
+		s.incTags = make(map[string][]string)
+	}
+	s.incTags[metric] = tags
+	return nil
+}
+
+func (s *fakeTaggedStatter) TimingDurationTags(metric string, val time.Duration, rate float32, tags []string) error {
+	if s.timingTags == nil {
+		s.timingTags = make(map[string][]string)
+	}
+	s.timingTags[metric] = tags
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package statsd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twitchtv/twirp"
+)
+
+// TaggedStatter is a Statter that supports tags, like DogStatsD. Tags are
+// formatted as "key:value".
+type TaggedStatter interface {
+	IncTags(metric string, val int64, rate float32, tags []string) error
+	TimingDurationTags(metric string, val time.Duration, rate float32, tags []string) error
+}
+
+// NewTaggedStatsdServerHooks provides a twirp.ServerHooks struct which sends data
+// to statsd, with the service, package, method, error code and status code as tags
+// instead of parts of metric names:
+// - inc twirp.requests, tagged with service, package and method
+// - inc twirp.responses, tagged with service, package, method, code and status
+// - time twirp.response, tagged with service, package, method, code and status
+// The code tag is the Twirp error code, or "ok" if the request succeeded, and the
+// status tag is the HTTP status code of the response. Tags are omitted if the
+// value is unknown, for example the method of a request that can't be routed.
+func NewTaggedStatsdServerHooks(stats TaggedStatter) *twirp.ServerHooks {
+	hooks := &twirp.ServerHooks{}
+	hooks.RequestReceived = func(ctx context.Context) (context.Context, error) {
+		return markReqStart(ctx), nil
+	}
+	// twirp.requests is sent once the request is routed, so it has a method tag,
+	// or with the response if it can't be routed.
+	hooks.RequestRouted = func(ctx context.Context) (context.Context, error) {
+		stats.IncTags("twirp.requests", 1, 1.0, rpcTags(ctx))
+		return ctx, nil
+	}
+	hooks.Error = func(ctx context.Context, twerr twirp.Error) context.Context {
+		return context.WithValue(ctx, errorCodeKey, twerr.Code())
+	}
+	hooks.ResponseSent = func(ctx context.Context) {
+		if _, routed := twirp.MethodName(ctx); !routed {
+			stats.IncTags("twirp.requests", 1, 1.0, rpcTags(ctx))
+		}
+		code, ok := ctx.Value(errorCodeKey).(twirp.ErrorCode)
+		if !ok {
+			code = codeOK
+		}
+		status, haveStatus := twirp.StatusCode(ctx)
+		recordTaggedResponse(ctx, stats, "twirp.", code, status, haveStatus)
+	}
+	return hooks
+}
+
+// NewTaggedStatsdClientHooks provides a twirp.ClientHooks struct which sends data
+// to statsd, with tags like NewTaggedStatsdServerHooks. Metric names start with
+// twirp.client: twirp.client.requests, twirp.client.responses and
+// twirp.client.response. The status tag is omitted if no response was received,
+// for example if the server is unreachable.
+func NewTaggedStatsdClientHooks(stats TaggedStatter) *twirp.ClientHooks {
+	hooks := &twirp.ClientHooks{}
+	hooks.RequestPrepared = func(ctx context.Context, _ *http.Request) (context.Context, error) {
+		ctx = markReqStart(ctx)
+		stats.IncTags("twirp.client.requests", 1, 1.0, rpcTags(ctx))
+		return ctx, nil
+	}
+	hooks.ResponseReceived = func(ctx context.Context) {
+		recordTaggedResponse(ctx, stats, "twirp.client.", codeOK, strconv.Itoa(http.StatusOK), true)
+	}
+	hooks.Error = func(ctx context.Context, twerr twirp.Error) {
+		status, haveStatus := twirp.StatusCode(ctx)
+		recordTaggedResponse(ctx, stats, "twirp.client.", twerr.Code(), status, haveStatus)
+	}
+	return hooks
+}
+
+// codeOK is the code tag of successful responses, which have no error code.
+const codeOK twirp.ErrorCode = "ok"
+
+// errorCodeKey is the context key for the error code of a failed request, set by
+// the server Error hook and read when the response is sent.
+var errorCodeKey = new(int)
+
+func recordTaggedResponse(ctx context.Context, stats TaggedStatter, prefix string, code twirp.ErrorCode, status string, haveStatus bool) {
+	tags := append(rpcTags(ctx), "code:"+sanitizeTag(string(code)))
+	if haveStatus {
+		tags = append(tags, "status:"+sanitizeTag(status))
+	}
+	stats.IncTags(prefix+"responses", 1, 1.0, tags)
+	if start, ok := getReqStart(ctx); ok {
+		stats.TimingDurationTags(prefix+"response", time.Now().Sub(start), 1.0, tags)
+	}
+}
+
+// rpcTags returns the service, package and method tags from the names in the
+// context.
+func rpcTags(ctx context.Context) []string {
+	tags := make([]string, 0, 5)
+	if service, ok := twirp.ServiceName(ctx); ok {
+		tags = append(tags, "service:"+sanitizeTag(service))
+	}
+	if pkg, ok := twirp.PackageName(ctx); ok && pkg != "" {
+		tags = append(tags, "package:"+sanitizeTag(pkg))
+	}
+	if method, ok := twirp.MethodName(ctx); ok {
+		tags = append(tags, "method:"+sanitizeTag(method))
+	}
+	return tags
+}
+
+// sanitizeTag replaces characters that separate tags in the DogStatsD protocol.
+func sanitizeTag(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '|', '#', ':':
+			return '_'
+		default:
+			return r
+		}
+	}, s)
+}
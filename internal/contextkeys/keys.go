@@ -25,4 +25,7 @@ const (
 	StatusCodeKey
 	RequestHeaderKey
 	ResponseWriterKey
+	MuxServerHooksKey
+	MuxInterceptorKey
+	MethodDescriptorKey
 )
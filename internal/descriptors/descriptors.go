@@ -16,38 +16,12 @@
 package descriptors
 
 import (
-	"bytes"
-	"compress/gzip"
-	"io"
-
-	"google.golang.org/protobuf/proto"
 	protobuf "google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/pkg/errors"
-)
 
-// UnpackFile reads gz as a gzipped, protobuf-encoded FileDescriptorProto. This
-// is the format used to store descriptors in protoc-gen-go and
-// protoc-gen-twirp.
-func UnpackFile(gz []byte) (*protobuf.FileDescriptorProto, error) {
-	r, err := gzip.NewReader(bytes.NewReader(gz))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to open gzip reader")
-	}
-	defer r.Close()
-
-	b, err := io.ReadAll(r)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to uncompress descriptor")
-	}
-
-	fd := new(protobuf.FileDescriptorProto)
-	if err := proto.Unmarshal(b, fd); err != nil {
-		return nil, errors.Wrap(err, "malformed FileDescriptorProto")
-	}
-
-	return fd, nil
-}
+	"github.com/twitchtv/twirp/descriptors"
+)
 
 // A DescribableMessage provides a gzipped, protobuf-encoded
 // FileDescriptorProto, and a series of ints which index into the File to
@@ -63,7 +37,7 @@ type DescribableMessage interface {
 // the FileDescriptorProto in which the message is defined.
 func MessageDescriptor(msg DescribableMessage) (*protobuf.FileDescriptorProto, *protobuf.DescriptorProto, error) {
 	gz, path := msg.Descriptor()
-	fd, err := UnpackFile(gz)
+	fd, err := descriptors.UnpackFile(gz)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "unable to unpack gzipped descriptor")
 	}
@@ -104,7 +78,7 @@ type DescribableEnum interface {
 // the FileDescriptorProto in which the enum is defined.
 func EnumDescriptor(enum DescribableEnum) (*protobuf.FileDescriptorProto, *protobuf.EnumDescriptorProto, error) {
 	gz, path := enum.EnumDescriptor()
-	fd, err := UnpackFile(gz)
+	fd, err := descriptors.UnpackFile(gz)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "unable to unpack gzipped descriptor")
 	}
@@ -157,7 +131,7 @@ type DescribableService interface {
 // value, and the FileDescriptorProto in which the service is defined.
 func ServiceDescriptor(svc DescribableService) (*protobuf.FileDescriptorProto, *protobuf.ServiceDescriptorProto, error) {
 	gz, idx := svc.ServiceDescriptor()
-	fd, err := UnpackFile(gz)
+	fd, err := descriptors.UnpackFile(gz)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "unable to unpack gzipped descriptor")
 	}
@@ -13,4 +13,4 @@
 
 package gen
 
-const Version = "v8.1.3"
+const Version = "v8.3.0"
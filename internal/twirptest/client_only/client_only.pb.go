@@ -0,0 +1,225 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0-devel
+// 	protoc        v3.21.8
+// source: client_only.proto
+
+// Test generate=client, to generate only clients
+
+package client_only
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CountReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	To int32 `protobuf:"varint,1,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *CountReq) Reset() {
+	*x = CountReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_only_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountReq) ProtoMessage() {}
+
+func (x *CountReq) ProtoReflect() protoreflect.Message {
+	mi := &file_client_only_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountReq.ProtoReflect.Descriptor instead.
+func (*CountReq) Descriptor() ([]byte, []int) {
+	return file_client_only_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CountReq) GetTo() int32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type Number struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value int32 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Number) Reset() {
+	*x = Number{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_only_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Number) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Number) ProtoMessage() {}
+
+func (x *Number) ProtoReflect() protoreflect.Message {
+	mi := &file_client_only_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Number.ProtoReflect.Descriptor instead.
+func (*Number) Descriptor() ([]byte, []int) {
+	return file_client_only_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Number) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+var File_client_only_proto protoreflect.FileDescriptor
+
+var file_client_only_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x24, 0x74, 0x77, 0x69, 0x72, 0x70, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2e, 0x74, 0x77, 0x69, 0x72, 0x70, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x22, 0x1a, 0x0a, 0x08, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x1e, 0x0a, 0x06, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x32, 0xde, 0x01, 0x0a, 0x07, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65,
+	0x72, 0x12, 0x69, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x2e, 0x74, 0x77, 0x69,
+	0x72, 0x70, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x74, 0x77, 0x69, 0x72,
+	0x70, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x6e, 0x6c,
+	0x79, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x2c, 0x2e, 0x74, 0x77, 0x69,
+	0x72, 0x70, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x74, 0x77, 0x69, 0x72,
+	0x70, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x6e, 0x6c,
+	0x79, 0x2e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x00, 0x30, 0x01, 0x12, 0x68, 0x0a, 0x03,
+	0x47, 0x65, 0x74, 0x12, 0x2e, 0x2e, 0x74, 0x77, 0x69, 0x72, 0x70, 0x2e, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x74, 0x77, 0x69, 0x72, 0x70, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x1a, 0x2c, 0x2e, 0x74, 0x77, 0x69, 0x72, 0x70, 0x2e, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x74, 0x77, 0x69, 0x72, 0x70, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x2e, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x22, 0x03, 0x90, 0x02, 0x01, 0x42, 0x0e, 0x5a, 0x0c, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_client_only_proto_rawDescOnce sync.Once
+	file_client_only_proto_rawDescData = file_client_only_proto_rawDesc
+)
+
+func file_client_only_proto_rawDescGZIP() []byte {
+	file_client_only_proto_rawDescOnce.Do(func() {
+		file_client_only_proto_rawDescData = protoimpl.X.CompressGZIP(file_client_only_proto_rawDescData)
+	})
+	return file_client_only_proto_rawDescData
+}
+
+var file_client_only_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_client_only_proto_goTypes = []interface{}{
+	(*CountReq)(nil), // 0: twirp.internal.twirptest.client_only.CountReq
+	(*Number)(nil),   // 1: twirp.internal.twirptest.client_only.Number
+}
+var file_client_only_proto_depIdxs = []int32{
+	0, // 0: twirp.internal.twirptest.client_only.Counter.Count:input_type -> twirp.internal.twirptest.client_only.CountReq
+	0, // 1: twirp.internal.twirptest.client_only.Counter.Get:input_type -> twirp.internal.twirptest.client_only.CountReq
+	1, // 2: twirp.internal.twirptest.client_only.Counter.Count:output_type -> twirp.internal.twirptest.client_only.Number
+	1, // 3: twirp.internal.twirptest.client_only.Counter.Get:output_type -> twirp.internal.twirptest.client_only.Number
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_client_only_proto_init() }
+func file_client_only_proto_init() {
+	if File_client_only_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_client_only_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_only_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Number); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_client_only_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_client_only_proto_goTypes,
+		DependencyIndexes: file_client_only_proto_depIdxs,
+		MessageInfos:      file_client_only_proto_msgTypes,
+	}.Build()
+	File_client_only_proto = out.File
+	file_client_only_proto_rawDesc = nil
+	file_client_only_proto_goTypes = nil
+	file_client_only_proto_depIdxs = nil
+}
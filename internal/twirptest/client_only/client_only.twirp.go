@@ -0,0 +1,437 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: client_only.proto
+
+// Test generate=client, to generate only clients
+
+package client_only
+
+import context "context"
+import http "net/http"
+
+import twirp "github.com/twitchtv/twirp"
+import ctxsetters "github.com/twitchtv/twirp/ctxsetters"
+import twirpruntime "github.com/twitchtv/twirp/twirpruntime"
+
+// Version compatibility assertion.
+// If the constant is not defined in the package, that likely means
+// the package needs to be updated to work with this generated code.
+// See https://twitchtv.github.io/twirp/docs/version_matrix.html
+const _ = twirp.TwirpPackageMinVersion_8_3_0
+
+// =================
+// Counter Interface
+// =================
+
+type Counter interface {
+	// Count streams the numbers from 1 to CountReq.to.
+	Count(context.Context, *CountReq, CounterCountServerStream) error
+
+	// Get is a regular unary method, that can be called with GET requests.
+	Get(context.Context, *CountReq) (*Number, error)
+}
+
+// CounterClient is the interface implemented by Counter clients. Unary methods
+// are the same as in the Counter interface, server-streaming methods return a stream
+// to read the response messages.
+type CounterClient interface {
+	// Count streams the numbers from 1 to CountReq.to.
+	Count(context.Context, *CountReq) (CounterCountClientStream, error)
+
+	// Get is a regular unary method, that can be called with GET requests.
+	Get(context.Context, *CountReq) (*Number, error)
+}
+
+// CounterCountServerStream is used by implementations of Counter.Count to send response messages.
+type CounterCountServerStream interface {
+	// Send sends a message to the client. If it returns an error (e.g. the client is gone),
+	// the method should stop and return.
+	Send(*Number) error
+}
+
+// CounterCountClientStream is returned by CounterClient.Count to read response messages:
+//
+//	for stream.Next() {
+//	  msg := stream.Msg()
+//	}
+//	if err := stream.Err(); err != nil {
+//	  // handle error
+//	}
+type CounterCountClientStream interface {
+	// Next reads the next message, available with Msg. It returns false when the
+	// stream is over, either successfully or with an error (see Err).
+	Next() bool
+
+	// Msg returns the message read by the last call to Next.
+	Msg() *Number
+
+	// Err returns the error that ended the stream, or nil if it ended successfully.
+	Err() error
+
+	// Close stops reading the stream. It must be called if the stream is not read
+	// until Next returns false, to release the connection.
+	Close() error
+}
+
+type counterCountClientStream struct {
+	*twirpruntime.ClientStream // provides Err and Close
+	msg                        *Number
+}
+
+func (s *counterCountClientStream) Next() bool {
+	msg := new(Number)
+	if !s.Recv(msg) {
+		return false
+	}
+	s.msg = msg
+	return true
+}
+
+func (s *counterCountClientStream) Msg() *Number { return s.msg }
+
+// =======================
+// Counter Protobuf Client
+// =======================
+
+type counterProtobufClient struct {
+	client            HTTPClient
+	urls              [2]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // Protobuf unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+	httpGet           bool                           // use GET requests for methods without side effects
+}
+
+// NewCounterProtobufClient creates a Protobuf client that implements the CounterClient interface.
+// It communicates using Protobuf and can be configured with a custom HTTPClient.
+func NewCounterProtobufClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) CounterClient {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ProtobufCodec
+	_ = clientOpts.ReadOpt("codec", &codec)
+	httpGet := false
+	_ = clientOpts.ReadOpt("httpGet", &httpGet)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "twirp.internal.twirptest.client_only", "Counter")
+	urls := [2]string{
+		serviceURL + "Count",
+		serviceURL + "Get",
+	}
+
+	return &counterProtobufClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+		httpGet:           httpGet,
+	}
+}
+
+func (c *counterProtobufClient) Count(ctx context.Context, in *CountReq) (CounterCountClientStream, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.internal.twirptest.client_only")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Count")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Count"])
+	caller := c.callCount
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (CounterCountClientStream, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callCount(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(CounterCountClientStream)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(CounterCountClientStream) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterProtobufClient) callCount(ctx context.Context, in *CountReq) (CounterCountClientStream, error) {
+	ctx, stream, err := twirpruntime.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+	return &counterCountClientStream{ClientStream: stream}, nil
+}
+
+func (c *counterProtobufClient) Get(ctx context.Context, in *CountReq) (*Number, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.internal.twirptest.client_only")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Get")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Get"])
+	caller := c.callGet
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (*Number, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callGet(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Number)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Number) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterProtobufClient) callGet(ctx context.Context, in *CountReq) (*Number, error) {
+	out := new(Number)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out, c.codec, c.maxResponseBytes, c.compression, c.httpGet)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *counterProtobufClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// ===================
+// Counter JSON Client
+// ===================
+
+type counterJSONClient struct {
+	client            HTTPClient
+	urls              [2]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // JSON unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+	httpGet           bool                           // use GET requests for methods without side effects
+}
+
+// NewCounterJSONClient creates a JSON client that implements the CounterClient interface.
+// It communicates using JSON and can be configured with a custom HTTPClient.
+func NewCounterJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) CounterClient {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ReadJSONCodec(&clientOpts)
+	_ = clientOpts.ReadOpt("codec", &codec)
+	httpGet := false
+	_ = clientOpts.ReadOpt("httpGet", &httpGet)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "twirp.internal.twirptest.client_only", "Counter")
+	urls := [2]string{
+		serviceURL + "Count",
+		serviceURL + "Get",
+	}
+
+	return &counterJSONClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+		httpGet:           httpGet,
+	}
+}
+
+func (c *counterJSONClient) Count(ctx context.Context, in *CountReq) (CounterCountClientStream, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.internal.twirptest.client_only")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Count")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Count"])
+	caller := c.callCount
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (CounterCountClientStream, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callCount(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(CounterCountClientStream)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(CounterCountClientStream) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterJSONClient) callCount(ctx context.Context, in *CountReq) (CounterCountClientStream, error) {
+	ctx, stream, err := twirpruntime.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+	return &counterCountClientStream{ClientStream: stream}, nil
+}
+
+func (c *counterJSONClient) Get(ctx context.Context, in *CountReq) (*Number, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.internal.twirptest.client_only")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Get")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Get"])
+	caller := c.callGet
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (*Number, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callGet(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Number)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Number) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterJSONClient) callGet(ctx context.Context, in *CountReq) (*Number, error) {
+	out := new(Number)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out, c.codec, c.maxResponseBytes, c.compression, c.httpGet)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *counterJSONClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// =====
+// Utils
+// =====
+
+// HTTPClient is the interface used by generated clients to send HTTP requests.
+// It is fulfilled by *(net/http).Client, which is sufficient for most users.
+// Users can provide their own implementation for special retry policies.
+//
+// HTTPClient implementations should not follow redirects. Redirects are
+// automatically disabled if *(net/http).Client is passed to client
+// constructors. See twirpruntime.WithoutRedirects for more details.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+var twirpFileDescriptor0 = []byte{
+	// 176 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4c, 0xce, 0xc9, 0x4c,
+	0xcd, 0x2b, 0x89, 0xcf, 0xcf, 0xcb, 0xa9, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x52, 0x29,
+	0x29, 0xcf, 0x2c, 0x2a, 0xd0, 0xcb, 0xcc, 0x2b, 0x49, 0x2d, 0xca, 0x4b, 0xcc, 0xd1, 0x03, 0x73,
+	0x4b, 0x52, 0x8b, 0x4b, 0xf4, 0x90, 0xd4, 0x2a, 0x49, 0x71, 0x71, 0x38, 0xe7, 0x97, 0xe6, 0x95,
+	0x04, 0xa5, 0x16, 0x0a, 0xf1, 0x71, 0x31, 0x95, 0xe4, 0x4b, 0x30, 0x2a, 0x30, 0x6a, 0xb0, 0x06,
+	0x31, 0x95, 0xe4, 0x2b, 0xc9, 0x71, 0xb1, 0xf9, 0x95, 0xe6, 0x26, 0xa5, 0x16, 0x09, 0x89, 0x70,
+	0xb1, 0x96, 0x25, 0xe6, 0x94, 0xa6, 0x42, 0x25, 0x21, 0x1c, 0xa3, 0x7b, 0x8c, 0x5c, 0xec, 0x60,
+	0xcd, 0xa9, 0x45, 0x42, 0x99, 0x5c, 0xac, 0x60, 0xa6, 0x90, 0x9e, 0x1e, 0x31, 0xf6, 0xea, 0xc1,
+	0x2c, 0x95, 0xd2, 0x21, 0x4e, 0x3d, 0xc4, 0x21, 0x4a, 0x0c, 0x06, 0x8c, 0x42, 0x19, 0x5c, 0xcc,
+	0xee, 0xa9, 0xb4, 0xb6, 0x88, 0x79, 0x02, 0x13, 0xa3, 0x13, 0x5f, 0x14, 0x8f, 0x3e, 0x92, 0x5c,
+	0x12, 0x1b, 0x38, 0x64, 0x8d, 0x01, 0x03, 0x00, 0xb8, 0x10, 0xcb, 0x07, 0x6e, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client_only
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/twitchtv/twirp/internal/twirptest/server_only"
+)
+
+type counter struct{}
+
+func (counter) Count(ctx context.Context, req *server_only.CountReq, stream server_only.CounterCountServerStream) error {
+	for i := int32(1); i <= req.To; i++ {
+		if err := stream.Send(&server_only.Number{Value: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (counter) Get(ctx context.Context, req *server_only.CountReq) (*server_only.Number, error) {
+	return &server_only.Number{Value: req.To}, nil
+}
+
+// newServer serves the client_only service with the server_only service,
+// which has the same messages and methods under another package name.
+func newServer() *httptest.Server {
+	server := server_only.NewCounterServer(counter{})
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Path = strings.Replace(r.URL.Path, ".client_only.", ".server_only.", 1)
+		server.ServeHTTP(w, r)
+	}))
+}
+
+// declarations returns the names of the top-level declarations in a Go file.
+func declarations(t *testing.T, filename string) map[string]bool {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", filename, err)
+	}
+	names := map[string]bool{}
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			names[decl.Name.Name] = true
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok {
+					names[spec.Name.Name] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestClientOnlyDeclarations(t *testing.T) {
+	names := declarations(t, "client_only.twirp.go")
+	for _, name := range []string{"CounterClient", "NewCounterProtobufClient", "NewCounterJSONClient", "HTTPClient", "CounterCountClientStream"} {
+		if !names[name] {
+			t.Errorf("expected %s to be generated", name)
+		}
+	}
+	for _, name := range []string{"NewCounterServer", "TwirpServer", "WriteError", "writeError"} {
+		if names[name] {
+			t.Errorf("expected %s not to be generated", name)
+		}
+	}
+}
+
+func TestClientOnly(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	clients := map[string]CounterClient{
+		"protobuf": NewCounterProtobufClient(s.URL, http.DefaultClient),
+		"json":     NewCounterJSONClient(s.URL, http.DefaultClient),
+	}
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			n, err := client.Get(context.Background(), &CountReq{To: 3})
+			if err != nil || n.Value != 3 {
+				t.Fatalf("unexpected Get response %v, err=%v", n, err)
+			}
+
+			stream, err := client.Count(context.Background(), &CountReq{To: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stream.Close()
+			var values []int32
+			for stream.Next() {
+				values = append(values, stream.Msg().Value)
+			}
+			if err := stream.Err(); err != nil || len(values) != 2 || values[0] != 1 || values[1] != 2 {
+				t.Fatalf("unexpected Count values %v, err=%v", values, err)
+			}
+		})
+	}
+}
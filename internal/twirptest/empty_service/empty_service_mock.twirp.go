@@ -0,0 +1,46 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: empty_service.proto
+
+package empty_service
+
+import sync "sync"
+
+import proto "google.golang.org/protobuf/proto"
+
+// ==========
+// Empty Mock
+// ==========
+
+// EmptyMock is a mock implementation of the Empty interface, to be used in tests.
+//
+// Calls to each method are recorded, and answered with the first matching expectation
+// added with Expect<Method>. Calls that match no expectation are answered by the
+// <Method>Func stub if not nil, or fail with a twirp.Unimplemented error. Call
+// AssertExpectations at the end of the test to check that all the expectations were
+// met, and that there were no unexpected calls.
+type EmptyMock struct {
+	mu         sync.Mutex
+	unexpected []emptyMockCall
+}
+
+var _ Empty = (*EmptyMock)(nil)
+
+// emptyMockCall is a call that matched no expectation or stub, reported by AssertExpectations.
+type emptyMockCall struct {
+	method string
+	req    proto.Message
+}
+
+// AssertExpectations reports on t the expectations that were not met, and the calls
+// that matched no expectation or stub.
+func (m *EmptyMock) AssertExpectations(t interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, call := range m.unexpected {
+		t.Errorf("EmptyMock: unexpected call to %s with request %v", call.method, call.req)
+	}
+}
@@ -13,4 +13,4 @@
 
 package twirptest
 
-//go:generate protoc --go_out=paths=source_relative:. --twirp_out=paths=source_relative:. service.proto
+//go:generate protoc --go_out=paths=source_relative:. --twirp_out=paths=source_relative,mocks=true,unimplemented=true:. service.proto
@@ -1,4 +1,4 @@
-// Code generated by protoc-gen-twirp v8.1.3, DO NOT EDIT.
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
 // source: service.proto
 
 package google_protobuf_imports
@@ -7,28 +7,21 @@ import context "context"
 import fmt "fmt"
 import http "net/http"
 import io "io"
-import json "encoding/json"
 import strconv "strconv"
-import strings "strings"
+import time "time"
 
-import protojson "google.golang.org/protobuf/encoding/protojson"
-import proto "google.golang.org/protobuf/proto"
 import twirp "github.com/twitchtv/twirp"
 import ctxsetters "github.com/twitchtv/twirp/ctxsetters"
+import twirpruntime "github.com/twitchtv/twirp/twirpruntime"
 
 import google_protobuf "google.golang.org/protobuf/types/known/emptypb"
 import google_protobuf1 "google.golang.org/protobuf/types/known/wrapperspb"
 
-import bytes "bytes"
-import errors "errors"
-import path "path"
-import url "net/url"
-
 // Version compatibility assertion.
 // If the constant is not defined in the package, that likely means
 // the package needs to be updated to work with this generated code.
 // See https://twitchtv.github.io/twirp/docs/version_matrix.html
-const _ = twirp.TwirpPackageMinVersion_8_1_0
+const _ = twirp.TwirpPackageMinVersion_8_3_0
 
 // =============
 // Svc Interface
@@ -43,17 +36,21 @@ type Svc interface {
 // ===================
 
 type svcProtobufClient struct {
-	client      HTTPClient
-	urls        [1]string
-	interceptor twirp.Interceptor
-	opts        twirp.ClientOptions
+	client            HTTPClient
+	urls              [1]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // Protobuf unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
 }
 
 // NewSvcProtobufClient creates a Protobuf client that implements the Svc interface.
 // It communicates using Protobuf and can be configured with a custom HTTPClient.
 func NewSvcProtobufClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) Svc {
 	if c, ok := client.(*http.Client); ok {
-		client = withoutRedirects(c)
+		client = twirpruntime.WithoutRedirects(c)
 	}
 
 	clientOpts := twirp.ClientOptions{}
@@ -68,19 +65,27 @@ func NewSvcProtobufClient(baseURL string, client HTTPClient, opts ...twirp.Clien
 	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
 		pathPrefix = "/twirp" // default prefix
 	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ProtobufCodec
+	_ = clientOpts.ReadOpt("codec", &codec)
 
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
-	serviceURL := sanitizeBaseURL(baseURL)
-	serviceURL += baseServicePath(pathPrefix, "twirp.internal.twirptest.use_empty", "Svc")
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "twirp.internal.twirptest.use_empty", "Svc")
 	urls := [1]string{
 		serviceURL + "Send",
 	}
 
 	return &svcProtobufClient{
-		client:      client,
-		urls:        urls,
-		interceptor: twirp.ChainInterceptors(clientOpts.Interceptors...),
-		opts:        clientOpts,
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
 	}
 }
 
@@ -88,6 +93,7 @@ func (c *svcProtobufClient) Send(ctx context.Context, in *google_protobuf1.Strin
 	ctx = ctxsetters.WithPackageName(ctx, "twirp.internal.twirptest.use_empty")
 	ctx = ctxsetters.WithServiceName(ctx, "Svc")
 	ctx = ctxsetters.WithMethodName(ctx, "Send")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Send"])
 	caller := c.callSend
 	if c.interceptor != nil {
 		caller = func(ctx context.Context, req *google_protobuf1.StringValue) (*google_protobuf.Empty, error) {
@@ -115,37 +121,47 @@ func (c *svcProtobufClient) Send(ctx context.Context, in *google_protobuf1.Strin
 
 func (c *svcProtobufClient) callSend(ctx context.Context, in *google_protobuf1.StringValue) (*google_protobuf.Empty, error) {
 	out := new(google_protobuf.Empty)
-	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, false)
 	if err != nil {
 		twerr, ok := err.(twirp.Error)
 		if !ok {
 			twerr = twirp.InternalErrorWith(err)
 		}
-		callClientError(ctx, c.opts.Hooks, twerr)
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
 		return nil, err
 	}
 
-	callClientResponseReceived(ctx, c.opts.Hooks)
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
 
 	return out, nil
 }
 
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *svcProtobufClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
 // ===============
 // Svc JSON Client
 // ===============
 
 type svcJSONClient struct {
-	client      HTTPClient
-	urls        [1]string
-	interceptor twirp.Interceptor
-	opts        twirp.ClientOptions
+	client            HTTPClient
+	urls              [1]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // JSON unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
 }
 
 // NewSvcJSONClient creates a JSON client that implements the Svc interface.
 // It communicates using JSON and can be configured with a custom HTTPClient.
 func NewSvcJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) Svc {
 	if c, ok := client.(*http.Client); ok {
-		client = withoutRedirects(c)
+		client = twirpruntime.WithoutRedirects(c)
 	}
 
 	clientOpts := twirp.ClientOptions{}
@@ -160,19 +176,27 @@ func NewSvcJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOpt
 	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
 		pathPrefix = "/twirp" // default prefix
 	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ReadJSONCodec(&clientOpts)
+	_ = clientOpts.ReadOpt("codec", &codec)
 
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
-	serviceURL := sanitizeBaseURL(baseURL)
-	serviceURL += baseServicePath(pathPrefix, "twirp.internal.twirptest.use_empty", "Svc")
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "twirp.internal.twirptest.use_empty", "Svc")
 	urls := [1]string{
 		serviceURL + "Send",
 	}
 
 	return &svcJSONClient{
-		client:      client,
-		urls:        urls,
-		interceptor: twirp.ChainInterceptors(clientOpts.Interceptors...),
-		opts:        clientOpts,
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
 	}
 }
 
@@ -180,6 +204,7 @@ func (c *svcJSONClient) Send(ctx context.Context, in *google_protobuf1.StringVal
 	ctx = ctxsetters.WithPackageName(ctx, "twirp.internal.twirptest.use_empty")
 	ctx = ctxsetters.WithServiceName(ctx, "Svc")
 	ctx = ctxsetters.WithMethodName(ctx, "Send")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Send"])
 	caller := c.callSend
 	if c.interceptor != nil {
 		caller = func(ctx context.Context, req *google_protobuf1.StringValue) (*google_protobuf.Empty, error) {
@@ -207,68 +232,105 @@ func (c *svcJSONClient) Send(ctx context.Context, in *google_protobuf1.StringVal
 
 func (c *svcJSONClient) callSend(ctx context.Context, in *google_protobuf1.StringValue) (*google_protobuf.Empty, error) {
 	out := new(google_protobuf.Empty)
-	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, false)
 	if err != nil {
 		twerr, ok := err.(twirp.Error)
 		if !ok {
 			twerr = twirp.InternalErrorWith(err)
 		}
-		callClientError(ctx, c.opts.Hooks, twerr)
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
 		return nil, err
 	}
 
-	callClientResponseReceived(ctx, c.opts.Hooks)
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
 
 	return out, nil
 }
 
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *svcJSONClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
 // ==================
 // Svc Server Handler
 // ==================
 
 type svcServer struct {
 	Svc
-	interceptor      twirp.Interceptor
-	hooks            *twirp.ServerHooks
-	pathPrefix       string // prefix for routing
-	jsonSkipDefaults bool   // do not include unpopulated fields (default values) in the response
-	jsonCamelCase    bool   // JSON fields are serialized as lowerCamelCase rather than keeping the original proto names
+	interceptor           twirp.Interceptor
+	hooks                 *twirp.ServerHooks
+	pathPrefix            string                                                 // prefix for routing
+	maxRequestBytes       int64                                                  // limit for request bodies, no limit if 0 or less
+	methodMaxRequestBytes map[string]int64                                       // per-method overrides of maxRequestBytes
+	compression           twirpruntime.Compression                               // request decompression and response compression
+	requestTimeouts       bool                                                   // apply timeouts from the Twirp-Timeout request header
+	maxRequestTimeout     time.Duration                                          // limit for timeouts from the Twirp-Timeout request header
+	panicHandler          func(context.Context, interface{}, []byte) twirp.Error // handles panics in methods, re-panic if nil
+	errorTransformer      func(context.Context, error) twirp.Error               // maps errors before they are sent, optional
+	codecs                map[string]twirp.Codec                                 // codecs by content type, including the built-in Protobuf and JSON codecs
+	methodDescriptors     twirpruntime.MethodDescriptors                         // added to request contexts
 }
 
 // NewSvcServer builds a TwirpServer that can be used as an http.Handler to handle
 // HTTP requests that are routed to the right method in the provided svc implementation.
 // The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
 func NewSvcServer(svc Svc, opts ...interface{}) TwirpServer {
-	serverOpts := newServerOpts(opts)
+	serverOpts := twirpruntime.NewServerOptions(opts)
 
 	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
-	jsonSkipDefaults := false
-	_ = serverOpts.ReadOpt("jsonSkipDefaults", &jsonSkipDefaults)
-	jsonCamelCase := false
-	_ = serverOpts.ReadOpt("jsonCamelCase", &jsonCamelCase)
+	var maxRequestBytes int64
+	_ = serverOpts.ReadOpt("maxRequestBytes", &maxRequestBytes)
+	var methodMaxRequestBytes map[string]int64
+	_ = serverOpts.ReadOpt("methodMaxRequestBytes", &methodMaxRequestBytes)
+	requestTimeouts := true
+	_ = serverOpts.ReadOpt("requestTimeouts", &requestTimeouts)
+	var maxRequestTimeout time.Duration
+	_ = serverOpts.ReadOpt("maxRequestTimeout", &maxRequestTimeout)
+	var panicHandler func(context.Context, interface{}, []byte) twirp.Error
+	_ = serverOpts.ReadOpt("panicHandler", &panicHandler)
+	var errorTransformer func(context.Context, error) twirp.Error
+	_ = serverOpts.ReadOpt("errorTransformer", &errorTransformer)
 	var pathPrefix string
 	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
 		pathPrefix = "/twirp" // default prefix
 	}
 
 	return &svcServer{
-		Svc:              svc,
-		hooks:            serverOpts.Hooks,
-		interceptor:      twirp.ChainInterceptors(serverOpts.Interceptors...),
-		pathPrefix:       pathPrefix,
-		jsonSkipDefaults: jsonSkipDefaults,
-		jsonCamelCase:    jsonCamelCase,
+		Svc:                   svc,
+		hooks:                 serverOpts.Hooks,
+		interceptor:           twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:            pathPrefix,
+		maxRequestBytes:       maxRequestBytes,
+		methodMaxRequestBytes: methodMaxRequestBytes,
+		compression:           twirpruntime.ReadCompression(serverOpts),
+		requestTimeouts:       requestTimeouts,
+		maxRequestTimeout:     maxRequestTimeout,
+		panicHandler:          panicHandler,
+		errorTransformer:      errorTransformer,
+		codecs:                twirpruntime.ReadServerCodecs(serverOpts),
+		methodDescriptors:     twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
 	}
 }
 
 // writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// The error is first mapped by the error transformer, if any (see twirp.WithServerErrorTransformer).
 // If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
 func (s *svcServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
-	writeError(ctx, resp, err, s.hooks)
+	twirpruntime.WriteError(ctx, resp, twirpruntime.TransformError(ctx, err, s.errorTransformer), s.hooks)
 }
 
 // handleRequestBodyError is used to handle error when the twirp server cannot read request
 func (s *svcServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if twerr, ok := err.(twirp.Error); ok {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if twerr := twirpruntime.BodyTooLargeTwirpError(err, "request "); twerr != nil {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
 	if context.Canceled == ctx.Err() {
 		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
 		return
@@ -277,7 +339,17 @@ func (s *svcServer) handleRequestBodyError(ctx context.Context, resp http.Respon
 		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
 		return
 	}
-	s.writeError(ctx, resp, twirp.WrapError(malformedRequestError(msg), err))
+	s.writeError(ctx, resp, twirp.WrapError(twirpruntime.MalformedRequestError(msg), err))
+}
+
+// requestBody returns the request body, decompressed if it has a Content-Encoding,
+// and limited to the max size configured for the method. It must be closed after reading.
+func (s *svcServer) requestBody(req *http.Request, method string) (io.ReadCloser, error) {
+	maxBytes := s.maxRequestBytes
+	if n, ok := s.methodMaxRequestBytes[method]; ok {
+		maxBytes = n
+	}
+	return twirpruntime.RequestBody(req, maxBytes, s.compression)
 }
 
 // SvcPathPrefix is a convenience constant that may identify URL paths.
@@ -288,12 +360,20 @@ const SvcPathPrefix = "/twirp/twirp.internal.twirptest.use_empty.Svc/"
 
 func (s *svcServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
+	if hooks, interceptor, ok := twirpruntime.MuxServerOptions(ctx); ok {
+		// Served by a twirp.Mux: apply the shared hooks and interceptors before the ones from this server
+		shared := *s
+		shared.hooks = twirp.ChainHooks(hooks, s.hooks)
+		shared.interceptor = twirp.ChainInterceptors(interceptor, s.interceptor)
+		s = &shared
+	}
 	ctx = ctxsetters.WithPackageName(ctx, "twirp.internal.twirptest.use_empty")
 	ctx = ctxsetters.WithServiceName(ctx, "Svc")
 	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	ctx = twirpruntime.WithErrorDetailsAccepted(ctx, req) // only clients that send Twirp-Error-Details get error details
 
 	var err error
-	ctx, err = callRequestReceived(ctx, s.hooks)
+	ctx, err = twirpruntime.CallRequestReceived(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
 		return
@@ -301,20 +381,20 @@ func (s *svcServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 
 	if req.Method != "POST" {
 		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
 		return
 	}
 
 	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
-	prefix, pkgService, method := parseTwirpPath(req.URL.Path)
+	prefix, pkgService, method := twirpruntime.ParseTwirpPath(req.URL.Path)
 	if pkgService != "twirp.internal.twirptest.use_empty.Svc" {
 		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
 		return
 	}
 	if prefix != s.pathPrefix {
 		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
 		return
 	}
 
@@ -324,129 +404,58 @@ func (s *svcServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		return
 	default:
 		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
 		return
 	}
 }
 
 func (s *svcServer) serveSend(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
-	header := req.Header.Get("Content-Type")
-	i := strings.Index(header, ";")
-	if i == -1 {
-		i = len(header)
-	}
-	switch strings.TrimSpace(strings.ToLower(header[:i])) {
-	case "application/json":
-		s.serveSendJSON(ctx, resp, req)
-	case "application/protobuf":
-		s.serveSendProtobuf(ctx, resp, req)
-	default:
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
-		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
 		s.writeError(ctx, resp, twerr)
+		return
 	}
+	s.serveSendCodec(ctx, resp, req, codec)
 }
 
-func (s *svcServer) serveSendJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *svcServer) serveSendCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "Send")
-	ctx, err = callRequestRouted(ctx, s.hooks)
-	if err != nil {
-		s.writeError(ctx, resp, err)
-		return
-	}
-
-	d := json.NewDecoder(req.Body)
-	rawReqBody := json.RawMessage{}
-	if err := d.Decode(&rawReqBody); err != nil {
-		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
-		return
-	}
-	reqContent := new(google_protobuf1.StringValue)
-	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
-	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
-		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
-		return
-	}
-
-	handler := s.Svc.Send
-	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *google_protobuf1.StringValue) (*google_protobuf.Empty, error) {
-			resp, err := s.interceptor(
-				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*google_protobuf1.StringValue)
-					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*google_protobuf1.StringValue) when calling interceptor")
-					}
-					return s.Svc.Send(ctx, typedReq)
-				},
-			)(ctx, req)
-			if resp != nil {
-				typedResp, ok := resp.(*google_protobuf.Empty)
-				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*google_protobuf.Empty) when calling interceptor")
-				}
-				return typedResp, err
-			}
-			return nil, err
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
 		}
 	}
 
-	// Call service method
-	var respContent *google_protobuf.Empty
-	func() {
-		defer ensurePanicResponses(ctx, resp, s.hooks)
-		respContent, err = handler(ctx, reqContent)
-	}()
-
+	ctx = ctxsetters.WithMethodName(ctx, "Send")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["Send"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
 		return
 	}
-	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *google_protobuf.Empty and nil error while calling Send. nil responses are not supported"))
-		return
-	}
 
-	ctx = callResponsePrepared(ctx, s.hooks)
-
-	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
-	respBytes, err := marshaler.Marshal(respContent)
-	if err != nil {
-		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
-		return
-	}
-
-	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
-	resp.Header().Set("Content-Type", "application/json")
-	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
-	resp.WriteHeader(http.StatusOK)
-
-	if n, err := resp.Write(respBytes); err != nil {
-		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
-		twerr := twirp.NewError(twirp.Unknown, msg)
-		ctx = callError(ctx, s.hooks, twerr)
-	}
-	callResponseSent(ctx, s.hooks)
-}
-
-func (s *svcServer) serveSendProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
-	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "Send")
-	ctx, err = callRequestRouted(ctx, s.hooks)
+	reqBody, err := s.requestBody(req, "Send")
 	if err != nil {
-		s.writeError(ctx, resp, err)
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
 		return
 	}
+	defer func() { _ = reqBody.Close() }()
 
-	buf, err := io.ReadAll(req.Body)
+	buf, err := io.ReadAll(reqBody)
 	if err != nil {
-		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
 		return
 	}
 	reqContent := new(google_protobuf1.StringValue)
-	if err = proto.Unmarshal(buf, reqContent); err != nil {
-		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
 		return
 	}
 
@@ -476,8 +485,11 @@ func (s *svcServer) serveSendProtobuf(ctx context.Context, resp http.ResponseWri
 	// Call service method
 	var respContent *google_protobuf.Empty
 	func() {
-		defer ensurePanicResponses(ctx, resp, s.hooks)
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
 		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
 	}()
 
 	if err != nil {
@@ -489,24 +501,31 @@ func (s *svcServer) serveSendProtobuf(ctx context.Context, resp http.ResponseWri
 		return
 	}
 
-	ctx = callResponsePrepared(ctx, s.hooks)
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
 
-	respBytes, err := proto.Marshal(respContent)
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
 	if err != nil {
-		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
 		return
 	}
 
 	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
-	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
 	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
 	resp.WriteHeader(http.StatusOK)
 	if n, err := resp.Write(respBytes); err != nil {
 		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
 		twerr := twirp.NewError(twirp.Unknown, msg)
-		ctx = callError(ctx, s.hooks, twerr)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
 	}
-	callResponseSent(ctx, s.hooks)
+	twirpruntime.CallResponseSent(ctx, s.hooks)
 }
 
 func (s *svcServer) ServiceDescriptor() ([]byte, int) {
@@ -514,14 +533,17 @@ func (s *svcServer) ServiceDescriptor() ([]byte, int) {
 }
 
 func (s *svcServer) ProtocGenTwirpVersion() string {
-	return "v8.1.3"
+	return "v8.3.0"
 }
 
+// AppliesMuxServerOptions marks servers that apply the hooks and interceptors shared by a twirp.Mux.
+func (s *svcServer) AppliesMuxServerOptions() {}
+
 // PathPrefix returns the base service path, in the form: "/<prefix>/<package>.<Service>/"
 // that is everything in a Twirp route except for the <Method>. This can be used for routing,
 // for example to identify the requests that are targeted to this service in a mux.
 func (s *svcServer) PathPrefix() string {
-	return baseServicePath(s.pathPrefix, "twirp.internal.twirptest.use_empty", "Svc")
+	return twirpruntime.BaseServicePath(s.pathPrefix, "twirp.internal.twirptest.use_empty", "Svc")
 }
 
 // =====
@@ -534,8 +556,7 @@ func (s *svcServer) PathPrefix() string {
 //
 // HTTPClient implementations should not follow redirects. Redirects are
 // automatically disabled if *(net/http).Client is passed to client
-// constructors. See the withoutRedirects function in this file for more
-// details.
+// constructors. See twirpruntime.WithoutRedirects for more details.
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -568,529 +589,15 @@ type TwirpServer interface {
 	PathPrefix() string
 }
 
-func newServerOpts(opts []interface{}) *twirp.ServerOptions {
-	serverOpts := &twirp.ServerOptions{}
-	for _, opt := range opts {
-		switch o := opt.(type) {
-		case twirp.ServerOption:
-			o(serverOpts)
-		case *twirp.ServerHooks: // backwards compatibility, allow to specify hooks as an argument
-			twirp.WithServerHooks(o)(serverOpts)
-		case nil: // backwards compatibility, allow nil value for the argument
-			continue
-		default:
-			panic(fmt.Sprintf("Invalid option type %T, please use a twirp.ServerOption", o))
-		}
-	}
-	return serverOpts
-}
-
 // WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).
 // Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.
 // If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
 func WriteError(resp http.ResponseWriter, err error) {
-	writeError(context.Background(), resp, err, nil)
-}
-
-// writeError writes Twirp errors in the response and triggers hooks.
-func writeError(ctx context.Context, resp http.ResponseWriter, err error, hooks *twirp.ServerHooks) {
-	// Convert to a twirp.Error. Non-twirp errors are converted to internal errors.
-	var twerr twirp.Error
-	if !errors.As(err, &twerr) {
-		twerr = twirp.InternalErrorWith(err)
-	}
-
-	statusCode := twirp.ServerHTTPStatusFromErrorCode(twerr.Code())
-	ctx = ctxsetters.WithStatusCode(ctx, statusCode)
-	ctx = callError(ctx, hooks, twerr)
-
-	respBody := marshalErrorToJSON(twerr)
-
-	resp.Header().Set("Content-Type", "application/json") // Error responses are always JSON
-	resp.Header().Set("Content-Length", strconv.Itoa(len(respBody)))
-	resp.WriteHeader(statusCode) // set HTTP status code and send response
-
-	_, writeErr := resp.Write(respBody)
-	if writeErr != nil {
-		// We have three options here. We could log the error, call the Error
-		// hook, or just silently ignore the error.
-		//
-		// Logging is unacceptable because we don't have a user-controlled
-		// logger; writing out to stderr without permission is too rude.
-		//
-		// Calling the Error hook would confuse users: it would mean the Error
-		// hook got called twice for one request, which is likely to lead to
-		// duplicated log messages and metrics, no matter how well we document
-		// the behavior.
-		//
-		// Silently ignoring the error is our least-bad option. It's highly
-		// likely that the connection is broken and the original 'err' says
-		// so anyway.
-		_ = writeErr
-	}
-
-	callResponseSent(ctx, hooks)
-}
-
-// sanitizeBaseURL parses the baseURL, and adds the "http" scheme if needed.
-// If the URL is unparsable, the baseURL is returned unchanged.
-func sanitizeBaseURL(baseURL string) string {
-	u, err := url.Parse(baseURL)
-	if err != nil {
-		return baseURL // invalid URL will fail later when making requests
-	}
-	if u.Scheme == "" {
-		u.Scheme = "http"
-	}
-	return u.String()
-}
-
-// baseServicePath composes the path prefix for the service (without <Method>).
-// e.g.: baseServicePath("/twirp", "my.pkg", "MyService")
-//
-//	returns => "/twirp/my.pkg.MyService/"
-//
-// e.g.: baseServicePath("", "", "MyService")
-//
-//	returns => "/MyService/"
-func baseServicePath(prefix, pkg, service string) string {
-	fullServiceName := service
-	if pkg != "" {
-		fullServiceName = pkg + "." + service
-	}
-	return path.Join("/", prefix, fullServiceName) + "/"
-}
-
-// parseTwirpPath extracts path components form a valid Twirp route.
-// Expected format: "[<prefix>]/<package>.<Service>/<Method>"
-// e.g.: prefix, pkgService, method := parseTwirpPath("/twirp/pkg.Svc/MakeHat")
-func parseTwirpPath(path string) (string, string, string) {
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 {
-		return "", "", ""
-	}
-	method := parts[len(parts)-1]
-	pkgService := parts[len(parts)-2]
-	prefix := strings.Join(parts[0:len(parts)-2], "/")
-	return prefix, pkgService, method
-}
-
-// getCustomHTTPReqHeaders retrieves a copy of any headers that are set in
-// a context through the twirp.WithHTTPRequestHeaders function.
-// If there are no headers set, or if they have the wrong type, nil is returned.
-func getCustomHTTPReqHeaders(ctx context.Context) http.Header {
-	header, ok := twirp.HTTPRequestHeaders(ctx)
-	if !ok || header == nil {
-		return nil
-	}
-	copied := make(http.Header)
-	for k, vv := range header {
-		if vv == nil {
-			copied[k] = nil
-			continue
-		}
-		copied[k] = make([]string, len(vv))
-		copy(copied[k], vv)
-	}
-	return copied
-}
-
-// newRequest makes an http.Request from a client, adding common headers.
-func newRequest(ctx context.Context, url string, reqBody io.Reader, contentType string) (*http.Request, error) {
-	req, err := http.NewRequest("POST", url, reqBody)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	if customHeader := getCustomHTTPReqHeaders(ctx); customHeader != nil {
-		req.Header = customHeader
-	}
-	req.Header.Set("Accept", contentType)
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Twirp-Version", "v8.1.3")
-	return req, nil
-}
-
-// JSON serialization for errors
-type twerrJSON struct {
-	Code string            `json:"code"`
-	Msg  string            `json:"msg"`
-	Meta map[string]string `json:"meta,omitempty"`
-}
-
-// marshalErrorToJSON returns JSON from a twirp.Error, that can be used as HTTP error response body.
-// If serialization fails, it will use a descriptive Internal error instead.
-func marshalErrorToJSON(twerr twirp.Error) []byte {
-	// make sure that msg is not too large
-	msg := twerr.Msg()
-	if len(msg) > 1e6 {
-		msg = msg[:1e6]
-	}
-
-	tj := twerrJSON{
-		Code: string(twerr.Code()),
-		Msg:  msg,
-		Meta: twerr.MetaMap(),
-	}
-
-	buf, err := json.Marshal(&tj)
-	if err != nil {
-		buf = []byte("{\"type\": \"" + twirp.Internal + "\", \"msg\": \"There was an error but it could not be serialized into JSON\"}") // fallback
-	}
-
-	return buf
-}
-
-// errorFromResponse builds a twirp.Error from a non-200 HTTP response.
-// If the response has a valid serialized Twirp error, then it's returned.
-// If not, the response status code is used to generate a similar twirp
-// error. See twirpErrorFromIntermediary for more info on intermediary errors.
-func errorFromResponse(resp *http.Response) twirp.Error {
-	statusCode := resp.StatusCode
-	statusText := http.StatusText(statusCode)
-
-	if isHTTPRedirect(statusCode) {
-		// Unexpected redirect: it must be an error from an intermediary.
-		// Twirp clients don't follow redirects automatically, Twirp only handles
-		// POST requests, redirects should only happen on GET and HEAD requests.
-		location := resp.Header.Get("Location")
-		msg := fmt.Sprintf("unexpected HTTP status code %d %q received, Location=%q", statusCode, statusText, location)
-		return twirpErrorFromIntermediary(statusCode, msg, location)
-	}
-
-	respBodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return wrapInternal(err, "failed to read server error response body")
-	}
-
-	var tj twerrJSON
-	dec := json.NewDecoder(bytes.NewReader(respBodyBytes))
-	dec.DisallowUnknownFields()
-	if err := dec.Decode(&tj); err != nil || tj.Code == "" {
-		// Invalid JSON response; it must be an error from an intermediary.
-		msg := fmt.Sprintf("Error from intermediary with HTTP status code %d %q", statusCode, statusText)
-		return twirpErrorFromIntermediary(statusCode, msg, string(respBodyBytes))
-	}
-
-	errorCode := twirp.ErrorCode(tj.Code)
-	if !twirp.IsValidErrorCode(errorCode) {
-		msg := "invalid type returned from server error response: " + tj.Code
-		return twirp.InternalError(msg).WithMeta("body", string(respBodyBytes))
-	}
-
-	twerr := twirp.NewError(errorCode, tj.Msg)
-	for k, v := range tj.Meta {
-		twerr = twerr.WithMeta(k, v)
-	}
-	return twerr
-}
-
-// twirpErrorFromIntermediary maps HTTP errors from non-twirp sources to twirp errors.
-// The mapping is similar to gRPC: https://github.com/grpc/grpc/blob/master/doc/http-grpc-status-mapping.md.
-// Returned twirp Errors have some additional metadata for inspection.
-func twirpErrorFromIntermediary(status int, msg string, bodyOrLocation string) twirp.Error {
-	var code twirp.ErrorCode
-	if isHTTPRedirect(status) { // 3xx
-		code = twirp.Internal
-	} else {
-		switch status {
-		case 400: // Bad Request
-			code = twirp.Internal
-		case 401: // Unauthorized
-			code = twirp.Unauthenticated
-		case 403: // Forbidden
-			code = twirp.PermissionDenied
-		case 404: // Not Found
-			code = twirp.BadRoute
-		case 429: // Too Many Requests
-			code = twirp.ResourceExhausted
-		case 502, 503, 504: // Bad Gateway, Service Unavailable, Gateway Timeout
-			code = twirp.Unavailable
-		default: // All other codes
-			code = twirp.Unknown
-		}
-	}
-
-	twerr := twirp.NewError(code, msg)
-	twerr = twerr.WithMeta("http_error_from_intermediary", "true") // to easily know if this error was from intermediary
-	twerr = twerr.WithMeta("status_code", strconv.Itoa(status))
-	if isHTTPRedirect(status) {
-		twerr = twerr.WithMeta("location", bodyOrLocation)
-	} else {
-		twerr = twerr.WithMeta("body", bodyOrLocation)
-	}
-	return twerr
-}
-
-func isHTTPRedirect(status int) bool {
-	return status >= 300 && status <= 399
-}
-
-// wrapInternal wraps an error with a prefix as an Internal error.
-// The original error cause is accessible by github.com/pkg/errors.Cause.
-func wrapInternal(err error, prefix string) twirp.Error {
-	return twirp.InternalErrorWith(&wrappedError{prefix: prefix, cause: err})
-}
-
-type wrappedError struct {
-	prefix string
-	cause  error
-}
-
-func (e *wrappedError) Error() string { return e.prefix + ": " + e.cause.Error() }
-func (e *wrappedError) Unwrap() error { return e.cause } // for go1.13 + errors.Is/As
-func (e *wrappedError) Cause() error  { return e.cause } // for github.com/pkg/errors
-
-// ensurePanicResponses makes sure that rpc methods causing a panic still result in a Twirp Internal
-// error response (status 500), and error hooks are properly called with the panic wrapped as an error.
-// The panic is re-raised so it can be handled normally with middleware.
-func ensurePanicResponses(ctx context.Context, resp http.ResponseWriter, hooks *twirp.ServerHooks) {
-	if r := recover(); r != nil {
-		// Wrap the panic as an error so it can be passed to error hooks.
-		// The original error is accessible from error hooks, but not visible in the response.
-		err := errFromPanic(r)
-		twerr := &internalWithCause{msg: "Internal service panic", cause: err}
-		// Actually write the error
-		writeError(ctx, resp, twerr, hooks)
-		// If possible, flush the error to the wire.
-		f, ok := resp.(http.Flusher)
-		if ok {
-			f.Flush()
-		}
-
-		panic(r)
-	}
-}
-
-// errFromPanic returns the typed error if the recovered panic is an error, otherwise formats as error.
-func errFromPanic(p interface{}) error {
-	if err, ok := p.(error); ok {
-		return err
-	}
-	return fmt.Errorf("panic: %v", p)
-}
-
-// internalWithCause is a Twirp Internal error wrapping an original error cause,
-// but the original error message is not exposed on Msg(). The original error
-// can be checked with go1.13+ errors.Is/As, and also by (github.com/pkg/errors).Unwrap
-type internalWithCause struct {
-	msg   string
-	cause error
-}
-
-func (e *internalWithCause) Unwrap() error                               { return e.cause } // for go1.13 + errors.Is/As
-func (e *internalWithCause) Cause() error                                { return e.cause } // for github.com/pkg/errors
-func (e *internalWithCause) Error() string                               { return e.msg + ": " + e.cause.Error() }
-func (e *internalWithCause) Code() twirp.ErrorCode                       { return twirp.Internal }
-func (e *internalWithCause) Msg() string                                 { return e.msg }
-func (e *internalWithCause) Meta(key string) string                      { return "" }
-func (e *internalWithCause) MetaMap() map[string]string                  { return nil }
-func (e *internalWithCause) WithMeta(key string, val string) twirp.Error { return e }
-
-// malformedRequestError is used when the twirp server cannot unmarshal a request
-func malformedRequestError(msg string) twirp.Error {
-	return twirp.NewError(twirp.Malformed, msg)
-}
-
-// badRouteError is used when the twirp server cannot route a request
-func badRouteError(msg string, method, url string) twirp.Error {
-	err := twirp.NewError(twirp.BadRoute, msg)
-	err = err.WithMeta("twirp_invalid_route", method+" "+url)
-	return err
-}
-
-// withoutRedirects makes sure that the POST request can not be redirected.
-// The standard library will, by default, redirect requests (including POSTs) if it gets a 302 or
-// 303 response, and also 301s in go1.8. It redirects by making a second request, changing the
-// method to GET and removing the body. This produces very confusing error messages, so instead we
-// set a redirect policy that always errors. This stops Go from executing the redirect.
-//
-// We have to be a little careful in case the user-provided http.Client has its own CheckRedirect
-// policy - if so, we'll run through that policy first.
-//
-// Because this requires modifying the http.Client, we make a new copy of the client and return it.
-func withoutRedirects(in *http.Client) *http.Client {
-	copy := *in
-	copy.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		if in.CheckRedirect != nil {
-			// Run the input's redirect if it exists, in case it has side effects, but ignore any error it
-			// returns, since we want to use ErrUseLastResponse.
-			err := in.CheckRedirect(req, via)
-			_ = err // Silly, but this makes sure generated code passes errcheck -blank, which some people use.
-		}
-		return http.ErrUseLastResponse
-	}
-	return &copy
-}
-
-// doProtobufRequest makes a Protobuf request to the remote Twirp service.
-func doProtobufRequest(ctx context.Context, client HTTPClient, hooks *twirp.ClientHooks, url string, in, out proto.Message) (_ context.Context, err error) {
-	reqBodyBytes, err := proto.Marshal(in)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to marshal proto request")
-	}
-	reqBody := bytes.NewBuffer(reqBodyBytes)
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	req, err := newRequest(ctx, url, reqBody, "application/protobuf")
-	if err != nil {
-		return ctx, wrapInternal(err, "could not build request")
-	}
-	ctx, err = callClientRequestPrepared(ctx, hooks, req)
-	if err != nil {
-		return ctx, err
-	}
-
-	req = req.WithContext(ctx)
-	resp, err := client.Do(req)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to do request")
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	if resp.StatusCode != 200 {
-		return ctx, errorFromResponse(resp)
-	}
-
-	respBodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to read response body")
-	}
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	if err = proto.Unmarshal(respBodyBytes, out); err != nil {
-		return ctx, wrapInternal(err, "failed to unmarshal proto response")
-	}
-	return ctx, nil
-}
-
-// doJSONRequest makes a JSON request to the remote Twirp service.
-func doJSONRequest(ctx context.Context, client HTTPClient, hooks *twirp.ClientHooks, url string, in, out proto.Message) (_ context.Context, err error) {
-	marshaler := &protojson.MarshalOptions{UseProtoNames: true}
-	reqBytes, err := marshaler.Marshal(in)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to marshal json request")
-	}
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	req, err := newRequest(ctx, url, bytes.NewReader(reqBytes), "application/json")
-	if err != nil {
-		return ctx, wrapInternal(err, "could not build request")
-	}
-	ctx, err = callClientRequestPrepared(ctx, hooks, req)
-	if err != nil {
-		return ctx, err
-	}
-
-	req = req.WithContext(ctx)
-	resp, err := client.Do(req)
-	if err != nil {
-		return ctx, wrapInternal(err, "failed to do request")
-	}
-
-	defer func() {
-		cerr := resp.Body.Close()
-		if err == nil && cerr != nil {
-			err = wrapInternal(cerr, "failed to close response body")
-		}
-	}()
-
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-
-	if resp.StatusCode != 200 {
-		return ctx, errorFromResponse(resp)
-	}
-
-	d := json.NewDecoder(resp.Body)
-	rawRespBody := json.RawMessage{}
-	if err := d.Decode(&rawRespBody); err != nil {
-		return ctx, wrapInternal(err, "failed to unmarshal json response")
-	}
-	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
-	if err = unmarshaler.Unmarshal(rawRespBody, out); err != nil {
-		return ctx, wrapInternal(err, "failed to unmarshal json response")
-	}
-	if err = ctx.Err(); err != nil {
-		return ctx, wrapInternal(err, "aborted because context was done")
-	}
-	return ctx, nil
-}
-
-// Call twirp.ServerHooks.RequestReceived if the hook is available
-func callRequestReceived(ctx context.Context, h *twirp.ServerHooks) (context.Context, error) {
-	if h == nil || h.RequestReceived == nil {
-		return ctx, nil
-	}
-	return h.RequestReceived(ctx)
-}
-
-// Call twirp.ServerHooks.RequestRouted if the hook is available
-func callRequestRouted(ctx context.Context, h *twirp.ServerHooks) (context.Context, error) {
-	if h == nil || h.RequestRouted == nil {
-		return ctx, nil
-	}
-	return h.RequestRouted(ctx)
-}
-
-// Call twirp.ServerHooks.ResponsePrepared if the hook is available
-func callResponsePrepared(ctx context.Context, h *twirp.ServerHooks) context.Context {
-	if h == nil || h.ResponsePrepared == nil {
-		return ctx
-	}
-	return h.ResponsePrepared(ctx)
-}
-
-// Call twirp.ServerHooks.ResponseSent if the hook is available
-func callResponseSent(ctx context.Context, h *twirp.ServerHooks) {
-	if h == nil || h.ResponseSent == nil {
-		return
-	}
-	h.ResponseSent(ctx)
-}
-
-// Call twirp.ServerHooks.Error if the hook is available
-func callError(ctx context.Context, h *twirp.ServerHooks, err twirp.Error) context.Context {
-	if h == nil || h.Error == nil {
-		return ctx
-	}
-	return h.Error(ctx, err)
-}
-
-func callClientResponseReceived(ctx context.Context, h *twirp.ClientHooks) {
-	if h == nil || h.ResponseReceived == nil {
-		return
-	}
-	h.ResponseReceived(ctx)
-}
-
-func callClientRequestPrepared(ctx context.Context, h *twirp.ClientHooks, req *http.Request) (context.Context, error) {
-	if h == nil || h.RequestPrepared == nil {
-		return ctx, nil
-	}
-	return h.RequestPrepared(ctx, req)
-}
-
-func callClientError(ctx context.Context, h *twirp.ClientHooks, err twirp.Error) {
-	if h == nil || h.Error == nil {
-		return
-	}
-	h.Error(ctx, err)
+	twirpruntime.WriteError(context.Background(), resp, err, nil)
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 169 bytes of a gzipped FileDescriptorProto
+	// 166 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2d, 0x4e, 0x2d, 0x2a,
 	0xcb, 0x4c, 0x4e, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x52, 0x2a, 0x29, 0xcf, 0x2c, 0x2a,
 	0xd0, 0xcb, 0xcc, 0x2b, 0x49, 0x2d, 0xca, 0x4b, 0xcc, 0xd1, 0x03, 0x73, 0x4b, 0x52, 0x8b, 0x4b,
@@ -1100,6 +607,6 @@ var twirpFileDescriptor0 = []byte{
 	0x65, 0xc9, 0x42, 0x36, 0x5c, 0x2c, 0xc1, 0xa9, 0x79, 0x29, 0x42, 0x32, 0x7a, 0x10, 0xf5, 0x7a,
 	0x30, 0xf5, 0x7a, 0xc1, 0x25, 0x45, 0x99, 0x79, 0xe9, 0x61, 0x89, 0x39, 0xa5, 0xa9, 0x52, 0x62,
 	0x18, 0xb2, 0xae, 0x20, 0xab, 0x9c, 0xa4, 0xa2, 0x24, 0xf4, 0x21, 0x32, 0xf1, 0x30, 0x99, 0xf8,
-	0xcc, 0xdc, 0x82, 0xfc, 0xa2, 0x92, 0xe2, 0x24, 0x36, 0xb0, 0x88, 0x31, 0x20, 0x00, 0x00, 0xff,
-	0xff, 0x09, 0x85, 0xee, 0x47, 0xd9, 0x00, 0x00, 0x00,
+	0xcc, 0xdc, 0x82, 0xfc, 0xa2, 0x92, 0xe2, 0x24, 0x36, 0xb0, 0x88, 0x31, 0x60, 0x00, 0x09, 0x85,
+	0xee, 0x47, 0xd9, 0x00, 0x00, 0x00,
 }
@@ -78,8 +78,23 @@ func NilHatmaker() Haberdasher {
 }
 
 func ServerAndClient(h Haberdasher, hooks *twirp.ServerHooks) (*httptest.Server, Haberdasher) {
-	s := httptest.NewServer(NewHaberdasherServer(h, hooks))
-	c := NewHaberdasherProtobufClient(s.URL, http.DefaultClient)
+	return ServerAndClientWithOptions(h, []twirp.ServerOption{twirp.WithServerHooks(hooks)}, nil)
+}
+
+// ServerAndClientWithOptions starts a test server for the Haberdasher, and
+// returns it with a Protobuf client. The server handler is wrapped with the
+// middleware, in order.
+func ServerAndClientWithOptions(h Haberdasher, serverOpts []twirp.ServerOption, clientOpts []twirp.ClientOption, middleware ...func(http.Handler) http.Handler) (*httptest.Server, Haberdasher) {
+	opts := make([]interface{}, len(serverOpts))
+	for i, o := range serverOpts {
+		opts[i] = o
+	}
+	var handler http.Handler = NewHaberdasherServer(h, opts...)
+	for _, m := range middleware {
+		handler = m(handler)
+	}
+	s := httptest.NewServer(handler)
+	c := NewHaberdasherProtobufClient(s.URL, http.DefaultClient, clientOpts...)
 	return s, c
 }
 
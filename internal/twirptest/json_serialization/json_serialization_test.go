@@ -247,6 +247,114 @@ func TestJSONSerializationCamelCase(t *testing.T) {
 	}
 }
 
+func TestJSONClientOptions(t *testing.T) {
+	s := httptest.NewServer(NewJSONSerializationServer(&JSONSerializationService{}))
+	defer s.Close()
+
+	var reqBody []byte
+	httpClient := &recordingClient{reqBody: &reqBody}
+
+	// default: original proto names, enum names, no default values
+	client := NewJSONSerializationJSONClient(s.URL, httpClient)
+	if _, err := client.EchoJSON(context.Background(), &Msg{PageNumber: 1, Foobar: Msg_BAR}); err != nil {
+		t.Fatalf("client.EchoJSON err=%q", err)
+	}
+	objmap := readJSONAsMap(t, bytes.NewReader(reqBody))
+	if len(objmap) != 2 || string(objmap["page_number"]) != "1" || string(objmap["foobar"]) != `"BAR"` {
+		t.Errorf("unexpected request body %s", reqBody)
+	}
+
+	client = NewJSONSerializationJSONClient(s.URL, httpClient,
+		twirp.WithClientJSONCamelCaseNames(true),
+		twirp.WithClientJSONEmitDefaults(true),
+		twirp.WithClientJSONEnumNumbers(true),
+	)
+	msg, err := client.EchoJSON(context.Background(), &Msg{PageNumber: 1, Foobar: Msg_BAR})
+	if err != nil {
+		t.Fatalf("client.EchoJSON err=%q", err)
+	}
+	if msg.PageNumber != 1 || msg.Foobar != Msg_BAR {
+		t.Errorf("unexpected response %v", msg)
+	}
+	objmap = readJSONAsMap(t, bytes.NewReader(reqBody))
+	for _, field := range []string{"query", "pageNumber", "hell", "foobar", "snippets", "allEmpty"} {
+		if _, ok := objmap[field]; !ok {
+			t.Errorf("expected JSON request to include camelCase field %q, have=%s", field, reqBody)
+		}
+	}
+	if have, want := string(objmap["foobar"]), "1"; have != want {
+		t.Errorf("expected enum as number in JSON request, have=%s, want=%s", have, want)
+	}
+
+	// Protobuf clients ignore JSON options
+	pbClient := NewJSONSerializationProtobufClient(s.URL, http.DefaultClient, twirp.WithClientJSONCamelCaseNames(true))
+	if _, err := pbClient.EchoJSON(context.Background(), &Msg{PageNumber: 1}); err != nil {
+		t.Fatalf("pbClient.EchoJSON err=%q", err)
+	}
+}
+
+func TestJSONClientStrict(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"query":"q","unknown_field":1}`))
+	}))
+	defer s.Close()
+
+	msg, err := NewJSONSerializationJSONClient(s.URL, http.DefaultClient).EchoJSON(context.Background(), &Msg{})
+	if err != nil {
+		t.Fatalf("unknown fields are expected to be discarded by default, err=%q", err)
+	}
+	if msg.Query != "q" {
+		t.Errorf("unexpected response %v", msg)
+	}
+
+	_, err = NewJSONSerializationJSONClient(s.URL, http.DefaultClient, twirp.WithClientJSONStrict(true)).EchoJSON(context.Background(), &Msg{})
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.Internal {
+		t.Fatalf("expected internal error with unknown fields on strict clients, have=%v", err)
+	}
+}
+
+func TestJSONServerStrict(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		s := httptest.NewServer(NewJSONSerializationServer(&JSONSerializationService{}, twirp.WithServerJSONStrict(strict)))
+		defer s.Close()
+
+		for _, body := range []string{
+			`{"query":"q","unknown_field":1}`,
+			`{"query":"q"} {"query":"trailing"}`,
+			`{"query":"q"} x`,
+		} {
+			resp, err := http.Post(s.URL+"/twirp/JSONSerialization/EchoJSON", "application/json", bytes.NewBufferString(body))
+			if err != nil {
+				t.Fatalf("manual EchoJSON err=%q", err)
+			}
+			respBody, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+
+			wantStatus := 200
+			if strict {
+				wantStatus = 400
+			}
+			if resp.StatusCode != wantStatus {
+				t.Errorf("strict=%v, body=%s: invalid status, have=%d, want=%d", strict, body, resp.StatusCode, wantStatus)
+			}
+			if strict && !bytes.Contains(respBody, []byte("the json request could not be decoded")) {
+				t.Errorf("strict=%v, body=%s: unexpected response %s", strict, body, respBody)
+			}
+		}
+
+		// valid requests are accepted, with trailing whitespace
+		resp, err := http.Post(s.URL+"/twirp/JSONSerialization/EchoJSON", "application/json", bytes.NewBufferString(`{"query":"q"}`+"\n"))
+		if err != nil {
+			t.Fatalf("manual EchoJSON err=%q", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != 200 {
+			t.Errorf("strict=%v: invalid status for a valid request, have=%d, want=200", strict, resp.StatusCode)
+		}
+	}
+}
+
 //
 // Test helpers
 //
@@ -261,3 +369,18 @@ func readJSONAsMap(t *testing.T, body io.Reader) map[string]json.RawMessage {
 	}
 	return objmap
 }
+
+// recordingClient is an HTTPClient that records the body of the last request.
+type recordingClient struct {
+	reqBody *[]byte
+}
+
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	*c.reqBody = body
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,234 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0-devel
+// 	protoc        v3.21.8
+// source: method_timeouts.proto
+
+package method_timeouts
+
+import (
+	_ "github.com/twitchtv/twirp/options"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SleepReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DurationMs    int64 `protobuf:"varint,1,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	IgnoreContext bool  `protobuf:"varint,2,opt,name=ignore_context,json=ignoreContext,proto3" json:"ignore_context,omitempty"` // keep sleeping after the context is done
+}
+
+func (x *SleepReq) Reset() {
+	*x = SleepReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_method_timeouts_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SleepReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SleepReq) ProtoMessage() {}
+
+func (x *SleepReq) ProtoReflect() protoreflect.Message {
+	mi := &file_method_timeouts_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SleepReq.ProtoReflect.Descriptor instead.
+func (*SleepReq) Descriptor() ([]byte, []int) {
+	return file_method_timeouts_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SleepReq) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *SleepReq) GetIgnoreContext() bool {
+	if x != nil {
+		return x.IgnoreContext
+	}
+	return false
+}
+
+type SleepResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeadlineMs int64 `protobuf:"varint,1,opt,name=deadline_ms,json=deadlineMs,proto3" json:"deadline_ms,omitempty"` // time left until the deadline of the server context, 0 if none
+}
+
+func (x *SleepResp) Reset() {
+	*x = SleepResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_method_timeouts_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SleepResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SleepResp) ProtoMessage() {}
+
+func (x *SleepResp) ProtoReflect() protoreflect.Message {
+	mi := &file_method_timeouts_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SleepResp.ProtoReflect.Descriptor instead.
+func (*SleepResp) Descriptor() ([]byte, []int) {
+	return file_method_timeouts_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SleepResp) GetDeadlineMs() int64 {
+	if x != nil {
+		return x.DeadlineMs
+	}
+	return 0
+}
+
+var File_method_timeouts_proto protoreflect.FileDescriptor
+
+var file_method_timeouts_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x13, 0x74, 0x77, 0x69, 0x72, 0x70, 0x2f, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x52, 0x0a, 0x08,
+	0x53, 0x6c, 0x65, 0x65, 0x70, 0x52, 0x65, 0x71, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x67, 0x6e,
+	0x6f, 0x72, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0d, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
+	0x22, 0x2c, 0x0a, 0x09, 0x53, 0x6c, 0x65, 0x65, 0x70, 0x52, 0x65, 0x73, 0x70, 0x12, 0x1f, 0x0a,
+	0x0b, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0a, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x4d, 0x73, 0x32, 0x96,
+	0x01, 0x0a, 0x07, 0x53, 0x6c, 0x65, 0x65, 0x70, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x05, 0x53, 0x6c,
+	0x65, 0x65, 0x70, 0x12, 0x09, 0x2e, 0x53, 0x6c, 0x65, 0x65, 0x70, 0x52, 0x65, 0x71, 0x1a, 0x0a,
+	0x2e, 0x53, 0x6c, 0x65, 0x65, 0x70, 0x52, 0x65, 0x73, 0x70, 0x22, 0x0b, 0x82, 0x80, 0x19, 0x07,
+	0x0a, 0x05, 0x32, 0x30, 0x30, 0x6d, 0x73, 0x12, 0x33, 0x0a, 0x0b, 0x53, 0x6c, 0x65, 0x65, 0x70,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x09, 0x2e, 0x53, 0x6c, 0x65, 0x65, 0x70, 0x52, 0x65,
+	0x71, 0x1a, 0x0a, 0x2e, 0x53, 0x6c, 0x65, 0x65, 0x70, 0x52, 0x65, 0x73, 0x70, 0x22, 0x0b, 0x82,
+	0x80, 0x19, 0x07, 0x0a, 0x05, 0x32, 0x30, 0x30, 0x6d, 0x73, 0x30, 0x01, 0x12, 0x29, 0x0a, 0x0e,
+	0x53, 0x6c, 0x65, 0x65, 0x70, 0x4e, 0x6f, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x09,
+	0x2e, 0x53, 0x6c, 0x65, 0x65, 0x70, 0x52, 0x65, 0x71, 0x1a, 0x0a, 0x2e, 0x53, 0x6c, 0x65, 0x65,
+	0x70, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x42, 0x12, 0x5a, 0x10, 0x2f, 0x6d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_method_timeouts_proto_rawDescOnce sync.Once
+	file_method_timeouts_proto_rawDescData = file_method_timeouts_proto_rawDesc
+)
+
+func file_method_timeouts_proto_rawDescGZIP() []byte {
+	file_method_timeouts_proto_rawDescOnce.Do(func() {
+		file_method_timeouts_proto_rawDescData = protoimpl.X.CompressGZIP(file_method_timeouts_proto_rawDescData)
+	})
+	return file_method_timeouts_proto_rawDescData
+}
+
+var file_method_timeouts_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_method_timeouts_proto_goTypes = []interface{}{
+	(*SleepReq)(nil),  // 0: SleepReq
+	(*SleepResp)(nil), // 1: SleepResp
+}
+var file_method_timeouts_proto_depIdxs = []int32{
+	0, // 0: Sleeper.Sleep:input_type -> SleepReq
+	0, // 1: Sleeper.SleepStream:input_type -> SleepReq
+	0, // 2: Sleeper.SleepNoTimeout:input_type -> SleepReq
+	1, // 3: Sleeper.Sleep:output_type -> SleepResp
+	1, // 4: Sleeper.SleepStream:output_type -> SleepResp
+	1, // 5: Sleeper.SleepNoTimeout:output_type -> SleepResp
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_method_timeouts_proto_init() }
+func file_method_timeouts_proto_init() {
+	if File_method_timeouts_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_method_timeouts_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SleepReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_method_timeouts_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SleepResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_method_timeouts_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_method_timeouts_proto_goTypes,
+		DependencyIndexes: file_method_timeouts_proto_depIdxs,
+		MessageInfos:      file_method_timeouts_proto_msgTypes,
+	}.Build()
+	File_method_timeouts_proto = out.File
+	file_method_timeouts_proto_rawDesc = nil
+	file_method_timeouts_proto_goTypes = nil
+	file_method_timeouts_proto_depIdxs = nil
+}
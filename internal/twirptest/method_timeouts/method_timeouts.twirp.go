@@ -0,0 +1,1080 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: method_timeouts.proto
+
+package method_timeouts
+
+import context "context"
+import fmt "fmt"
+import http "net/http"
+import io "io"
+import strconv "strconv"
+import time "time"
+
+import twirp "github.com/twitchtv/twirp"
+import ctxsetters "github.com/twitchtv/twirp/ctxsetters"
+import twirpruntime "github.com/twitchtv/twirp/twirpruntime"
+
+// Version compatibility assertion.
+// If the constant is not defined in the package, that likely means
+// the package needs to be updated to work with this generated code.
+// See https://twitchtv.github.io/twirp/docs/version_matrix.html
+const _ = twirp.TwirpPackageMinVersion_8_3_0
+
+// =================
+// Sleeper Interface
+// =================
+
+type Sleeper interface {
+	Sleep(context.Context, *SleepReq) (*SleepResp, error)
+
+	SleepStream(context.Context, *SleepReq, SleeperSleepStreamServerStream) error
+
+	SleepNoTimeout(context.Context, *SleepReq) (*SleepResp, error)
+}
+
+// SleeperClient is the interface implemented by Sleeper clients. Unary methods
+// are the same as in the Sleeper interface, server-streaming methods return a stream
+// to read the response messages.
+type SleeperClient interface {
+	Sleep(context.Context, *SleepReq) (*SleepResp, error)
+
+	SleepStream(context.Context, *SleepReq) (SleeperSleepStreamClientStream, error)
+
+	SleepNoTimeout(context.Context, *SleepReq) (*SleepResp, error)
+}
+
+// SleeperSleepStreamServerStream is used by implementations of Sleeper.SleepStream to send response messages.
+type SleeperSleepStreamServerStream interface {
+	// Send sends a message to the client. If it returns an error (e.g. the client is gone),
+	// the method should stop and return.
+	Send(*SleepResp) error
+}
+
+type sleeperSleepStreamServerStream struct {
+	*twirpruntime.ServerStream
+}
+
+func (s *sleeperSleepStreamServerStream) Send(msg *SleepResp) error {
+	return s.ServerStream.Send(msg)
+}
+
+// SleeperSleepStreamClientStream is returned by SleeperClient.SleepStream to read response messages:
+//
+//	for stream.Next() {
+//	  msg := stream.Msg()
+//	}
+//	if err := stream.Err(); err != nil {
+//	  // handle error
+//	}
+type SleeperSleepStreamClientStream interface {
+	// Next reads the next message, available with Msg. It returns false when the
+	// stream is over, either successfully or with an error (see Err).
+	Next() bool
+
+	// Msg returns the message read by the last call to Next.
+	Msg() *SleepResp
+
+	// Err returns the error that ended the stream, or nil if it ended successfully.
+	Err() error
+
+	// Close stops reading the stream. It must be called if the stream is not read
+	// until Next returns false, to release the connection.
+	Close() error
+}
+
+type sleeperSleepStreamClientStream struct {
+	*twirpruntime.ClientStream // provides Err and Close
+	msg                        *SleepResp
+}
+
+func (s *sleeperSleepStreamClientStream) Next() bool {
+	msg := new(SleepResp)
+	if !s.Recv(msg) {
+		return false
+	}
+	s.msg = msg
+	return true
+}
+
+func (s *sleeperSleepStreamClientStream) Msg() *SleepResp { return s.msg }
+
+// =======================
+// Sleeper Protobuf Client
+// =======================
+
+type sleeperProtobufClient struct {
+	client            HTTPClient
+	urls              [3]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // Protobuf unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+}
+
+// NewSleeperProtobufClient creates a Protobuf client that implements the SleeperClient interface.
+// It communicates using Protobuf and can be configured with a custom HTTPClient.
+func NewSleeperProtobufClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) SleeperClient {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ProtobufCodec
+	_ = clientOpts.ReadOpt("codec", &codec)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "", "Sleeper")
+	urls := [3]string{
+		serviceURL + "Sleep",
+		serviceURL + "SleepStream",
+		serviceURL + "SleepNoTimeout",
+	}
+
+	return &sleeperProtobufClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+func (c *sleeperProtobufClient) Sleep(ctx context.Context, in *SleepReq) (*SleepResp, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Sleeper")
+	ctx = ctxsetters.WithMethodName(ctx, "Sleep")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Sleep"])
+	caller := c.callSleep
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return c.callSleep(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SleepResp)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SleepResp) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *sleeperProtobufClient) callSleep(ctx context.Context, in *SleepReq) (*SleepResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond) // (twirp.method).timeout option, unless ctx has an earlier deadline
+	defer cancel()
+	out := new(SleepResp)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *sleeperProtobufClient) SleepStream(ctx context.Context, in *SleepReq) (SleeperSleepStreamClientStream, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Sleeper")
+	ctx = ctxsetters.WithMethodName(ctx, "SleepStream")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["SleepStream"])
+	caller := c.callSleepStream
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SleepReq) (SleeperSleepStreamClientStream, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return c.callSleepStream(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(SleeperSleepStreamClientStream)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(SleeperSleepStreamClientStream) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *sleeperProtobufClient) callSleepStream(ctx context.Context, in *SleepReq) (SleeperSleepStreamClientStream, error) {
+	ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond) // (twirp.method).timeout option, unless ctx has an earlier deadline
+	ctx, stream, err := twirpruntime.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		cancel()
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+	stream.SetCancel(cancel) // the timeout applies to the whole stream
+	return &sleeperSleepStreamClientStream{ClientStream: stream}, nil
+}
+
+func (c *sleeperProtobufClient) SleepNoTimeout(ctx context.Context, in *SleepReq) (*SleepResp, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Sleeper")
+	ctx = ctxsetters.WithMethodName(ctx, "SleepNoTimeout")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["SleepNoTimeout"])
+	caller := c.callSleepNoTimeout
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return c.callSleepNoTimeout(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SleepResp)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SleepResp) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *sleeperProtobufClient) callSleepNoTimeout(ctx context.Context, in *SleepReq) (*SleepResp, error) {
+	out := new(SleepResp)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[2], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *sleeperProtobufClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// ===================
+// Sleeper JSON Client
+// ===================
+
+type sleeperJSONClient struct {
+	client            HTTPClient
+	urls              [3]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // JSON unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+}
+
+// NewSleeperJSONClient creates a JSON client that implements the SleeperClient interface.
+// It communicates using JSON and can be configured with a custom HTTPClient.
+func NewSleeperJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) SleeperClient {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ReadJSONCodec(&clientOpts)
+	_ = clientOpts.ReadOpt("codec", &codec)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "", "Sleeper")
+	urls := [3]string{
+		serviceURL + "Sleep",
+		serviceURL + "SleepStream",
+		serviceURL + "SleepNoTimeout",
+	}
+
+	return &sleeperJSONClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+func (c *sleeperJSONClient) Sleep(ctx context.Context, in *SleepReq) (*SleepResp, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Sleeper")
+	ctx = ctxsetters.WithMethodName(ctx, "Sleep")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Sleep"])
+	caller := c.callSleep
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return c.callSleep(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SleepResp)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SleepResp) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *sleeperJSONClient) callSleep(ctx context.Context, in *SleepReq) (*SleepResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond) // (twirp.method).timeout option, unless ctx has an earlier deadline
+	defer cancel()
+	out := new(SleepResp)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *sleeperJSONClient) SleepStream(ctx context.Context, in *SleepReq) (SleeperSleepStreamClientStream, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Sleeper")
+	ctx = ctxsetters.WithMethodName(ctx, "SleepStream")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["SleepStream"])
+	caller := c.callSleepStream
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SleepReq) (SleeperSleepStreamClientStream, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return c.callSleepStream(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(SleeperSleepStreamClientStream)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(SleeperSleepStreamClientStream) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *sleeperJSONClient) callSleepStream(ctx context.Context, in *SleepReq) (SleeperSleepStreamClientStream, error) {
+	ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond) // (twirp.method).timeout option, unless ctx has an earlier deadline
+	ctx, stream, err := twirpruntime.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		cancel()
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+	stream.SetCancel(cancel) // the timeout applies to the whole stream
+	return &sleeperSleepStreamClientStream{ClientStream: stream}, nil
+}
+
+func (c *sleeperJSONClient) SleepNoTimeout(ctx context.Context, in *SleepReq) (*SleepResp, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Sleeper")
+	ctx = ctxsetters.WithMethodName(ctx, "SleepNoTimeout")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["SleepNoTimeout"])
+	caller := c.callSleepNoTimeout
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return c.callSleepNoTimeout(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SleepResp)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SleepResp) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *sleeperJSONClient) callSleepNoTimeout(ctx context.Context, in *SleepReq) (*SleepResp, error) {
+	out := new(SleepResp)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[2], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *sleeperJSONClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// ======================
+// Sleeper Server Handler
+// ======================
+
+type sleeperServer struct {
+	Sleeper
+	interceptor           twirp.Interceptor
+	hooks                 *twirp.ServerHooks
+	pathPrefix            string                                                 // prefix for routing
+	maxRequestBytes       int64                                                  // limit for request bodies, no limit if 0 or less
+	methodMaxRequestBytes map[string]int64                                       // per-method overrides of maxRequestBytes
+	compression           twirpruntime.Compression                               // request decompression and response compression
+	requestTimeouts       bool                                                   // apply timeouts from the Twirp-Timeout request header
+	maxRequestTimeout     time.Duration                                          // limit for timeouts from the Twirp-Timeout request header
+	panicHandler          func(context.Context, interface{}, []byte) twirp.Error // handles panics in methods, re-panic if nil
+	errorTransformer      func(context.Context, error) twirp.Error               // maps errors before they are sent, optional
+	codecs                map[string]twirp.Codec                                 // codecs by content type, including the built-in Protobuf and JSON codecs
+	methodDescriptors     twirpruntime.MethodDescriptors                         // added to request contexts
+}
+
+// NewSleeperServer builds a TwirpServer that can be used as an http.Handler to handle
+// HTTP requests that are routed to the right method in the provided svc implementation.
+// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
+func NewSleeperServer(svc Sleeper, opts ...interface{}) TwirpServer {
+	serverOpts := twirpruntime.NewServerOptions(opts)
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	var maxRequestBytes int64
+	_ = serverOpts.ReadOpt("maxRequestBytes", &maxRequestBytes)
+	var methodMaxRequestBytes map[string]int64
+	_ = serverOpts.ReadOpt("methodMaxRequestBytes", &methodMaxRequestBytes)
+	requestTimeouts := true
+	_ = serverOpts.ReadOpt("requestTimeouts", &requestTimeouts)
+	var maxRequestTimeout time.Duration
+	_ = serverOpts.ReadOpt("maxRequestTimeout", &maxRequestTimeout)
+	var panicHandler func(context.Context, interface{}, []byte) twirp.Error
+	_ = serverOpts.ReadOpt("panicHandler", &panicHandler)
+	var errorTransformer func(context.Context, error) twirp.Error
+	_ = serverOpts.ReadOpt("errorTransformer", &errorTransformer)
+	var pathPrefix string
+	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+
+	return &sleeperServer{
+		Sleeper:               svc,
+		hooks:                 serverOpts.Hooks,
+		interceptor:           twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:            pathPrefix,
+		maxRequestBytes:       maxRequestBytes,
+		methodMaxRequestBytes: methodMaxRequestBytes,
+		compression:           twirpruntime.ReadCompression(serverOpts),
+		requestTimeouts:       requestTimeouts,
+		maxRequestTimeout:     maxRequestTimeout,
+		panicHandler:          panicHandler,
+		errorTransformer:      errorTransformer,
+		codecs:                twirpruntime.ReadServerCodecs(serverOpts),
+		methodDescriptors:     twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// The error is first mapped by the error transformer, if any (see twirp.WithServerErrorTransformer).
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func (s *sleeperServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(ctx, resp, twirpruntime.TransformError(ctx, err, s.errorTransformer), s.hooks)
+}
+
+// handleRequestBodyError is used to handle error when the twirp server cannot read request
+func (s *sleeperServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if twerr, ok := err.(twirp.Error); ok {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if twerr := twirpruntime.BodyTooLargeTwirpError(err, "request "); twerr != nil {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if context.Canceled == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
+		return
+	}
+	if context.DeadlineExceeded == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
+		return
+	}
+	s.writeError(ctx, resp, twirp.WrapError(twirpruntime.MalformedRequestError(msg), err))
+}
+
+// requestBody returns the request body, decompressed if it has a Content-Encoding,
+// and limited to the max size configured for the method. It must be closed after reading.
+func (s *sleeperServer) requestBody(req *http.Request, method string) (io.ReadCloser, error) {
+	maxBytes := s.maxRequestBytes
+	if n, ok := s.methodMaxRequestBytes[method]; ok {
+		maxBytes = n
+	}
+	return twirpruntime.RequestBody(req, maxBytes, s.compression)
+}
+
+// SleeperPathPrefix is a convenience constant that may identify URL paths.
+// Should be used with caution, it only matches routes generated by Twirp Go clients,
+// with the default "/twirp" prefix and default CamelCase service and method names.
+// More info: https://twitchtv.github.io/twirp/docs/routing.html
+const SleeperPathPrefix = "/twirp/Sleeper/"
+
+func (s *sleeperServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if hooks, interceptor, ok := twirpruntime.MuxServerOptions(ctx); ok {
+		// Served by a twirp.Mux: apply the shared hooks and interceptors before the ones from this server
+		shared := *s
+		shared.hooks = twirp.ChainHooks(hooks, s.hooks)
+		shared.interceptor = twirp.ChainInterceptors(interceptor, s.interceptor)
+		s = &shared
+	}
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Sleeper")
+	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	ctx = twirpruntime.WithErrorDetailsAccepted(ctx, req) // only clients that send Twirp-Error-Details get error details
+
+	var err error
+	ctx, err = twirpruntime.CallRequestReceived(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	if req.Method != "POST" {
+		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
+	prefix, pkgService, method := twirpruntime.ParseTwirpPath(req.URL.Path)
+	if pkgService != "Sleeper" {
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+	if prefix != s.pathPrefix {
+		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	switch method {
+	case "Sleep":
+		s.serveSleep(ctx, resp, req)
+		return
+	case "SleepStream":
+		s.serveSleepStream(ctx, resp, req)
+		return
+	case "SleepNoTimeout":
+		s.serveSleepNoTimeout(ctx, resp, req)
+		return
+	default:
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+}
+
+func (s *sleeperServer) serveSleep(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveSleepCodec(ctx, resp, req, codec)
+}
+
+func (s *sleeperServer) serveSleepCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, _, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "Sleep")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["Sleep"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "Sleep")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(SleepReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	handler := s.Sleeper.Sleep
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return s.Sleeper.Sleep(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SleepResp)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SleepResp) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *SleepResp
+	func() {
+		ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond) // (twirp.method).timeout option
+		defer cancel()
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
+		respContent, err = handler(ctx, reqContent)
+		err = twirpruntime.MethodTimeoutError(ctx, err)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *SleepResp and nil error while calling Sleep. nil responses are not supported"))
+		return
+	}
+
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
+
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
+	}
+	twirpruntime.CallResponseSent(ctx, s.hooks)
+}
+
+func (s *sleeperServer) serveSleepStream(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveSleepStreamCodec(ctx, resp, req, codec)
+}
+
+func (s *sleeperServer) serveSleepStreamCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, _, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "SleepStream")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["SleepStream"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "SleepStream")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(SleepReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	stream := &sleeperSleepStreamServerStream{ServerStream: twirpruntime.NewServerStream(ctx, resp, s.hooks, s.panicHandler, s.errorTransformer, codec)}
+
+	handler := s.Sleeper.SleepStream
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *SleepReq, stream SleeperSleepStreamServerStream) error {
+			// Interceptors are called once per stream, the response is always nil
+			_, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return nil, s.Sleeper.SleepStream(ctx, typedReq, stream)
+				},
+			)(ctx, req)
+			return err
+		}
+	}
+
+	// Call service method, response messages are written by the stream
+	func() {
+		ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond) // (twirp.method).timeout option
+		defer cancel()
+		defer stream.ServerStream.EnsurePanicResponses(&err)
+		err = handler(ctx, reqContent, stream)
+		err = twirpruntime.MethodTimeoutError(ctx, err)
+	}()
+	stream.Finish(err)
+}
+
+func (s *sleeperServer) serveSleepNoTimeout(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveSleepNoTimeoutCodec(ctx, resp, req, codec)
+}
+
+func (s *sleeperServer) serveSleepNoTimeoutCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "SleepNoTimeout")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["SleepNoTimeout"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "SleepNoTimeout")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(SleepReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	handler := s.Sleeper.SleepNoTimeout
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SleepReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SleepReq) when calling interceptor")
+					}
+					return s.Sleeper.SleepNoTimeout(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SleepResp)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SleepResp) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *SleepResp
+	func() {
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
+		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *SleepResp and nil error while calling SleepNoTimeout. nil responses are not supported"))
+		return
+	}
+
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
+
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
+	}
+	twirpruntime.CallResponseSent(ctx, s.hooks)
+}
+
+func (s *sleeperServer) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+func (s *sleeperServer) ProtocGenTwirpVersion() string {
+	return "v8.3.0"
+}
+
+// AppliesMuxServerOptions marks servers that apply the hooks and interceptors shared by a twirp.Mux.
+func (s *sleeperServer) AppliesMuxServerOptions() {}
+
+// PathPrefix returns the base service path, in the form: "/<prefix>/<package>.<Service>/"
+// that is everything in a Twirp route except for the <Method>. This can be used for routing,
+// for example to identify the requests that are targeted to this service in a mux.
+func (s *sleeperServer) PathPrefix() string {
+	return twirpruntime.BaseServicePath(s.pathPrefix, "", "Sleeper")
+}
+
+// =====
+// Utils
+// =====
+
+// HTTPClient is the interface used by generated clients to send HTTP requests.
+// It is fulfilled by *(net/http).Client, which is sufficient for most users.
+// Users can provide their own implementation for special retry policies.
+//
+// HTTPClient implementations should not follow redirects. Redirects are
+// automatically disabled if *(net/http).Client is passed to client
+// constructors. See twirpruntime.WithoutRedirects for more details.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// TwirpServer is the interface generated server structs will support: they're
+// HTTP handlers with additional methods for accessing metadata about the
+// service. Those accessors are a low-level API for building reflection tools.
+// Most people can think of TwirpServers as just http.Handlers.
+type TwirpServer interface {
+	http.Handler
+
+	// ServiceDescriptor returns gzipped bytes describing the .proto file that
+	// this service was generated from. Once unzipped, the bytes can be
+	// unmarshalled as a
+	// google.golang.org/protobuf/types/descriptorpb.FileDescriptorProto.
+	//
+	// The returned integer is the index of this particular service within that
+	// FileDescriptorProto's 'Service' slice of ServiceDescriptorProtos. This is a
+	// low-level field, expected to be used for reflection.
+	ServiceDescriptor() ([]byte, int)
+
+	// ProtocGenTwirpVersion is the semantic version string of the version of
+	// twirp used to generate this file.
+	ProtocGenTwirpVersion() string
+
+	// PathPrefix returns the HTTP URL path prefix for all methods handled by this
+	// service. This can be used with an HTTP mux to route Twirp requests.
+	// The path prefix is in the form: "/<prefix>/<package>.<Service>/"
+	// that is, everything in a Twirp route except for the <Method> at the end.
+	PathPrefix() string
+}
+
+// WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).
+// Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func WriteError(resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(context.Background(), resp, err, nil)
+}
+
+var twirpFileDescriptor0 = []byte{
+	// 233 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xcd, 0x4d, 0x2d, 0xc9,
+	0xc8, 0x4f, 0x89, 0x2f, 0xc9, 0xcc, 0x4d, 0xcd, 0x2f, 0x2d, 0x29, 0xd6, 0x2b, 0x28, 0xca, 0x2f,
+	0xc9, 0x97, 0x12, 0x2e, 0x29, 0xcf, 0x2c, 0x2a, 0xd0, 0xcf, 0x2f, 0x28, 0xc9, 0xcc, 0xcf, 0x83,
+	0x0a, 0x2a, 0x05, 0x71, 0x71, 0x04, 0xe7, 0xa4, 0xa6, 0x16, 0x04, 0xa5, 0x16, 0x0a, 0xc9, 0x73,
+	0x71, 0xa7, 0x94, 0x16, 0x25, 0x82, 0xa4, 0xe3, 0x73, 0x8b, 0x25, 0x18, 0x15, 0x18, 0x35, 0x98,
+	0x83, 0xb8, 0x60, 0x42, 0xbe, 0xc5, 0x42, 0xaa, 0x5c, 0x7c, 0x99, 0xe9, 0x79, 0xf9, 0x45, 0xa9,
+	0xf1, 0xc9, 0xf9, 0x79, 0x25, 0xa9, 0x15, 0x25, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x1c, 0x41, 0xbc,
+	0x10, 0x51, 0x67, 0x88, 0xa0, 0x92, 0x0e, 0x17, 0x27, 0xd4, 0xcc, 0xe2, 0x02, 0xb0, 0xa1, 0xa9,
+	0x89, 0x29, 0x39, 0x99, 0x79, 0xa9, 0xc8, 0x86, 0x42, 0x85, 0x7c, 0x8b, 0x8d, 0xa6, 0x31, 0x72,
+	0xb1, 0x83, 0x95, 0xa7, 0x16, 0x09, 0x69, 0x73, 0xb1, 0x82, 0x99, 0x42, 0x9c, 0x7a, 0x30, 0x57,
+	0x49, 0x71, 0xe9, 0xc1, 0x0d, 0x53, 0xe2, 0x6e, 0x6a, 0x90, 0x64, 0xe7, 0x62, 0x35, 0x32, 0x30,
+	0xc8, 0x2d, 0x16, 0x32, 0xe6, 0xe2, 0x06, 0xcb, 0x04, 0x97, 0x14, 0xa5, 0x26, 0xe6, 0x12, 0xa3,
+	0xc5, 0x80, 0x51, 0x48, 0x93, 0x8b, 0x0f, 0x2c, 0xe7, 0x97, 0x1f, 0x02, 0x09, 0x1d, 0x5c, 0xfa,
+	0x18, 0x9c, 0x84, 0xa2, 0x04, 0xf4, 0xd1, 0x42, 0x32, 0x89, 0x0d, 0x1c, 0x6a, 0xc6, 0x80, 0x01,
+	0x00, 0xa2, 0xe6, 0x22, 0xd8, 0x63, 0x01, 0x00, 0x00,
+}
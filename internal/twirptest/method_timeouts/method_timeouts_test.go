@@ -0,0 +1,247 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package method_timeouts
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/descriptors"
+	"github.com/twitchtv/twirp/options"
+)
+
+type sleeper struct{}
+
+func (sleeper) sleep(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+	resp := &SleepResp{}
+	if deadline, ok := ctx.Deadline(); ok {
+		resp.DeadlineMs = time.Until(deadline).Milliseconds()
+	}
+	select {
+	case <-time.After(time.Duration(req.DurationMs) * time.Millisecond):
+	case <-ctx.Done():
+		if !req.IgnoreContext {
+			return nil, ctx.Err()
+		}
+		time.Sleep(time.Duration(req.DurationMs) * time.Millisecond)
+	}
+	return resp, nil
+}
+
+func (s sleeper) Sleep(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+	return s.sleep(ctx, req)
+}
+
+func (s sleeper) SleepNoTimeout(ctx context.Context, req *SleepReq) (*SleepResp, error) {
+	return s.sleep(ctx, req)
+}
+
+func (s sleeper) SleepStream(ctx context.Context, req *SleepReq, stream SleeperSleepStreamServerStream) error {
+	if err := stream.Send(&SleepResp{}); err != nil {
+		return err
+	}
+	resp, err := s.sleep(ctx, req)
+	if err != nil {
+		return err
+	}
+	return stream.Send(resp)
+}
+
+func newClients(url string) map[string]SleeperClient {
+	return map[string]SleeperClient{
+		"protobuf": NewSleeperProtobufClient(url, http.DefaultClient),
+		"json":     NewSleeperJSONClient(url, http.DefaultClient),
+	}
+}
+
+// post sends a JSON request to the server, without the client timeout of generated
+// clients, and returns the response body.
+func post(t *testing.T, url, method, body string) string {
+	resp, err := http.Post(url+"/twirp/Sleeper/"+method, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("%s err=%q", method, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s err=%q", method, err)
+	}
+	return strings.ReplaceAll(string(respBody), " ", "")
+}
+
+func TestServerMethodTimeout(t *testing.T) {
+	s := httptest.NewServer(NewSleeperServer(sleeper{}))
+	defer s.Close()
+
+	resp := &SleepResp{}
+	if err := protojson.Unmarshal([]byte(post(t, s.URL, "Sleep", `{}`)), resp); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if resp.DeadlineMs <= 0 || resp.DeadlineMs > 200 {
+		t.Errorf("expected the method timeout on the server context, have %dms left", resp.DeadlineMs)
+	}
+
+	if body := post(t, s.URL, "Sleep", `{"duration_ms":5000}`); !strings.Contains(body, `"code":"deadline_exceeded"`) {
+		t.Errorf("expected deadline_exceeded error, have %s", body)
+	}
+
+	// enforced even if the method does not stop
+	if body := post(t, s.URL, "Sleep", `{"duration_ms":300,"ignore_context":true}`); !strings.Contains(body, `"code":"deadline_exceeded"`) {
+		t.Errorf("expected deadline_exceeded error, have %s", body)
+	}
+
+	resp = &SleepResp{}
+	if err := protojson.Unmarshal([]byte(post(t, s.URL, "SleepNoTimeout", `{"duration_ms":300}`)), resp); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if resp.DeadlineMs != 0 {
+		t.Errorf("expected no deadline on the server context, have %dms left", resp.DeadlineMs)
+	}
+}
+
+func TestServerMethodTimeoutStream(t *testing.T) {
+	s := httptest.NewServer(NewSleeperServer(sleeper{}))
+	defer s.Close()
+
+	body := post(t, s.URL, "SleepStream", `{"duration_ms":5000}`)
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"code":"deadline_exceeded"`) {
+		t.Errorf("expected a message and a deadline_exceeded error frame, have %s", body)
+	}
+}
+
+func TestClientMethodTimeoutStream(t *testing.T) {
+	s := httptest.NewServer(NewSleeperServer(sleeper{}))
+	defer s.Close()
+
+	for name, client := range newClients(s.URL) {
+		t.Run(name, func(t *testing.T) {
+			stream, err := client.SleepStream(context.Background(), &SleepReq{DurationMs: 1000, IgnoreContext: true})
+			if err != nil {
+				t.Fatalf("SleepStream err=%q", err)
+			}
+			start := time.Now()
+			if !stream.Next() {
+				t.Fatalf("expected a first message, err=%v", stream.Err())
+			}
+			if stream.Next() {
+				t.Fatal("expected the stream to end after the timeout")
+			}
+			if stream.Err() == nil || time.Since(start) > 500*time.Millisecond {
+				t.Errorf("expected the stream to fail on the client timeout, have err=%v after %v", stream.Err(), time.Since(start))
+			}
+		})
+	}
+}
+
+func TestClientMethodTimeout(t *testing.T) {
+	var header string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Twirp-Timeout")
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		if r.Header.Get("Content-Type") == "application/json" {
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	defer s.Close()
+
+	timeoutMs := func() int {
+		ms, err := strconv.Atoi(header)
+		if err != nil {
+			t.Fatalf("invalid Twirp-Timeout header %q", header)
+		}
+		return ms
+	}
+
+	for name, client := range newClients(s.URL) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := client.Sleep(context.Background(), &SleepReq{}); err != nil {
+				t.Fatalf("Sleep err=%q", err)
+			}
+			if ms := timeoutMs(); ms <= 100 || ms > 200 {
+				t.Errorf("expected the method timeout in the request, have %dms", ms)
+			}
+
+			// an earlier deadline is kept
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			if _, err := client.Sleep(ctx, &SleepReq{}); err != nil {
+				t.Fatalf("Sleep err=%q", err)
+			}
+			if ms := timeoutMs(); ms > 50 {
+				t.Errorf("expected the earlier deadline in the request, have %dms", ms)
+			}
+
+			// a later deadline is reduced to the method timeout
+			ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+			defer cancel()
+			if _, err := client.Sleep(ctx, &SleepReq{}); err != nil {
+				t.Fatalf("Sleep err=%q", err)
+			}
+			if ms := timeoutMs(); ms > 200 {
+				t.Errorf("expected the method timeout in the request, have %dms", ms)
+			}
+
+			header = ""
+			if _, err := client.SleepNoTimeout(context.Background(), &SleepReq{}); err != nil {
+				t.Fatalf("SleepNoTimeout err=%q", err)
+			}
+			if header != "" {
+				t.Errorf("expected no Twirp-Timeout header, have %q", header)
+			}
+		})
+	}
+}
+
+func TestMethodOptionsFromContext(t *testing.T) {
+	timeouts := make(chan string, 1)
+	interceptor := func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			md, ok := descriptors.MethodDescriptorFromContext(ctx)
+			if !ok {
+				t.Error("expected a method descriptor in the context")
+				return next(ctx, req)
+			}
+			opts, _ := proto.GetExtension(md.Options(), options.E_Method).(*options.MethodOptions)
+			timeouts <- opts.GetTimeout()
+			return next(ctx, req)
+		}
+	}
+	s := httptest.NewServer(NewSleeperServer(sleeper{}, twirp.WithServerInterceptors(interceptor)))
+	defer s.Close()
+	client := NewSleeperProtobufClient(s.URL, http.DefaultClient)
+
+	if _, err := client.Sleep(context.Background(), &SleepReq{}); err != nil {
+		t.Fatalf("Sleep err=%q", err)
+	}
+	if have := <-timeouts; have != "200ms" {
+		t.Errorf("expected the timeout option of Sleep, have %q", have)
+	}
+	if _, err := client.SleepNoTimeout(context.Background(), &SleepReq{}); err != nil {
+		t.Fatalf("SleepNoTimeout err=%q", err)
+	}
+	if have := <-timeouts; have != "" {
+		t.Errorf("expected no timeout option on SleepNoTimeout, have %q", have)
+	}
+}
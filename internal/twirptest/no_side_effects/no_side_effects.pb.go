@@ -0,0 +1,221 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0-devel
+// 	protoc        v3.21.8
+// source: no_side_effects.proto
+
+package no_side_effects
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetItemReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetItemReq) Reset() {
+	*x = GetItemReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_no_side_effects_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetItemReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetItemReq) ProtoMessage() {}
+
+func (x *GetItemReq) ProtoReflect() protoreflect.Message {
+	mi := &file_no_side_effects_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetItemReq.ProtoReflect.Descriptor instead.
+func (*GetItemReq) Descriptor() ([]byte, []int) {
+	return file_no_side_effects_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetItemReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_no_side_effects_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_no_side_effects_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_no_side_effects_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Item) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Item) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+var File_no_side_effects_proto protoreflect.FileDescriptor
+
+var file_no_side_effects_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x6e, 0x6f, 0x5f, 0x73, 0x69, 0x64, 0x65, 0x5f, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x1c, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x49, 0x74,
+	0x65, 0x6d, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2a, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x32, 0x4b, 0x0a, 0x07, 0x43, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x12, 0x22, 0x0a, 0x07,
+	0x47, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0b, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x74, 0x65,
+	0x6d, 0x52, 0x65, 0x71, 0x1a, 0x05, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x22, 0x03, 0x90, 0x02, 0x01,
+	0x12, 0x1c, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x05,
+	0x2e, 0x49, 0x74, 0x65, 0x6d, 0x1a, 0x05, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x22, 0x00, 0x42, 0x12,
+	0x5a, 0x10, 0x2f, 0x6e, 0x6f, 0x5f, 0x73, 0x69, 0x64, 0x65, 0x5f, 0x65, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_no_side_effects_proto_rawDescOnce sync.Once
+	file_no_side_effects_proto_rawDescData = file_no_side_effects_proto_rawDesc
+)
+
+func file_no_side_effects_proto_rawDescGZIP() []byte {
+	file_no_side_effects_proto_rawDescOnce.Do(func() {
+		file_no_side_effects_proto_rawDescData = protoimpl.X.CompressGZIP(file_no_side_effects_proto_rawDescData)
+	})
+	return file_no_side_effects_proto_rawDescData
+}
+
+var file_no_side_effects_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_no_side_effects_proto_goTypes = []interface{}{
+	(*GetItemReq)(nil), // 0: GetItemReq
+	(*Item)(nil),       // 1: Item
+}
+var file_no_side_effects_proto_depIdxs = []int32{
+	0, // 0: Catalog.GetItem:input_type -> GetItemReq
+	1, // 1: Catalog.UpdateItem:input_type -> Item
+	1, // 2: Catalog.GetItem:output_type -> Item
+	1, // 3: Catalog.UpdateItem:output_type -> Item
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_no_side_effects_proto_init() }
+func file_no_side_effects_proto_init() {
+	if File_no_side_effects_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_no_side_effects_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetItemReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_no_side_effects_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Item); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_no_side_effects_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_no_side_effects_proto_goTypes,
+		DependencyIndexes: file_no_side_effects_proto_depIdxs,
+		MessageInfos:      file_no_side_effects_proto_msgTypes,
+	}.Build()
+	File_no_side_effects_proto = out.File
+	file_no_side_effects_proto_rawDesc = nil
+	file_no_side_effects_proto_goTypes = nil
+	file_no_side_effects_proto_depIdxs = nil
+}
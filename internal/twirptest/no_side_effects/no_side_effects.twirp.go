@@ -0,0 +1,849 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: no_side_effects.proto
+
+package no_side_effects
+
+import context "context"
+import fmt "fmt"
+import http "net/http"
+import io "io"
+import strconv "strconv"
+import time "time"
+
+import twirp "github.com/twitchtv/twirp"
+import ctxsetters "github.com/twitchtv/twirp/ctxsetters"
+import twirpruntime "github.com/twitchtv/twirp/twirpruntime"
+
+// Version compatibility assertion.
+// If the constant is not defined in the package, that likely means
+// the package needs to be updated to work with this generated code.
+// See https://twitchtv.github.io/twirp/docs/version_matrix.html
+const _ = twirp.TwirpPackageMinVersion_8_3_0
+
+// =================
+// Catalog Interface
+// =================
+
+type Catalog interface {
+	GetItem(context.Context, *GetItemReq) (*Item, error)
+
+	UpdateItem(context.Context, *Item) (*Item, error)
+}
+
+// =======================
+// Catalog Protobuf Client
+// =======================
+
+type catalogProtobufClient struct {
+	client            HTTPClient
+	urls              [2]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // Protobuf unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+	httpGet           bool                           // use GET requests for methods without side effects
+}
+
+// NewCatalogProtobufClient creates a Protobuf client that implements the Catalog interface.
+// It communicates using Protobuf and can be configured with a custom HTTPClient.
+func NewCatalogProtobufClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) Catalog {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ProtobufCodec
+	_ = clientOpts.ReadOpt("codec", &codec)
+	httpGet := false
+	_ = clientOpts.ReadOpt("httpGet", &httpGet)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "", "Catalog")
+	urls := [2]string{
+		serviceURL + "GetItem",
+		serviceURL + "UpdateItem",
+	}
+
+	return &catalogProtobufClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+		httpGet:           httpGet,
+	}
+}
+
+func (c *catalogProtobufClient) GetItem(ctx context.Context, in *GetItemReq) (*Item, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Catalog")
+	ctx = ctxsetters.WithMethodName(ctx, "GetItem")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["GetItem"])
+	caller := c.callGetItem
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *GetItemReq) (*Item, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*GetItemReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*GetItemReq) when calling interceptor")
+					}
+					return c.callGetItem(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Item)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Item) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *catalogProtobufClient) callGetItem(ctx context.Context, in *GetItemReq) (*Item, error) {
+	out := new(Item)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, c.httpGet)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *catalogProtobufClient) UpdateItem(ctx context.Context, in *Item) (*Item, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Catalog")
+	ctx = ctxsetters.WithMethodName(ctx, "UpdateItem")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["UpdateItem"])
+	caller := c.callUpdateItem
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *Item) (*Item, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*Item)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*Item) when calling interceptor")
+					}
+					return c.callUpdateItem(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Item)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Item) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *catalogProtobufClient) callUpdateItem(ctx context.Context, in *Item) (*Item, error) {
+	out := new(Item)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *catalogProtobufClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// ===================
+// Catalog JSON Client
+// ===================
+
+type catalogJSONClient struct {
+	client            HTTPClient
+	urls              [2]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // JSON unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+	httpGet           bool                           // use GET requests for methods without side effects
+}
+
+// NewCatalogJSONClient creates a JSON client that implements the Catalog interface.
+// It communicates using JSON and can be configured with a custom HTTPClient.
+func NewCatalogJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) Catalog {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ReadJSONCodec(&clientOpts)
+	_ = clientOpts.ReadOpt("codec", &codec)
+	httpGet := false
+	_ = clientOpts.ReadOpt("httpGet", &httpGet)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "", "Catalog")
+	urls := [2]string{
+		serviceURL + "GetItem",
+		serviceURL + "UpdateItem",
+	}
+
+	return &catalogJSONClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+		httpGet:           httpGet,
+	}
+}
+
+func (c *catalogJSONClient) GetItem(ctx context.Context, in *GetItemReq) (*Item, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Catalog")
+	ctx = ctxsetters.WithMethodName(ctx, "GetItem")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["GetItem"])
+	caller := c.callGetItem
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *GetItemReq) (*Item, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*GetItemReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*GetItemReq) when calling interceptor")
+					}
+					return c.callGetItem(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Item)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Item) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *catalogJSONClient) callGetItem(ctx context.Context, in *GetItemReq) (*Item, error) {
+	out := new(Item)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, c.httpGet)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *catalogJSONClient) UpdateItem(ctx context.Context, in *Item) (*Item, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Catalog")
+	ctx = ctxsetters.WithMethodName(ctx, "UpdateItem")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["UpdateItem"])
+	caller := c.callUpdateItem
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *Item) (*Item, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*Item)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*Item) when calling interceptor")
+					}
+					return c.callUpdateItem(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Item)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Item) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *catalogJSONClient) callUpdateItem(ctx context.Context, in *Item) (*Item, error) {
+	out := new(Item)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *catalogJSONClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// ======================
+// Catalog Server Handler
+// ======================
+
+type catalogServer struct {
+	Catalog
+	interceptor           twirp.Interceptor
+	hooks                 *twirp.ServerHooks
+	pathPrefix            string                                                 // prefix for routing
+	maxRequestBytes       int64                                                  // limit for request bodies, no limit if 0 or less
+	methodMaxRequestBytes map[string]int64                                       // per-method overrides of maxRequestBytes
+	compression           twirpruntime.Compression                               // request decompression and response compression
+	requestTimeouts       bool                                                   // apply timeouts from the Twirp-Timeout request header
+	maxRequestTimeout     time.Duration                                          // limit for timeouts from the Twirp-Timeout request header
+	panicHandler          func(context.Context, interface{}, []byte) twirp.Error // handles panics in methods, re-panic if nil
+	errorTransformer      func(context.Context, error) twirp.Error               // maps errors before they are sent, optional
+	codecs                map[string]twirp.Codec                                 // codecs by content type, including the built-in Protobuf and JSON codecs
+	methodDescriptors     twirpruntime.MethodDescriptors                         // added to request contexts
+}
+
+// NewCatalogServer builds a TwirpServer that can be used as an http.Handler to handle
+// HTTP requests that are routed to the right method in the provided svc implementation.
+// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
+func NewCatalogServer(svc Catalog, opts ...interface{}) TwirpServer {
+	serverOpts := twirpruntime.NewServerOptions(opts)
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	var maxRequestBytes int64
+	_ = serverOpts.ReadOpt("maxRequestBytes", &maxRequestBytes)
+	var methodMaxRequestBytes map[string]int64
+	_ = serverOpts.ReadOpt("methodMaxRequestBytes", &methodMaxRequestBytes)
+	requestTimeouts := true
+	_ = serverOpts.ReadOpt("requestTimeouts", &requestTimeouts)
+	var maxRequestTimeout time.Duration
+	_ = serverOpts.ReadOpt("maxRequestTimeout", &maxRequestTimeout)
+	var panicHandler func(context.Context, interface{}, []byte) twirp.Error
+	_ = serverOpts.ReadOpt("panicHandler", &panicHandler)
+	var errorTransformer func(context.Context, error) twirp.Error
+	_ = serverOpts.ReadOpt("errorTransformer", &errorTransformer)
+	var pathPrefix string
+	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+
+	return &catalogServer{
+		Catalog:               svc,
+		hooks:                 serverOpts.Hooks,
+		interceptor:           twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:            pathPrefix,
+		maxRequestBytes:       maxRequestBytes,
+		methodMaxRequestBytes: methodMaxRequestBytes,
+		compression:           twirpruntime.ReadCompression(serverOpts),
+		requestTimeouts:       requestTimeouts,
+		maxRequestTimeout:     maxRequestTimeout,
+		panicHandler:          panicHandler,
+		errorTransformer:      errorTransformer,
+		codecs:                twirpruntime.ReadServerCodecs(serverOpts),
+		methodDescriptors:     twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// The error is first mapped by the error transformer, if any (see twirp.WithServerErrorTransformer).
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func (s *catalogServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(ctx, resp, twirpruntime.TransformError(ctx, err, s.errorTransformer), s.hooks)
+}
+
+// handleRequestBodyError is used to handle error when the twirp server cannot read request
+func (s *catalogServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if twerr, ok := err.(twirp.Error); ok {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if twerr := twirpruntime.BodyTooLargeTwirpError(err, "request "); twerr != nil {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if context.Canceled == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
+		return
+	}
+	if context.DeadlineExceeded == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
+		return
+	}
+	s.writeError(ctx, resp, twirp.WrapError(twirpruntime.MalformedRequestError(msg), err))
+}
+
+// requestBody returns the request body, decompressed if it has a Content-Encoding,
+// and limited to the max size configured for the method. It must be closed after reading.
+func (s *catalogServer) requestBody(req *http.Request, method string) (io.ReadCloser, error) {
+	maxBytes := s.maxRequestBytes
+	if n, ok := s.methodMaxRequestBytes[method]; ok {
+		maxBytes = n
+	}
+	return twirpruntime.RequestBody(req, maxBytes, s.compression)
+}
+
+// CatalogPathPrefix is a convenience constant that may identify URL paths.
+// Should be used with caution, it only matches routes generated by Twirp Go clients,
+// with the default "/twirp" prefix and default CamelCase service and method names.
+// More info: https://twitchtv.github.io/twirp/docs/routing.html
+const CatalogPathPrefix = "/twirp/Catalog/"
+
+func (s *catalogServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if hooks, interceptor, ok := twirpruntime.MuxServerOptions(ctx); ok {
+		// Served by a twirp.Mux: apply the shared hooks and interceptors before the ones from this server
+		shared := *s
+		shared.hooks = twirp.ChainHooks(hooks, s.hooks)
+		shared.interceptor = twirp.ChainInterceptors(interceptor, s.interceptor)
+		s = &shared
+	}
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Catalog")
+	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	ctx = twirpruntime.WithErrorDetailsAccepted(ctx, req) // only clients that send Twirp-Error-Details get error details
+
+	var err error
+	ctx, err = twirpruntime.CallRequestReceived(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	if req.Method != "POST" && req.Method != "GET" {
+		msg := fmt.Sprintf("unsupported method %q (only POST and GET are allowed)", req.Method)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
+	prefix, pkgService, method := twirpruntime.ParseTwirpPath(req.URL.Path)
+	if pkgService != "Catalog" {
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+	if prefix != s.pathPrefix {
+		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	switch method {
+	case "GetItem":
+		s.serveGetItem(ctx, resp, req)
+		return
+	case "UpdateItem":
+		if req.Method != "POST" {
+			msg := fmt.Sprintf("unsupported method %q (only POST is allowed, the method may have side effects)", req.Method)
+			s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+			return
+		}
+		s.serveUpdateItem(ctx, resp, req)
+		return
+	default:
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+}
+
+func (s *catalogServer) serveGetItem(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	if req.Method == "GET" { // allowed for methods without side effects
+		var err error
+		if req, err = twirpruntime.RequestFromQuery(req); err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveGetItemCodec(ctx, resp, req, codec)
+}
+
+func (s *catalogServer) serveGetItemCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "GetItem")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["GetItem"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "GetItem")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(GetItemReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	handler := s.Catalog.GetItem
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *GetItemReq) (*Item, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*GetItemReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*GetItemReq) when calling interceptor")
+					}
+					return s.Catalog.GetItem(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Item)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Item) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *Item
+	func() {
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
+		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *Item and nil error while calling GetItem. nil responses are not supported"))
+		return
+	}
+
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
+
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
+	}
+	twirpruntime.CallResponseSent(ctx, s.hooks)
+}
+
+func (s *catalogServer) serveUpdateItem(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveUpdateItemCodec(ctx, resp, req, codec)
+}
+
+func (s *catalogServer) serveUpdateItemCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "UpdateItem")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["UpdateItem"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "UpdateItem")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(Item)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	handler := s.Catalog.UpdateItem
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *Item) (*Item, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*Item)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*Item) when calling interceptor")
+					}
+					return s.Catalog.UpdateItem(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Item)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Item) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *Item
+	func() {
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
+		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *Item and nil error while calling UpdateItem. nil responses are not supported"))
+		return
+	}
+
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
+
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
+	}
+	twirpruntime.CallResponseSent(ctx, s.hooks)
+}
+
+func (s *catalogServer) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+func (s *catalogServer) ProtocGenTwirpVersion() string {
+	return "v8.3.0"
+}
+
+// AppliesMuxServerOptions marks servers that apply the hooks and interceptors shared by a twirp.Mux.
+func (s *catalogServer) AppliesMuxServerOptions() {}
+
+// PathPrefix returns the base service path, in the form: "/<prefix>/<package>.<Service>/"
+// that is everything in a Twirp route except for the <Method>. This can be used for routing,
+// for example to identify the requests that are targeted to this service in a mux.
+func (s *catalogServer) PathPrefix() string {
+	return twirpruntime.BaseServicePath(s.pathPrefix, "", "Catalog")
+}
+
+// =====
+// Utils
+// =====
+
+// HTTPClient is the interface used by generated clients to send HTTP requests.
+// It is fulfilled by *(net/http).Client, which is sufficient for most users.
+// Users can provide their own implementation for special retry policies.
+//
+// HTTPClient implementations should not follow redirects. Redirects are
+// automatically disabled if *(net/http).Client is passed to client
+// constructors. See twirpruntime.WithoutRedirects for more details.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// TwirpServer is the interface generated server structs will support: they're
+// HTTP handlers with additional methods for accessing metadata about the
+// service. Those accessors are a low-level API for building reflection tools.
+// Most people can think of TwirpServers as just http.Handlers.
+type TwirpServer interface {
+	http.Handler
+
+	// ServiceDescriptor returns gzipped bytes describing the .proto file that
+	// this service was generated from. Once unzipped, the bytes can be
+	// unmarshalled as a
+	// google.golang.org/protobuf/types/descriptorpb.FileDescriptorProto.
+	//
+	// The returned integer is the index of this particular service within that
+	// FileDescriptorProto's 'Service' slice of ServiceDescriptorProtos. This is a
+	// low-level field, expected to be used for reflection.
+	ServiceDescriptor() ([]byte, int)
+
+	// ProtocGenTwirpVersion is the semantic version string of the version of
+	// twirp used to generate this file.
+	ProtocGenTwirpVersion() string
+
+	// PathPrefix returns the HTTP URL path prefix for all methods handled by this
+	// service. This can be used with an HTTP mux to route Twirp requests.
+	// The path prefix is in the form: "/<prefix>/<package>.<Service>/"
+	// that is, everything in a Twirp route except for the <Method> at the end.
+	PathPrefix() string
+}
+
+// WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).
+// Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func WriteError(resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(context.Background(), resp, err, nil)
+}
+
+var twirpFileDescriptor0 = []byte{
+	// 157 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xcd, 0xcb, 0x8f, 0x2f,
+	0xce, 0x4c, 0x49, 0x8d, 0x4f, 0x4d, 0x4b, 0x4b, 0x4d, 0x2e, 0x29, 0xd6, 0x2b, 0x28, 0xca, 0x2f,
+	0xc9, 0x57, 0x92, 0xe1, 0xe2, 0x72, 0x4f, 0x2d, 0xf1, 0x2c, 0x49, 0xcd, 0x0d, 0x4a, 0x2d, 0x14,
+	0xe2, 0xe3, 0x62, 0xca, 0x4c, 0x91, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x62, 0xca, 0x4c, 0x51,
+	0xd2, 0xe2, 0x62, 0x01, 0x49, 0xa1, 0x8b, 0x0b, 0x09, 0x71, 0xb1, 0xe4, 0x25, 0xe6, 0xa6, 0x4a,
+	0x30, 0x81, 0x45, 0xc0, 0x6c, 0x23, 0x6f, 0x2e, 0x76, 0xe7, 0xc4, 0x92, 0xc4, 0x9c, 0xfc, 0x74,
+	0x21, 0x25, 0x2e, 0x76, 0xa8, 0xa1, 0x42, 0xdc, 0x7a, 0x08, 0xe3, 0xa5, 0x58, 0xf5, 0x40, 0x2c,
+	0x25, 0xe6, 0x09, 0x4c, 0x8c, 0x42, 0x32, 0x5c, 0x5c, 0xa1, 0x05, 0x29, 0x89, 0x25, 0xa9, 0x60,
+	0x65, 0x10, 0x19, 0x98, 0x02, 0x06, 0x27, 0xa1, 0x28, 0x01, 0x7d, 0x34, 0x07, 0x27, 0xb1, 0x81,
+	0x5d, 0x6c, 0x0c, 0x18, 0x00, 0x56, 0x8e, 0xa7, 0x8d, 0xca, 0x00, 0x00, 0x00,
+}
@@ -0,0 +1,245 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package no_side_effects
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+)
+
+type catalog struct{}
+
+func (c *catalog) GetItem(ctx context.Context, req *GetItemReq) (*Item, error) {
+	if req.Id == "" {
+		return nil, twirp.RequiredArgumentError("id")
+	}
+	_ = twirp.SetHTTPResponseHeader(ctx, "Cache-Control", "public, max-age=60")
+	_ = twirp.SetHTTPResponseHeader(ctx, "ETag", `"`+req.Id+`"`)
+	return &Item{Id: req.Id, Name: "item " + req.Id}, nil
+}
+
+func (c *catalog) UpdateItem(ctx context.Context, req *Item) (*Item, error) {
+	return req, nil
+}
+
+// recordMethods returns an HTTPClient that records the HTTP method of each request.
+func recordMethods(methods *[]string) HTTPClient {
+	return &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		*methods = append(*methods, req.Method)
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestHTTPGetQueryString(t *testing.T) {
+	s := httptest.NewServer(NewCatalogServer(&catalog{}))
+	defer s.Close()
+
+	pbMsg, err := proto.Marshal(&GetItemReq{Id: "pb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		accept     string
+		wantStatus int
+		wantItem   *Item
+	}{
+		{
+			name:       "json",
+			query:      "encoding=json&message=" + url.QueryEscape(`{"id":"json"}`),
+			wantStatus: http.StatusOK,
+			wantItem:   &Item{Id: "json", Name: "item json"},
+		},
+		{
+			name:       "json by default",
+			query:      "message=" + url.QueryEscape(`{"id":"default"}`),
+			wantStatus: http.StatusOK,
+			wantItem:   &Item{Id: "default", Name: "item default"},
+		},
+		{
+			name:       "protobuf",
+			query:      "encoding=protobuf&message=" + base64.RawURLEncoding.EncodeToString(pbMsg),
+			accept:     "application/protobuf",
+			wantStatus: http.StatusOK,
+			wantItem:   &Item{Id: "pb", Name: "item pb"},
+		},
+		{
+			name:       "protobuf with padding",
+			query:      "encoding=protobuf&message=" + url.QueryEscape(base64.URLEncoding.EncodeToString(pbMsg)),
+			accept:     "application/protobuf",
+			wantStatus: http.StatusOK,
+			wantItem:   &Item{Id: "pb", Name: "item pb"},
+		},
+		{
+			name:       "empty message",
+			query:      "",
+			wantStatus: http.StatusBadRequest, // id is required
+		},
+		{
+			name:       "invalid base64",
+			query:      "encoding=protobuf&message=%21%21",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown encoding",
+			query:      "encoding=xml&message=foo",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(s.URL + "/twirp/Catalog/GetItem?" + tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("unexpected status, have=%d, want=%d, body=%s", resp.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantItem == nil {
+				return
+			}
+
+			item := &Item{}
+			if tt.accept == "application/protobuf" {
+				if ct := resp.Header.Get("Content-Type"); ct != "application/protobuf" {
+					t.Fatalf("unexpected Content-Type %q", ct)
+				}
+				err = proto.Unmarshal(body, item)
+			} else {
+				if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+					t.Fatalf("unexpected Content-Type %q", ct)
+				}
+				err = protojson.Unmarshal(body, item)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(item, tt.wantItem) {
+				t.Errorf("unexpected item, have=%v, want=%v", item, tt.wantItem)
+			}
+			if cc := resp.Header.Get("Cache-Control"); cc != "public, max-age=60" {
+				t.Errorf("unexpected Cache-Control header %q", cc)
+			}
+			if etag := resp.Header.Get("ETag"); etag != `"`+tt.wantItem.Id+`"` {
+				t.Errorf("unexpected ETag header %q", etag)
+			}
+		})
+	}
+}
+
+func TestHTTPGetRejectedForMethodsWithSideEffects(t *testing.T) {
+	s := httptest.NewServer(NewCatalogServer(&catalog{}))
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/twirp/Catalog/UpdateItem?message=" + url.QueryEscape(`{"id":"foo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status, have=%d, want=%d", resp.StatusCode, http.StatusNotFound)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "bad_route") {
+		t.Errorf("expected bad_route error, have %s", body)
+	}
+
+	req, _ := http.NewRequest("PUT", s.URL+"/twirp/Catalog/GetItem", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status for PUT, have=%d, want=%d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestClientHTTPGet(t *testing.T) {
+	s := httptest.NewServer(NewCatalogServer(&catalog{}))
+	defer s.Close()
+
+	clients := map[string]func(HTTPClient, ...twirp.ClientOption) Catalog{
+		"protobuf": func(hc HTTPClient, opts ...twirp.ClientOption) Catalog {
+			return NewCatalogProtobufClient(s.URL, hc, opts...)
+		},
+		"json": func(hc HTTPClient, opts ...twirp.ClientOption) Catalog {
+			return NewCatalogJSONClient(s.URL, hc, opts...)
+		},
+	}
+	for name, newClient := range clients {
+		t.Run(name, func(t *testing.T) {
+			var methods []string
+			client := newClient(recordMethods(&methods), twirp.WithClientHTTPGet(true))
+
+			item, err := client.GetItem(context.Background(), &GetItemReq{Id: "a/b?c=d&e"})
+			if err != nil {
+				t.Fatalf("GetItem err=%q", err)
+			}
+			if item.Name != "item a/b?c=d&e" {
+				t.Errorf("unexpected item name %q", item.Name)
+			}
+
+			_, err = client.GetItem(context.Background(), &GetItemReq{})
+			if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.InvalidArgument {
+				t.Errorf("expected invalid_argument error, have %v", err)
+			}
+
+			if _, err = client.UpdateItem(context.Background(), &Item{Id: "a"}); err != nil {
+				t.Fatalf("UpdateItem err=%q", err)
+			}
+
+			want := []string{"GET", "GET", "POST"}
+			if strings.Join(methods, ",") != strings.Join(want, ",") {
+				t.Errorf("unexpected HTTP methods, have=%v, want=%v", methods, want)
+			}
+		})
+	}
+}
+
+func TestClientHTTPGetDisabledByDefault(t *testing.T) {
+	s := httptest.NewServer(NewCatalogServer(&catalog{}))
+	defer s.Close()
+
+	var methods []string
+	client := NewCatalogProtobufClient(s.URL, recordMethods(&methods))
+	if _, err := client.GetItem(context.Background(), &GetItemReq{Id: "a"}); err != nil {
+		t.Fatalf("GetItem err=%q", err)
+	}
+	if len(methods) != 1 || methods[0] != "POST" {
+		t.Errorf("unexpected HTTP methods, have=%v, want=[POST]", methods)
+	}
+}
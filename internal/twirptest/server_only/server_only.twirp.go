@@ -0,0 +1,493 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: server_only.proto
+
+// Test generate=server, to generate only servers
+
+package server_only
+
+import context "context"
+import fmt "fmt"
+import http "net/http"
+import io "io"
+import strconv "strconv"
+import time "time"
+
+import twirp "github.com/twitchtv/twirp"
+import ctxsetters "github.com/twitchtv/twirp/ctxsetters"
+import twirpruntime "github.com/twitchtv/twirp/twirpruntime"
+
+// Version compatibility assertion.
+// If the constant is not defined in the package, that likely means
+// the package needs to be updated to work with this generated code.
+// See https://twitchtv.github.io/twirp/docs/version_matrix.html
+const _ = twirp.TwirpPackageMinVersion_8_3_0
+
+// =================
+// Counter Interface
+// =================
+
+type Counter interface {
+	// Count streams the numbers from 1 to CountReq.to.
+	Count(context.Context, *CountReq, CounterCountServerStream) error
+
+	// Get is a regular unary method, that can be called with GET requests.
+	Get(context.Context, *CountReq) (*Number, error)
+}
+
+// CounterCountServerStream is used by implementations of Counter.Count to send response messages.
+type CounterCountServerStream interface {
+	// Send sends a message to the client. If it returns an error (e.g. the client is gone),
+	// the method should stop and return.
+	Send(*Number) error
+}
+
+type counterCountServerStream struct {
+	*twirpruntime.ServerStream
+}
+
+func (s *counterCountServerStream) Send(msg *Number) error {
+	return s.ServerStream.Send(msg)
+}
+
+// ======================
+// Counter Server Handler
+// ======================
+
+type counterServer struct {
+	Counter
+	interceptor           twirp.Interceptor
+	hooks                 *twirp.ServerHooks
+	pathPrefix            string                                                 // prefix for routing
+	maxRequestBytes       int64                                                  // limit for request bodies, no limit if 0 or less
+	methodMaxRequestBytes map[string]int64                                       // per-method overrides of maxRequestBytes
+	compression           twirpruntime.Compression                               // request decompression and response compression
+	requestTimeouts       bool                                                   // apply timeouts from the Twirp-Timeout request header
+	maxRequestTimeout     time.Duration                                          // limit for timeouts from the Twirp-Timeout request header
+	panicHandler          func(context.Context, interface{}, []byte) twirp.Error // handles panics in methods, re-panic if nil
+	errorTransformer      func(context.Context, error) twirp.Error               // maps errors before they are sent, optional
+	codecs                map[string]twirp.Codec                                 // codecs by content type, including the built-in Protobuf and JSON codecs
+	methodDescriptors     twirpruntime.MethodDescriptors                         // added to request contexts
+}
+
+// NewCounterServer builds a TwirpServer that can be used as an http.Handler to handle
+// HTTP requests that are routed to the right method in the provided svc implementation.
+// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
+func NewCounterServer(svc Counter, opts ...interface{}) TwirpServer {
+	serverOpts := twirpruntime.NewServerOptions(opts)
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	var maxRequestBytes int64
+	_ = serverOpts.ReadOpt("maxRequestBytes", &maxRequestBytes)
+	var methodMaxRequestBytes map[string]int64
+	_ = serverOpts.ReadOpt("methodMaxRequestBytes", &methodMaxRequestBytes)
+	requestTimeouts := true
+	_ = serverOpts.ReadOpt("requestTimeouts", &requestTimeouts)
+	var maxRequestTimeout time.Duration
+	_ = serverOpts.ReadOpt("maxRequestTimeout", &maxRequestTimeout)
+	var panicHandler func(context.Context, interface{}, []byte) twirp.Error
+	_ = serverOpts.ReadOpt("panicHandler", &panicHandler)
+	var errorTransformer func(context.Context, error) twirp.Error
+	_ = serverOpts.ReadOpt("errorTransformer", &errorTransformer)
+	var pathPrefix string
+	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+
+	return &counterServer{
+		Counter:               svc,
+		hooks:                 serverOpts.Hooks,
+		interceptor:           twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:            pathPrefix,
+		maxRequestBytes:       maxRequestBytes,
+		methodMaxRequestBytes: methodMaxRequestBytes,
+		compression:           twirpruntime.ReadCompression(serverOpts),
+		requestTimeouts:       requestTimeouts,
+		maxRequestTimeout:     maxRequestTimeout,
+		panicHandler:          panicHandler,
+		errorTransformer:      errorTransformer,
+		codecs:                twirpruntime.ReadServerCodecs(serverOpts),
+		methodDescriptors:     twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// The error is first mapped by the error transformer, if any (see twirp.WithServerErrorTransformer).
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func (s *counterServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(ctx, resp, twirpruntime.TransformError(ctx, err, s.errorTransformer), s.hooks)
+}
+
+// handleRequestBodyError is used to handle error when the twirp server cannot read request
+func (s *counterServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if twerr, ok := err.(twirp.Error); ok {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if twerr := twirpruntime.BodyTooLargeTwirpError(err, "request "); twerr != nil {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if context.Canceled == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
+		return
+	}
+	if context.DeadlineExceeded == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
+		return
+	}
+	s.writeError(ctx, resp, twirp.WrapError(twirpruntime.MalformedRequestError(msg), err))
+}
+
+// requestBody returns the request body, decompressed if it has a Content-Encoding,
+// and limited to the max size configured for the method. It must be closed after reading.
+func (s *counterServer) requestBody(req *http.Request, method string) (io.ReadCloser, error) {
+	maxBytes := s.maxRequestBytes
+	if n, ok := s.methodMaxRequestBytes[method]; ok {
+		maxBytes = n
+	}
+	return twirpruntime.RequestBody(req, maxBytes, s.compression)
+}
+
+// CounterPathPrefix is a convenience constant that may identify URL paths.
+// Should be used with caution, it only matches routes generated by Twirp Go clients,
+// with the default "/twirp" prefix and default CamelCase service and method names.
+// More info: https://twitchtv.github.io/twirp/docs/routing.html
+const CounterPathPrefix = "/twirp/twirp.internal.twirptest.server_only.Counter/"
+
+func (s *counterServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if hooks, interceptor, ok := twirpruntime.MuxServerOptions(ctx); ok {
+		// Served by a twirp.Mux: apply the shared hooks and interceptors before the ones from this server
+		shared := *s
+		shared.hooks = twirp.ChainHooks(hooks, s.hooks)
+		shared.interceptor = twirp.ChainInterceptors(interceptor, s.interceptor)
+		s = &shared
+	}
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.internal.twirptest.server_only")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	ctx = twirpruntime.WithErrorDetailsAccepted(ctx, req) // only clients that send Twirp-Error-Details get error details
+
+	var err error
+	ctx, err = twirpruntime.CallRequestReceived(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	if req.Method != "POST" && req.Method != "GET" {
+		msg := fmt.Sprintf("unsupported method %q (only POST and GET are allowed)", req.Method)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
+	prefix, pkgService, method := twirpruntime.ParseTwirpPath(req.URL.Path)
+	if pkgService != "twirp.internal.twirptest.server_only.Counter" {
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+	if prefix != s.pathPrefix {
+		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	switch method {
+	case "Count":
+		if req.Method != "POST" {
+			msg := fmt.Sprintf("unsupported method %q (only POST is allowed, the method may have side effects)", req.Method)
+			s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+			return
+		}
+		s.serveCount(ctx, resp, req)
+		return
+	case "Get":
+		s.serveGet(ctx, resp, req)
+		return
+	default:
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+}
+
+func (s *counterServer) serveCount(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveCountCodec(ctx, resp, req, codec)
+}
+
+func (s *counterServer) serveCountCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "Count")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["Count"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "Count")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(CountReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	stream := &counterCountServerStream{ServerStream: twirpruntime.NewServerStream(ctx, resp, s.hooks, s.panicHandler, s.errorTransformer, codec)}
+
+	handler := s.Counter.Count
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *CountReq, stream CounterCountServerStream) error {
+			// Interceptors are called once per stream, the response is always nil
+			_, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return nil, s.Counter.Count(ctx, typedReq, stream)
+				},
+			)(ctx, req)
+			return err
+		}
+	}
+
+	// Call service method, response messages are written by the stream
+	func() {
+		defer stream.ServerStream.EnsurePanicResponses(&err)
+		err = handler(ctx, reqContent, stream)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+	stream.Finish(err)
+}
+
+func (s *counterServer) serveGet(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	if req.Method == "GET" { // allowed for methods without side effects
+		var err error
+		if req, err = twirpruntime.RequestFromQuery(req); err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveGetCodec(ctx, resp, req, codec)
+}
+
+func (s *counterServer) serveGetCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "Get")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["Get"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "Get")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(CountReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	handler := s.Counter.Get
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *CountReq) (*Number, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return s.Counter.Get(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Number)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Number) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *Number
+	func() {
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
+		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *Number and nil error while calling Get. nil responses are not supported"))
+		return
+	}
+
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
+
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
+	}
+	twirpruntime.CallResponseSent(ctx, s.hooks)
+}
+
+func (s *counterServer) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+func (s *counterServer) ProtocGenTwirpVersion() string {
+	return "v8.3.0"
+}
+
+// AppliesMuxServerOptions marks servers that apply the hooks and interceptors shared by a twirp.Mux.
+func (s *counterServer) AppliesMuxServerOptions() {}
+
+// PathPrefix returns the base service path, in the form: "/<prefix>/<package>.<Service>/"
+// that is everything in a Twirp route except for the <Method>. This can be used for routing,
+// for example to identify the requests that are targeted to this service in a mux.
+func (s *counterServer) PathPrefix() string {
+	return twirpruntime.BaseServicePath(s.pathPrefix, "twirp.internal.twirptest.server_only", "Counter")
+}
+
+// =====
+// Utils
+// =====
+
+// TwirpServer is the interface generated server structs will support: they're
+// HTTP handlers with additional methods for accessing metadata about the
+// service. Those accessors are a low-level API for building reflection tools.
+// Most people can think of TwirpServers as just http.Handlers.
+type TwirpServer interface {
+	http.Handler
+
+	// ServiceDescriptor returns gzipped bytes describing the .proto file that
+	// this service was generated from. Once unzipped, the bytes can be
+	// unmarshalled as a
+	// google.golang.org/protobuf/types/descriptorpb.FileDescriptorProto.
+	//
+	// The returned integer is the index of this particular service within that
+	// FileDescriptorProto's 'Service' slice of ServiceDescriptorProtos. This is a
+	// low-level field, expected to be used for reflection.
+	ServiceDescriptor() ([]byte, int)
+
+	// ProtocGenTwirpVersion is the semantic version string of the version of
+	// twirp used to generate this file.
+	ProtocGenTwirpVersion() string
+
+	// PathPrefix returns the HTTP URL path prefix for all methods handled by this
+	// service. This can be used with an HTTP mux to route Twirp requests.
+	// The path prefix is in the form: "/<prefix>/<package>.<Service>/"
+	// that is, everything in a Twirp route except for the <Method> at the end.
+	PathPrefix() string
+}
+
+// WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).
+// Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func WriteError(resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(context.Background(), resp, err, nil)
+}
+
+var twirpFileDescriptor0 = []byte{
+	// 176 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2c, 0x4e, 0x2d, 0x2a,
+	0x4b, 0x2d, 0x8a, 0xcf, 0xcf, 0xcb, 0xa9, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x52, 0x29,
+	0x29, 0xcf, 0x2c, 0x2a, 0xd0, 0xcb, 0xcc, 0x2b, 0x49, 0x2d, 0xca, 0x4b, 0xcc, 0xd1, 0x03, 0x73,
+	0x4b, 0x52, 0x8b, 0x4b, 0xf4, 0x90, 0xd4, 0x2a, 0x49, 0x71, 0x71, 0x38, 0xe7, 0x97, 0xe6, 0x95,
+	0x04, 0xa5, 0x16, 0x0a, 0xf1, 0x71, 0x31, 0x95, 0xe4, 0x4b, 0x30, 0x2a, 0x30, 0x6a, 0xb0, 0x06,
+	0x31, 0x95, 0xe4, 0x2b, 0xc9, 0x71, 0xb1, 0xf9, 0x95, 0xe6, 0x26, 0xa5, 0x16, 0x09, 0x89, 0x70,
+	0xb1, 0x96, 0x25, 0xe6, 0x94, 0xa6, 0x42, 0x25, 0x21, 0x1c, 0xa3, 0x7b, 0x8c, 0x5c, 0xec, 0x60,
+	0xcd, 0xa9, 0x45, 0x42, 0x99, 0x5c, 0xac, 0x60, 0xa6, 0x90, 0x9e, 0x1e, 0x31, 0xf6, 0xea, 0xc1,
+	0x2c, 0x95, 0xd2, 0x21, 0x4e, 0x3d, 0xc4, 0x21, 0x4a, 0x0c, 0x06, 0x8c, 0x42, 0x19, 0x5c, 0xcc,
+	0xee, 0xa9, 0xb4, 0xb6, 0x88, 0x79, 0x02, 0x13, 0xa3, 0x13, 0x5f, 0x14, 0x8f, 0x3e, 0x92, 0x5c,
+	0x12, 0x1b, 0x38, 0x64, 0x8d, 0x01, 0x03, 0x00, 0xac, 0xb6, 0xb8, 0xbb, 0x6e, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server_only
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type counter struct{}
+
+func (counter) Count(ctx context.Context, req *CountReq, stream CounterCountServerStream) error {
+	for i := int32(1); i <= req.To; i++ {
+		if err := stream.Send(&Number{Value: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (counter) Get(ctx context.Context, req *CountReq) (*Number, error) {
+	return &Number{Value: req.To}, nil
+}
+
+// declarations returns the names of the top-level declarations in a Go file.
+func declarations(t *testing.T, filename string) map[string]bool {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", filename, err)
+	}
+	names := map[string]bool{}
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			names[decl.Name.Name] = true
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok {
+					names[spec.Name.Name] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestServerOnlyDeclarations(t *testing.T) {
+	names := declarations(t, "server_only.twirp.go")
+	for _, name := range []string{"NewCounterServer", "TwirpServer", "CounterCountServerStream", "writeError"} {
+		if !names[name] {
+			t.Errorf("expected %s to be generated", name)
+		}
+	}
+	for _, name := range []string{"CounterClient", "NewCounterProtobufClient", "NewCounterJSONClient", "HTTPClient", "doProtobufRequest", "CounterCountClientStream"} {
+		if names[name] {
+			t.Errorf("expected %s not to be generated", name)
+		}
+	}
+}
+
+func TestServerOnly(t *testing.T) {
+	s := httptest.NewServer(NewCounterServer(counter{}))
+	defer s.Close()
+	prefix := s.URL + CounterPathPrefix
+
+	t.Run("protobuf", func(t *testing.T) {
+		reqBody, _ := proto.Marshal(&CountReq{To: 3})
+		resp, err := http.Post(prefix+"Get", "application/protobuf", strings.NewReader(string(reqBody)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		n := &Number{}
+		if err := proto.Unmarshal(body, n); resp.StatusCode != 200 || err != nil || n.Value != 3 {
+			t.Fatalf("unexpected response status=%d, body=%v", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		resp, err := http.Get(prefix + "Get?message=" + url.QueryEscape(`{"to":3}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		if strings.ReplaceAll(string(body), " ", "") != `{"value":3}` {
+			t.Fatalf("unexpected response status=%d, body=%s", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		resp, err := http.Post(prefix+"Count", "application/json", strings.NewReader(`{"to":2}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		want := `{"message":{"value":1}}` + "\n" + `{"message":{"value":2}}` + "\n" + `{"end":true}` + "\n"
+		if strings.ReplaceAll(string(body), " ", "") != want {
+			t.Fatalf("unexpected JSON stream %q, want %q", body, want)
+		}
+	})
+}
@@ -0,0 +1,1132 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: server_streaming.proto
+
+package server_streaming
+
+import context "context"
+import fmt "fmt"
+import http "net/http"
+import io "io"
+import strconv "strconv"
+import time "time"
+
+import twirp "github.com/twitchtv/twirp"
+import ctxsetters "github.com/twitchtv/twirp/ctxsetters"
+import twirpruntime "github.com/twitchtv/twirp/twirpruntime"
+
+// Version compatibility assertion.
+// If the constant is not defined in the package, that likely means
+// the package needs to be updated to work with this generated code.
+// See https://twitchtv.github.io/twirp/docs/version_matrix.html
+const _ = twirp.TwirpPackageMinVersion_8_3_0
+
+// =================
+// Counter Interface
+// =================
+
+type Counter interface {
+	// Count streams the numbers from 1 to CountReq.to.
+	Count(context.Context, *CountReq, CounterCountServerStream) error
+
+	// Watch streams the numbers from 1 to CountReq.to, and can be called with GET requests.
+	Watch(context.Context, *CountReq, CounterWatchServerStream) error
+
+	// Get is a regular unary method.
+	Get(context.Context, *CountReq) (*Number, error)
+}
+
+// CounterClient is the interface implemented by Counter clients. Unary methods
+// are the same as in the Counter interface, server-streaming methods return a stream
+// to read the response messages.
+type CounterClient interface {
+	// Count streams the numbers from 1 to CountReq.to.
+	Count(context.Context, *CountReq) (CounterCountClientStream, error)
+
+	// Watch streams the numbers from 1 to CountReq.to, and can be called with GET requests.
+	Watch(context.Context, *CountReq) (CounterWatchClientStream, error)
+
+	// Get is a regular unary method.
+	Get(context.Context, *CountReq) (*Number, error)
+}
+
+// CounterCountServerStream is used by implementations of Counter.Count to send response messages.
+type CounterCountServerStream interface {
+	// Send sends a message to the client. If it returns an error (e.g. the client is gone),
+	// the method should stop and return.
+	Send(*Number) error
+}
+
+type counterCountServerStream struct {
+	*twirpruntime.ServerStream
+}
+
+func (s *counterCountServerStream) Send(msg *Number) error {
+	return s.ServerStream.Send(msg)
+}
+
+// CounterCountClientStream is returned by CounterClient.Count to read response messages:
+//
+//	for stream.Next() {
+//	  msg := stream.Msg()
+//	}
+//	if err := stream.Err(); err != nil {
+//	  // handle error
+//	}
+type CounterCountClientStream interface {
+	// Next reads the next message, available with Msg. It returns false when the
+	// stream is over, either successfully or with an error (see Err).
+	Next() bool
+
+	// Msg returns the message read by the last call to Next.
+	Msg() *Number
+
+	// Err returns the error that ended the stream, or nil if it ended successfully.
+	Err() error
+
+	// Close stops reading the stream. It must be called if the stream is not read
+	// until Next returns false, to release the connection.
+	Close() error
+}
+
+type counterCountClientStream struct {
+	*twirpruntime.ClientStream // provides Err and Close
+	msg                        *Number
+}
+
+func (s *counterCountClientStream) Next() bool {
+	msg := new(Number)
+	if !s.Recv(msg) {
+		return false
+	}
+	s.msg = msg
+	return true
+}
+
+func (s *counterCountClientStream) Msg() *Number { return s.msg }
+
+// CounterWatchServerStream is used by implementations of Counter.Watch to send response messages.
+type CounterWatchServerStream interface {
+	// Send sends a message to the client. If it returns an error (e.g. the client is gone),
+	// the method should stop and return.
+	Send(*Number) error
+}
+
+type counterWatchServerStream struct {
+	*twirpruntime.ServerStream
+}
+
+func (s *counterWatchServerStream) Send(msg *Number) error {
+	return s.ServerStream.Send(msg)
+}
+
+// CounterWatchClientStream is returned by CounterClient.Watch to read response messages:
+//
+//	for stream.Next() {
+//	  msg := stream.Msg()
+//	}
+//	if err := stream.Err(); err != nil {
+//	  // handle error
+//	}
+type CounterWatchClientStream interface {
+	// Next reads the next message, available with Msg. It returns false when the
+	// stream is over, either successfully or with an error (see Err).
+	Next() bool
+
+	// Msg returns the message read by the last call to Next.
+	Msg() *Number
+
+	// Err returns the error that ended the stream, or nil if it ended successfully.
+	Err() error
+
+	// Close stops reading the stream. It must be called if the stream is not read
+	// until Next returns false, to release the connection.
+	Close() error
+}
+
+type counterWatchClientStream struct {
+	*twirpruntime.ClientStream // provides Err and Close
+	msg                        *Number
+}
+
+func (s *counterWatchClientStream) Next() bool {
+	msg := new(Number)
+	if !s.Recv(msg) {
+		return false
+	}
+	s.msg = msg
+	return true
+}
+
+func (s *counterWatchClientStream) Msg() *Number { return s.msg }
+
+// ============================
+// Counter Unimplemented Server
+// ============================
+
+// UnimplementedCounterServer can be embedded in implementations of the Counter interface
+// for forward compatibility: methods that are not implemented return a twirp.Unimplemented
+// error, and new methods added to the service don't break the build.
+type UnimplementedCounterServer struct{}
+
+var _ Counter = UnimplementedCounterServer{}
+
+func (UnimplementedCounterServer) Count(context.Context, *CountReq, CounterCountServerStream) error {
+	return twirp.NewError(twirp.Unimplemented, "method Count is not implemented")
+}
+
+func (UnimplementedCounterServer) Watch(context.Context, *CountReq, CounterWatchServerStream) error {
+	return twirp.NewError(twirp.Unimplemented, "method Watch is not implemented")
+}
+
+func (UnimplementedCounterServer) Get(context.Context, *CountReq) (*Number, error) {
+	return nil, twirp.NewError(twirp.Unimplemented, "method Get is not implemented")
+}
+
+// =======================
+// Counter Protobuf Client
+// =======================
+
+type counterProtobufClient struct {
+	client            HTTPClient
+	urls              [3]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // Protobuf unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+	httpGet           bool                           // use GET requests for methods without side effects
+}
+
+// NewCounterProtobufClient creates a Protobuf client that implements the CounterClient interface.
+// It communicates using Protobuf and can be configured with a custom HTTPClient.
+func NewCounterProtobufClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) CounterClient {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ProtobufCodec
+	_ = clientOpts.ReadOpt("codec", &codec)
+	httpGet := false
+	_ = clientOpts.ReadOpt("httpGet", &httpGet)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "", "Counter")
+	urls := [3]string{
+		serviceURL + "Count",
+		serviceURL + "Watch",
+		serviceURL + "Get",
+	}
+
+	return &counterProtobufClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+		httpGet:           httpGet,
+	}
+}
+
+func (c *counterProtobufClient) Count(ctx context.Context, in *CountReq) (CounterCountClientStream, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Count")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Count"])
+	caller := c.callCount
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (CounterCountClientStream, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callCount(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(CounterCountClientStream)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(CounterCountClientStream) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterProtobufClient) callCount(ctx context.Context, in *CountReq) (CounterCountClientStream, error) {
+	ctx, stream, err := twirpruntime.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+	return &counterCountClientStream{ClientStream: stream}, nil
+}
+
+func (c *counterProtobufClient) Watch(ctx context.Context, in *CountReq) (CounterWatchClientStream, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Watch")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Watch"])
+	caller := c.callWatch
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (CounterWatchClientStream, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callWatch(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(CounterWatchClientStream)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(CounterWatchClientStream) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterProtobufClient) callWatch(ctx context.Context, in *CountReq) (CounterWatchClientStream, error) {
+	ctx, stream, err := twirpruntime.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, c.codec, c.maxResponseBytes, c.compression, c.httpGet)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+	return &counterWatchClientStream{ClientStream: stream}, nil
+}
+
+func (c *counterProtobufClient) Get(ctx context.Context, in *CountReq) (*Number, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Get")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Get"])
+	caller := c.callGet
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (*Number, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callGet(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Number)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Number) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterProtobufClient) callGet(ctx context.Context, in *CountReq) (*Number, error) {
+	out := new(Number)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[2], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *counterProtobufClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// ===================
+// Counter JSON Client
+// ===================
+
+type counterJSONClient struct {
+	client            HTTPClient
+	urls              [3]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // JSON unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+	httpGet           bool                           // use GET requests for methods without side effects
+}
+
+// NewCounterJSONClient creates a JSON client that implements the CounterClient interface.
+// It communicates using JSON and can be configured with a custom HTTPClient.
+func NewCounterJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) CounterClient {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ReadJSONCodec(&clientOpts)
+	_ = clientOpts.ReadOpt("codec", &codec)
+	httpGet := false
+	_ = clientOpts.ReadOpt("httpGet", &httpGet)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "", "Counter")
+	urls := [3]string{
+		serviceURL + "Count",
+		serviceURL + "Watch",
+		serviceURL + "Get",
+	}
+
+	return &counterJSONClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+		httpGet:           httpGet,
+	}
+}
+
+func (c *counterJSONClient) Count(ctx context.Context, in *CountReq) (CounterCountClientStream, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Count")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Count"])
+	caller := c.callCount
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (CounterCountClientStream, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callCount(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(CounterCountClientStream)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(CounterCountClientStream) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterJSONClient) callCount(ctx context.Context, in *CountReq) (CounterCountClientStream, error) {
+	ctx, stream, err := twirpruntime.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+	return &counterCountClientStream{ClientStream: stream}, nil
+}
+
+func (c *counterJSONClient) Watch(ctx context.Context, in *CountReq) (CounterWatchClientStream, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Watch")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Watch"])
+	caller := c.callWatch
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (CounterWatchClientStream, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callWatch(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(CounterWatchClientStream)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(CounterWatchClientStream) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterJSONClient) callWatch(ctx context.Context, in *CountReq) (CounterWatchClientStream, error) {
+	ctx, stream, err := twirpruntime.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, c.codec, c.maxResponseBytes, c.compression, c.httpGet)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+	return &counterWatchClientStream{ClientStream: stream}, nil
+}
+
+func (c *counterJSONClient) Get(ctx context.Context, in *CountReq) (*Number, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithMethodName(ctx, "Get")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["Get"])
+	caller := c.callGet
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *CountReq) (*Number, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return c.callGet(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Number)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Number) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *counterJSONClient) callGet(ctx context.Context, in *CountReq) (*Number, error) {
+	out := new(Number)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[2], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *counterJSONClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// ======================
+// Counter Server Handler
+// ======================
+
+type counterServer struct {
+	Counter
+	interceptor           twirp.Interceptor
+	hooks                 *twirp.ServerHooks
+	pathPrefix            string                                                 // prefix for routing
+	maxRequestBytes       int64                                                  // limit for request bodies, no limit if 0 or less
+	methodMaxRequestBytes map[string]int64                                       // per-method overrides of maxRequestBytes
+	compression           twirpruntime.Compression                               // request decompression and response compression
+	requestTimeouts       bool                                                   // apply timeouts from the Twirp-Timeout request header
+	maxRequestTimeout     time.Duration                                          // limit for timeouts from the Twirp-Timeout request header
+	panicHandler          func(context.Context, interface{}, []byte) twirp.Error // handles panics in methods, re-panic if nil
+	errorTransformer      func(context.Context, error) twirp.Error               // maps errors before they are sent, optional
+	codecs                map[string]twirp.Codec                                 // codecs by content type, including the built-in Protobuf and JSON codecs
+	methodDescriptors     twirpruntime.MethodDescriptors                         // added to request contexts
+}
+
+// NewCounterServer builds a TwirpServer that can be used as an http.Handler to handle
+// HTTP requests that are routed to the right method in the provided svc implementation.
+// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
+func NewCounterServer(svc Counter, opts ...interface{}) TwirpServer {
+	serverOpts := twirpruntime.NewServerOptions(opts)
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	var maxRequestBytes int64
+	_ = serverOpts.ReadOpt("maxRequestBytes", &maxRequestBytes)
+	var methodMaxRequestBytes map[string]int64
+	_ = serverOpts.ReadOpt("methodMaxRequestBytes", &methodMaxRequestBytes)
+	requestTimeouts := true
+	_ = serverOpts.ReadOpt("requestTimeouts", &requestTimeouts)
+	var maxRequestTimeout time.Duration
+	_ = serverOpts.ReadOpt("maxRequestTimeout", &maxRequestTimeout)
+	var panicHandler func(context.Context, interface{}, []byte) twirp.Error
+	_ = serverOpts.ReadOpt("panicHandler", &panicHandler)
+	var errorTransformer func(context.Context, error) twirp.Error
+	_ = serverOpts.ReadOpt("errorTransformer", &errorTransformer)
+	var pathPrefix string
+	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+
+	return &counterServer{
+		Counter:               svc,
+		hooks:                 serverOpts.Hooks,
+		interceptor:           twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:            pathPrefix,
+		maxRequestBytes:       maxRequestBytes,
+		methodMaxRequestBytes: methodMaxRequestBytes,
+		compression:           twirpruntime.ReadCompression(serverOpts),
+		requestTimeouts:       requestTimeouts,
+		maxRequestTimeout:     maxRequestTimeout,
+		panicHandler:          panicHandler,
+		errorTransformer:      errorTransformer,
+		codecs:                twirpruntime.ReadServerCodecs(serverOpts),
+		methodDescriptors:     twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// The error is first mapped by the error transformer, if any (see twirp.WithServerErrorTransformer).
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func (s *counterServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(ctx, resp, twirpruntime.TransformError(ctx, err, s.errorTransformer), s.hooks)
+}
+
+// handleRequestBodyError is used to handle error when the twirp server cannot read request
+func (s *counterServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if twerr, ok := err.(twirp.Error); ok {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if twerr := twirpruntime.BodyTooLargeTwirpError(err, "request "); twerr != nil {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if context.Canceled == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
+		return
+	}
+	if context.DeadlineExceeded == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
+		return
+	}
+	s.writeError(ctx, resp, twirp.WrapError(twirpruntime.MalformedRequestError(msg), err))
+}
+
+// requestBody returns the request body, decompressed if it has a Content-Encoding,
+// and limited to the max size configured for the method. It must be closed after reading.
+func (s *counterServer) requestBody(req *http.Request, method string) (io.ReadCloser, error) {
+	maxBytes := s.maxRequestBytes
+	if n, ok := s.methodMaxRequestBytes[method]; ok {
+		maxBytes = n
+	}
+	return twirpruntime.RequestBody(req, maxBytes, s.compression)
+}
+
+// CounterPathPrefix is a convenience constant that may identify URL paths.
+// Should be used with caution, it only matches routes generated by Twirp Go clients,
+// with the default "/twirp" prefix and default CamelCase service and method names.
+// More info: https://twitchtv.github.io/twirp/docs/routing.html
+const CounterPathPrefix = "/twirp/Counter/"
+
+func (s *counterServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if hooks, interceptor, ok := twirpruntime.MuxServerOptions(ctx); ok {
+		// Served by a twirp.Mux: apply the shared hooks and interceptors before the ones from this server
+		shared := *s
+		shared.hooks = twirp.ChainHooks(hooks, s.hooks)
+		shared.interceptor = twirp.ChainInterceptors(interceptor, s.interceptor)
+		s = &shared
+	}
+	ctx = ctxsetters.WithPackageName(ctx, "")
+	ctx = ctxsetters.WithServiceName(ctx, "Counter")
+	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	ctx = twirpruntime.WithErrorDetailsAccepted(ctx, req) // only clients that send Twirp-Error-Details get error details
+
+	var err error
+	ctx, err = twirpruntime.CallRequestReceived(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	if req.Method != "POST" && req.Method != "GET" {
+		msg := fmt.Sprintf("unsupported method %q (only POST and GET are allowed)", req.Method)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
+	prefix, pkgService, method := twirpruntime.ParseTwirpPath(req.URL.Path)
+	if pkgService != "Counter" {
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+	if prefix != s.pathPrefix {
+		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	switch method {
+	case "Count":
+		if req.Method != "POST" {
+			msg := fmt.Sprintf("unsupported method %q (only POST is allowed, the method may have side effects)", req.Method)
+			s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+			return
+		}
+		s.serveCount(ctx, resp, req)
+		return
+	case "Watch":
+		s.serveWatch(ctx, resp, req)
+		return
+	case "Get":
+		if req.Method != "POST" {
+			msg := fmt.Sprintf("unsupported method %q (only POST is allowed, the method may have side effects)", req.Method)
+			s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+			return
+		}
+		s.serveGet(ctx, resp, req)
+		return
+	default:
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+}
+
+func (s *counterServer) serveCount(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveCountCodec(ctx, resp, req, codec)
+}
+
+func (s *counterServer) serveCountCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "Count")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["Count"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "Count")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(CountReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	stream := &counterCountServerStream{ServerStream: twirpruntime.NewServerStream(ctx, resp, s.hooks, s.panicHandler, s.errorTransformer, codec)}
+
+	handler := s.Counter.Count
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *CountReq, stream CounterCountServerStream) error {
+			// Interceptors are called once per stream, the response is always nil
+			_, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return nil, s.Counter.Count(ctx, typedReq, stream)
+				},
+			)(ctx, req)
+			return err
+		}
+	}
+
+	// Call service method, response messages are written by the stream
+	func() {
+		defer stream.ServerStream.EnsurePanicResponses(&err)
+		err = handler(ctx, reqContent, stream)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+	stream.Finish(err)
+}
+
+func (s *counterServer) serveWatch(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	if req.Method == "GET" { // allowed for methods without side effects
+		var err error
+		if req, err = twirpruntime.RequestFromQuery(req); err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveWatchCodec(ctx, resp, req, codec)
+}
+
+func (s *counterServer) serveWatchCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "Watch")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["Watch"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "Watch")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(CountReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	stream := &counterWatchServerStream{ServerStream: twirpruntime.NewServerStream(ctx, resp, s.hooks, s.panicHandler, s.errorTransformer, codec)}
+
+	handler := s.Counter.Watch
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *CountReq, stream CounterWatchServerStream) error {
+			// Interceptors are called once per stream, the response is always nil
+			_, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return nil, s.Counter.Watch(ctx, typedReq, stream)
+				},
+			)(ctx, req)
+			return err
+		}
+	}
+
+	// Call service method, response messages are written by the stream
+	func() {
+		defer stream.ServerStream.EnsurePanicResponses(&err)
+		err = handler(ctx, reqContent, stream)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+	stream.Finish(err)
+}
+
+func (s *counterServer) serveGet(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveGetCodec(ctx, resp, req, codec)
+}
+
+func (s *counterServer) serveGetCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "Get")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["Get"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "Get")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(CountReq)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	handler := s.Counter.Get
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *CountReq) (*Number, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*CountReq)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*CountReq) when calling interceptor")
+					}
+					return s.Counter.Get(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*Number)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*Number) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *Number
+	func() {
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
+		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *Number and nil error while calling Get. nil responses are not supported"))
+		return
+	}
+
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
+
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
+	}
+	twirpruntime.CallResponseSent(ctx, s.hooks)
+}
+
+func (s *counterServer) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+func (s *counterServer) ProtocGenTwirpVersion() string {
+	return "v8.3.0"
+}
+
+// AppliesMuxServerOptions marks servers that apply the hooks and interceptors shared by a twirp.Mux.
+func (s *counterServer) AppliesMuxServerOptions() {}
+
+// PathPrefix returns the base service path, in the form: "/<prefix>/<package>.<Service>/"
+// that is everything in a Twirp route except for the <Method>. This can be used for routing,
+// for example to identify the requests that are targeted to this service in a mux.
+func (s *counterServer) PathPrefix() string {
+	return twirpruntime.BaseServicePath(s.pathPrefix, "", "Counter")
+}
+
+// =====
+// Utils
+// =====
+
+// HTTPClient is the interface used by generated clients to send HTTP requests.
+// It is fulfilled by *(net/http).Client, which is sufficient for most users.
+// Users can provide their own implementation for special retry policies.
+//
+// HTTPClient implementations should not follow redirects. Redirects are
+// automatically disabled if *(net/http).Client is passed to client
+// constructors. See twirpruntime.WithoutRedirects for more details.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// TwirpServer is the interface generated server structs will support: they're
+// HTTP handlers with additional methods for accessing metadata about the
+// service. Those accessors are a low-level API for building reflection tools.
+// Most people can think of TwirpServers as just http.Handlers.
+type TwirpServer interface {
+	http.Handler
+
+	// ServiceDescriptor returns gzipped bytes describing the .proto file that
+	// this service was generated from. Once unzipped, the bytes can be
+	// unmarshalled as a
+	// google.golang.org/protobuf/types/descriptorpb.FileDescriptorProto.
+	//
+	// The returned integer is the index of this particular service within that
+	// FileDescriptorProto's 'Service' slice of ServiceDescriptorProtos. This is a
+	// low-level field, expected to be used for reflection.
+	ServiceDescriptor() ([]byte, int)
+
+	// ProtocGenTwirpVersion is the semantic version string of the version of
+	// twirp used to generate this file.
+	ProtocGenTwirpVersion() string
+
+	// PathPrefix returns the HTTP URL path prefix for all methods handled by this
+	// service. This can be used with an HTTP mux to route Twirp requests.
+	// The path prefix is in the form: "/<prefix>/<package>.<Service>/"
+	// that is, everything in a Twirp route except for the <Method> at the end.
+	PathPrefix() string
+}
+
+// WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).
+// Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func WriteError(resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(context.Background(), resp, err, nil)
+}
+
+var twirpFileDescriptor0 = []byte{
+	// 189 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2b, 0x4e, 0x2d, 0x2a,
+	0x4b, 0x2d, 0x8a, 0x2f, 0x2e, 0x29, 0x4a, 0x4d, 0xcc, 0xcd, 0xcc, 0x4b, 0xd7, 0x2b, 0x28, 0xca,
+	0x2f, 0xc9, 0x57, 0x32, 0xe7, 0xe2, 0x70, 0xce, 0x2f, 0xcd, 0x2b, 0x09, 0x4a, 0x2d, 0x14, 0xe2,
+	0xe3, 0x62, 0x2a, 0xc9, 0x97, 0x60, 0x54, 0x60, 0xd4, 0x60, 0x0d, 0x62, 0x2a, 0xc9, 0x17, 0x92,
+	0xe6, 0xe2, 0x4c, 0x4b, 0xcc, 0xcc, 0x89, 0x2f, 0xcf, 0x2c, 0xc9, 0x90, 0x60, 0x52, 0x60, 0xd4,
+	0xe0, 0x0c, 0xe2, 0x00, 0x09, 0x84, 0x67, 0x96, 0x64, 0x28, 0xc9, 0x71, 0xb1, 0xf9, 0x95, 0xe6,
+	0x26, 0xa5, 0x16, 0x09, 0x89, 0x70, 0xb1, 0x96, 0x25, 0xe6, 0x94, 0xa6, 0x42, 0x75, 0x42, 0x38,
+	0x46, 0xd9, 0x5c, 0xec, 0x60, 0x83, 0x53, 0x8b, 0x84, 0xe4, 0xb9, 0x58, 0xc1, 0x4c, 0x21, 0x4e,
+	0x3d, 0x98, 0x5d, 0x52, 0xec, 0x7a, 0x10, 0xdd, 0x4a, 0x0c, 0x06, 0x8c, 0x42, 0x4a, 0x5c, 0xac,
+	0xe1, 0x89, 0x25, 0xc9, 0x19, 0x58, 0x15, 0x30, 0x4f, 0x60, 0x62, 0x34, 0x60, 0x14, 0x92, 0xe6,
+	0x62, 0x76, 0x4f, 0xc5, 0x61, 0x84, 0x93, 0x70, 0x94, 0xa0, 0x3e, 0xba, 0x07, 0x93, 0xd8, 0xc0,
+	0x3e, 0x34, 0x06, 0x0c, 0x00, 0x82, 0x0d, 0x84, 0xb2, 0xfb, 0x00, 0x00, 0x00,
+}
@@ -0,0 +1,347 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: server_streaming.proto
+
+package server_streaming
+
+import context "context"
+import sync "sync"
+
+import proto "google.golang.org/protobuf/proto"
+import twirp "github.com/twitchtv/twirp"
+
+// ============
+// Counter Mock
+// ============
+
+// CounterMock is a mock implementation of the Counter interface, to be used in tests.
+//
+// Calls to each method are recorded, and answered with the first matching expectation
+// added with Expect<Method>. Calls that match no expectation are answered by the
+// <Method>Func stub if not nil, or fail with a twirp.Unimplemented error. Call
+// AssertExpectations at the end of the test to check that all the expectations were
+// met, and that there were no unexpected calls.
+type CounterMock struct {
+	// CountFunc stubs Count for calls that match no expectation.
+	CountFunc func(context.Context, *CountReq, CounterCountServerStream) error
+
+	// WatchFunc stubs Watch for calls that match no expectation.
+	WatchFunc func(context.Context, *CountReq, CounterWatchServerStream) error
+
+	// GetFunc stubs Get for calls that match no expectation.
+	GetFunc func(context.Context, *CountReq) (*Number, error)
+
+	mu                sync.Mutex
+	countCalls        []*CounterMockCountCall
+	countExpectations []*CounterMockCountExpectation
+	watchCalls        []*CounterMockWatchCall
+	watchExpectations []*CounterMockWatchExpectation
+	getCalls          []*CounterMockGetCall
+	getExpectations   []*CounterMockGetExpectation
+	unexpected        []counterMockCall
+}
+
+var _ Counter = (*CounterMock)(nil)
+
+// counterMockCall is a call that matched no expectation or stub, reported by AssertExpectations.
+type counterMockCall struct {
+	method string
+	req    proto.Message
+}
+
+// CounterMockCountCall is a call to CounterMock.Count.
+type CounterMockCountCall struct {
+	Ctx context.Context
+	Req *CountReq
+	Err error
+}
+
+// CounterMockCountExpectation is an expected call to CounterMock.Count, added with ExpectCount.
+type CounterMockCountExpectation struct {
+	req    *CountReq
+	resp   []*Number
+	err    error
+	called bool
+}
+
+// Return sets the messages sent by the expected call, and the error returned after
+// sending them. Use a twirp.Error to end the stream with a specific error code.
+func (e *CounterMockCountExpectation) Return(resp []*Number, err error) *CounterMockCountExpectation {
+	e.resp = resp
+	e.err = err
+	return e
+}
+
+// ExpectCount adds an expected call to Count with a request equal to req,
+// compared with proto.Equal, or with any request if req is nil. Each expectation
+// matches a single call. By default the call sends no messages, use Return to change it.
+func (m *CounterMock) ExpectCount(req *CountReq) *CounterMockCountExpectation {
+	e := &CounterMockCountExpectation{req: req}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.countExpectations = append(m.countExpectations, e)
+	return e
+}
+
+// CountCalls returns the calls made to Count, in order.
+func (m *CounterMock) CountCalls() []CounterMockCountCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := make([]CounterMockCountCall, len(m.countCalls))
+	for i, call := range m.countCalls {
+		calls[i] = *call
+	}
+	return calls
+}
+
+// Count implements the Counter interface.
+func (m *CounterMock) Count(ctx context.Context, req *CountReq, stream CounterCountServerStream) error {
+	m.mu.Lock()
+	call := &CounterMockCountCall{Ctx: ctx, Req: req}
+	m.countCalls = append(m.countCalls, call)
+	var expected *CounterMockCountExpectation
+	for _, e := range m.countExpectations {
+		if !e.called && (e.req == nil || proto.Equal(e.req, req)) {
+			e.called = true
+			expected = e
+			break
+		}
+	}
+	stub := m.CountFunc
+	if expected == nil && stub == nil {
+		m.unexpected = append(m.unexpected, counterMockCall{method: "Count", req: req})
+	}
+	m.mu.Unlock()
+
+	var err error
+	switch {
+	case expected != nil:
+		err = expected.err
+		for _, msg := range expected.resp {
+			if sendErr := stream.Send(msg); sendErr != nil {
+				err = sendErr
+				break
+			}
+		}
+	case stub != nil:
+		err = stub(ctx, req, stream)
+	default:
+		err = twirp.NewError(twirp.Unimplemented, "unexpected call to CounterMock.Count")
+	}
+
+	m.mu.Lock()
+	call.Err = err
+	m.mu.Unlock()
+	return err
+}
+
+// CounterMockWatchCall is a call to CounterMock.Watch.
+type CounterMockWatchCall struct {
+	Ctx context.Context
+	Req *CountReq
+	Err error
+}
+
+// CounterMockWatchExpectation is an expected call to CounterMock.Watch, added with ExpectWatch.
+type CounterMockWatchExpectation struct {
+	req    *CountReq
+	resp   []*Number
+	err    error
+	called bool
+}
+
+// Return sets the messages sent by the expected call, and the error returned after
+// sending them. Use a twirp.Error to end the stream with a specific error code.
+func (e *CounterMockWatchExpectation) Return(resp []*Number, err error) *CounterMockWatchExpectation {
+	e.resp = resp
+	e.err = err
+	return e
+}
+
+// ExpectWatch adds an expected call to Watch with a request equal to req,
+// compared with proto.Equal, or with any request if req is nil. Each expectation
+// matches a single call. By default the call sends no messages, use Return to change it.
+func (m *CounterMock) ExpectWatch(req *CountReq) *CounterMockWatchExpectation {
+	e := &CounterMockWatchExpectation{req: req}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watchExpectations = append(m.watchExpectations, e)
+	return e
+}
+
+// WatchCalls returns the calls made to Watch, in order.
+func (m *CounterMock) WatchCalls() []CounterMockWatchCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := make([]CounterMockWatchCall, len(m.watchCalls))
+	for i, call := range m.watchCalls {
+		calls[i] = *call
+	}
+	return calls
+}
+
+// Watch implements the Counter interface.
+func (m *CounterMock) Watch(ctx context.Context, req *CountReq, stream CounterWatchServerStream) error {
+	m.mu.Lock()
+	call := &CounterMockWatchCall{Ctx: ctx, Req: req}
+	m.watchCalls = append(m.watchCalls, call)
+	var expected *CounterMockWatchExpectation
+	for _, e := range m.watchExpectations {
+		if !e.called && (e.req == nil || proto.Equal(e.req, req)) {
+			e.called = true
+			expected = e
+			break
+		}
+	}
+	stub := m.WatchFunc
+	if expected == nil && stub == nil {
+		m.unexpected = append(m.unexpected, counterMockCall{method: "Watch", req: req})
+	}
+	m.mu.Unlock()
+
+	var err error
+	switch {
+	case expected != nil:
+		err = expected.err
+		for _, msg := range expected.resp {
+			if sendErr := stream.Send(msg); sendErr != nil {
+				err = sendErr
+				break
+			}
+		}
+	case stub != nil:
+		err = stub(ctx, req, stream)
+	default:
+		err = twirp.NewError(twirp.Unimplemented, "unexpected call to CounterMock.Watch")
+	}
+
+	m.mu.Lock()
+	call.Err = err
+	m.mu.Unlock()
+	return err
+}
+
+// CounterMockGetCall is a call to CounterMock.Get.
+type CounterMockGetCall struct {
+	Ctx  context.Context
+	Req  *CountReq
+	Resp *Number // nil until the call returns
+	Err  error
+}
+
+// CounterMockGetExpectation is an expected call to CounterMock.Get, added with ExpectGet.
+type CounterMockGetExpectation struct {
+	req    *CountReq
+	resp   *Number
+	err    error
+	called bool
+}
+
+// Return sets the values returned by the expected call. Use a twirp.Error to fail
+// the call with a specific error code.
+func (e *CounterMockGetExpectation) Return(resp *Number, err error) *CounterMockGetExpectation {
+	e.resp = resp
+	e.err = err
+	return e
+}
+
+// ExpectGet adds an expected call to Get with a request equal to req,
+// compared with proto.Equal, or with any request if req is nil. Each expectation
+// matches a single call. By default the call returns an empty response, use Return to change it.
+func (m *CounterMock) ExpectGet(req *CountReq) *CounterMockGetExpectation {
+	e := &CounterMockGetExpectation{req: req, resp: &Number{}}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.getExpectations = append(m.getExpectations, e)
+	return e
+}
+
+// GetCalls returns the calls made to Get, in order.
+func (m *CounterMock) GetCalls() []CounterMockGetCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := make([]CounterMockGetCall, len(m.getCalls))
+	for i, call := range m.getCalls {
+		calls[i] = *call
+	}
+	return calls
+}
+
+// Get implements the Counter interface.
+func (m *CounterMock) Get(ctx context.Context, req *CountReq) (*Number, error) {
+	m.mu.Lock()
+	call := &CounterMockGetCall{Ctx: ctx, Req: req}
+	m.getCalls = append(m.getCalls, call)
+	var expected *CounterMockGetExpectation
+	for _, e := range m.getExpectations {
+		if !e.called && (e.req == nil || proto.Equal(e.req, req)) {
+			e.called = true
+			expected = e
+			break
+		}
+	}
+	stub := m.GetFunc
+	if expected == nil && stub == nil {
+		m.unexpected = append(m.unexpected, counterMockCall{method: "Get", req: req})
+	}
+	m.mu.Unlock()
+
+	var resp *Number
+	var err error
+	switch {
+	case expected != nil:
+		resp, err = expected.resp, expected.err
+	case stub != nil:
+		resp, err = stub(ctx, req)
+	default:
+		err = twirp.NewError(twirp.Unimplemented, "unexpected call to CounterMock.Get")
+	}
+
+	m.mu.Lock()
+	call.Resp, call.Err = resp, err
+	m.mu.Unlock()
+	return resp, err
+}
+
+// AssertExpectations reports on t the expectations that were not met, and the calls
+// that matched no expectation or stub.
+func (m *CounterMock) AssertExpectations(t interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.countExpectations {
+		if e.called {
+			continue
+		}
+		if e.req == nil {
+			t.Errorf("CounterMock: expected call to Count was not made")
+		} else {
+			t.Errorf("CounterMock: expected call to Count with request %v was not made", e.req)
+		}
+	}
+	for _, e := range m.watchExpectations {
+		if e.called {
+			continue
+		}
+		if e.req == nil {
+			t.Errorf("CounterMock: expected call to Watch was not made")
+		} else {
+			t.Errorf("CounterMock: expected call to Watch with request %v was not made", e.req)
+		}
+	}
+	for _, e := range m.getExpectations {
+		if e.called {
+			continue
+		}
+		if e.req == nil {
+			t.Errorf("CounterMock: expected call to Get was not made")
+		} else {
+			t.Errorf("CounterMock: expected call to Get with request %v was not made", e.req)
+		}
+	}
+	for _, call := range m.unexpected {
+		t.Errorf("CounterMock: unexpected call to %s with request %v", call.method, call.req)
+	}
+}
@@ -0,0 +1,567 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server_streaming
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/twirpruntime"
+)
+
+type counter struct {
+	sent      chan int32  // optional, receives each number after it is sent
+	panicWith interface{} // optional, panic after sending all numbers
+}
+
+func (c *counter) count(ctx context.Context, req *CountReq, send func(*Number) error) error {
+	if req.To < 0 {
+		return twirp.InvalidArgumentError("to", "must be positive")
+	}
+	for i := int32(1); i <= req.To; i++ {
+		if err := send(&Number{Value: i}); err != nil {
+			return err
+		}
+		if c.sent != nil {
+			c.sent <- i
+		}
+	}
+	if c.panicWith != nil {
+		panic(c.panicWith)
+	}
+	if req.FailWith != "" {
+		return twirp.NewError(twirp.Unavailable, req.FailWith).WithMeta("sent", "all")
+	}
+	return nil
+}
+
+func (c *counter) Count(ctx context.Context, req *CountReq, stream CounterCountServerStream) error {
+	return c.count(ctx, req, stream.Send)
+}
+
+func (c *counter) Watch(ctx context.Context, req *CountReq, stream CounterWatchServerStream) error {
+	return c.count(ctx, req, stream.Send)
+}
+
+func (c *counter) Get(ctx context.Context, req *CountReq) (*Number, error) {
+	return &Number{Value: req.To}, nil
+}
+
+// readAll reads all the messages from a stream.
+func readAll(stream CounterCountClientStream) ([]int32, error) {
+	var values []int32
+	for stream.Next() {
+		values = append(values, stream.Msg().Value)
+	}
+	return values, stream.Err()
+}
+
+func equalValues(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func newClients(url string, opts ...twirp.ClientOption) map[string]CounterClient {
+	return map[string]CounterClient{
+		"protobuf": NewCounterProtobufClient(url, http.DefaultClient, opts...),
+		"json":     NewCounterJSONClient(url, http.DefaultClient, opts...),
+	}
+}
+
+func TestServerStreaming(t *testing.T) {
+	s := httptest.NewServer(NewCounterServer(&counter{}))
+	defer s.Close()
+
+	for name, client := range newClients(s.URL) {
+		t.Run(name, func(t *testing.T) {
+			stream, err := client.Count(context.Background(), &CountReq{To: 3})
+			if err != nil {
+				t.Fatalf("Count err=%q", err)
+			}
+			values, err := readAll(stream)
+			if err != nil {
+				t.Fatalf("stream err=%q", err)
+			}
+			if want := []int32{1, 2, 3}; !equalValues(values, want) {
+				t.Errorf("unexpected values, have=%v, want=%v", values, want)
+			}
+			if stream.Next() {
+				t.Error("Next returned true after the end of the stream")
+			}
+
+			// empty stream
+			stream, err = client.Count(context.Background(), &CountReq{To: 0})
+			if err != nil {
+				t.Fatalf("Count err=%q", err)
+			}
+			if values, err = readAll(stream); err != nil || len(values) != 0 {
+				t.Errorf("unexpected empty stream result, values=%v, err=%v", values, err)
+			}
+
+			// unary methods still work
+			n, err := client.Get(context.Background(), &CountReq{To: 7})
+			if err != nil || n.Value != 7 {
+				t.Errorf("unexpected Get result, n=%v, err=%v", n, err)
+			}
+		})
+	}
+}
+
+func TestServerStreamingErrors(t *testing.T) {
+	s := httptest.NewServer(NewCounterServer(&counter{}))
+	defer s.Close()
+
+	for name, client := range newClients(s.URL) {
+		t.Run(name, func(t *testing.T) {
+			// Error before sending any message: regular error response
+			_, err := client.Count(context.Background(), &CountReq{To: -1})
+			var twerr twirp.Error
+			if !errors.As(err, &twerr) || twerr.Code() != twirp.InvalidArgument {
+				t.Fatalf("expected invalid_argument error, have %v", err)
+			}
+
+			// Error after sending messages: terminal error frame
+			stream, err := client.Count(context.Background(), &CountReq{To: 2, FailWith: "oops"})
+			if err != nil {
+				t.Fatalf("Count err=%q", err)
+			}
+			values, err := readAll(stream)
+			if want := []int32{1, 2}; !equalValues(values, want) {
+				t.Errorf("unexpected values, have=%v, want=%v", values, want)
+			}
+			if !errors.As(err, &twerr) || twerr.Code() != twirp.Unavailable || twerr.Msg() != "oops" || twerr.Meta("sent") != "all" {
+				t.Fatalf("unexpected stream error %v", err)
+			}
+		})
+	}
+}
+
+func TestServerStreamingPanicHandler(t *testing.T) {
+	var recovered interface{}
+	panicHandler := twirp.WithServerPanicHandler(func(ctx context.Context, r interface{}, stack []byte) twirp.Error {
+		recovered = r
+		return twirp.NewError(twirp.Unavailable, "try again")
+	})
+	// The panic is not re-raised: a panic would crash the httptest server.
+	s := httptest.NewServer(NewCounterServer(&counter{panicWith: "OH NO!"}, panicHandler))
+	defer s.Close()
+
+	for name, client := range newClients(s.URL) {
+		t.Run(name, func(t *testing.T) {
+			stream, err := client.Count(context.Background(), &CountReq{To: 2})
+			if err != nil {
+				t.Fatalf("Count err=%q", err)
+			}
+			values, err := readAll(stream)
+			if want := []int32{1, 2}; !equalValues(values, want) {
+				t.Errorf("unexpected values, have=%v, want=%v", values, want)
+			}
+			var twerr twirp.Error
+			if !errors.As(err, &twerr) || twerr.Code() != twirp.Unavailable || twerr.Msg() != "try again" {
+				t.Fatalf("stream error %v, want the error of the panic handler", err)
+			}
+			if recovered != "OH NO!" {
+				t.Errorf("recovered=%v, want the panic value", recovered)
+			}
+		})
+	}
+}
+
+func TestServerStreamingIncremental(t *testing.T) {
+	sent := make(chan int32, 10)
+	s := httptest.NewServer(NewCounterServer(&counter{sent: sent}))
+	defer s.Close()
+
+	client := NewCounterProtobufClient(s.URL, http.DefaultClient)
+	stream, err := client.Count(context.Background(), &CountReq{To: 2})
+	if err != nil {
+		t.Fatalf("Count err=%q", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	// Each message is flushed as soon as it is sent
+	if !stream.Next() || stream.Msg().Value != 1 {
+		t.Fatalf("unexpected first message %v, err=%v", stream.Msg(), stream.Err())
+	}
+	if v := <-sent; v != 1 {
+		t.Fatalf("unexpected sent value %d", v)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close err=%q", err)
+	}
+	if stream.Next() {
+		t.Error("Next returned true after Close")
+	}
+}
+
+func TestServerStreamingHooks(t *testing.T) {
+	var serverEvents, clientEvents []string
+	responseSent := make(chan struct{}, 1)
+	serverHooks := &twirp.ServerHooks{
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			serverEvents = append(serverEvents, "routed")
+			return ctx, nil
+		},
+		ResponsePrepared: func(ctx context.Context) context.Context {
+			serverEvents = append(serverEvents, "prepared")
+			return ctx
+		},
+		Error: func(ctx context.Context, err twirp.Error) context.Context {
+			serverEvents = append(serverEvents, "error:"+string(err.Code()))
+			return ctx
+		},
+		ResponseSent: func(ctx context.Context) {
+			status, _ := twirp.StatusCode(ctx)
+			serverEvents = append(serverEvents, "sent:"+status)
+			responseSent <- struct{}{}
+		},
+	}
+	clientHooks := &twirp.ClientHooks{
+		ResponseReceived: func(ctx context.Context) {
+			clientEvents = append(clientEvents, "received")
+		},
+		Error: func(ctx context.Context, err twirp.Error) {
+			clientEvents = append(clientEvents, "error:"+string(err.Code()))
+		},
+	}
+	s := httptest.NewServer(NewCounterServer(&counter{}, twirp.WithServerHooks(serverHooks)))
+	defer s.Close()
+	client := NewCounterProtobufClient(s.URL, http.DefaultClient, twirp.WithClientHooks(clientHooks))
+
+	tests := []struct {
+		req          *CountReq
+		serverEvents []string
+		clientEvents []string
+	}{
+		{&CountReq{To: 2}, []string{"routed", "prepared", "sent:200"}, []string{"received"}},
+		{&CountReq{To: 0}, []string{"routed", "prepared", "sent:200"}, []string{"received"}},
+		{&CountReq{To: -1}, []string{"routed", "error:invalid_argument", "sent:400"}, []string{"error:invalid_argument"}},
+		{&CountReq{To: 1, FailWith: "oops"}, []string{"routed", "prepared", "error:unavailable", "sent:200"}, []string{"error:unavailable"}},
+	}
+	for _, tt := range tests {
+		serverEvents, clientEvents = nil, nil
+		stream, err := client.Count(context.Background(), tt.req)
+		if err == nil {
+			_, _ = readAll(stream)
+		}
+		<-responseSent // server events are complete
+		if strings.Join(serverEvents, ",") != strings.Join(tt.serverEvents, ",") {
+			t.Errorf("req=%v: unexpected server hooks, have=%v, want=%v", tt.req, serverEvents, tt.serverEvents)
+		}
+		if strings.Join(clientEvents, ",") != strings.Join(tt.clientEvents, ",") {
+			t.Errorf("req=%v: unexpected client hooks, have=%v, want=%v", tt.req, clientEvents, tt.clientEvents)
+		}
+	}
+}
+
+func TestServerStreamingInterceptors(t *testing.T) {
+	var mu sync.Mutex
+	var intercepted []string
+	interceptor := func(side string) twirp.Interceptor {
+		return func(next twirp.Method) twirp.Method {
+			return func(ctx context.Context, req interface{}) (interface{}, error) {
+				method, _ := twirp.MethodName(ctx)
+				mu.Lock()
+				intercepted = append(intercepted, side+":"+method)
+				mu.Unlock()
+				return next(ctx, req)
+			}
+		}
+	}
+	s := httptest.NewServer(NewCounterServer(&counter{}, twirp.WithServerInterceptors(interceptor("server"))))
+	defer s.Close()
+	client := NewCounterJSONClient(s.URL, http.DefaultClient, twirp.WithClientInterceptors(interceptor("client")))
+
+	stream, err := client.Count(context.Background(), &CountReq{To: 2})
+	if err != nil {
+		t.Fatalf("Count err=%q", err)
+	}
+	values, err := readAll(stream)
+	if err != nil || len(values) != 2 {
+		t.Fatalf("unexpected stream result, values=%v, err=%v", values, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := "client:Count,server:Count"; strings.Join(intercepted, ",") != want {
+		t.Errorf("unexpected interceptor calls, have=%v, want=%v", intercepted, want)
+	}
+}
+
+func TestServerStreamingMaxResponseBytes(t *testing.T) {
+	s := httptest.NewServer(NewCounterServer(&counter{}))
+	defer s.Close()
+
+	for name, client := range newClients(s.URL, twirp.WithClientMaxResponseBytes(1)) {
+		t.Run(name, func(t *testing.T) {
+			stream, err := client.Count(context.Background(), &CountReq{To: 1})
+			if err != nil {
+				t.Fatalf("Count err=%q", err)
+			}
+			_, err = readAll(stream)
+			var twerr twirp.Error
+			if !errors.As(err, &twerr) || twerr.Code() != twirp.ResourceExhausted {
+				t.Fatalf("expected resource_exhausted error, have %v", err)
+			}
+		})
+	}
+}
+
+func TestServerStreamingWireFormat(t *testing.T) {
+	s := httptest.NewServer(NewCounterServer(&counter{}))
+	defer s.Close()
+
+	t.Run("protobuf", func(t *testing.T) {
+		reqBody, _ := proto.Marshal(&CountReq{To: 2, FailWith: "oops"})
+		resp, err := http.Post(s.URL+"/twirp/Counter/Count", "application/protobuf", strings.NewReader(string(reqBody)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != 200 || resp.Header.Get("Content-Type") != "application/protobuf" {
+			t.Fatalf("unexpected response status=%d, Content-Type=%q", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+
+		r := bufio.NewReader(resp.Body)
+		readFrame := func() (byte, []byte) {
+			var header [5]byte
+			if _, err := io.ReadFull(r, header[:]); err != nil {
+				t.Fatalf("failed to read frame header: %v", err)
+			}
+			payload := make([]byte, binary.BigEndian.Uint32(header[1:]))
+			if _, err := io.ReadFull(r, payload); err != nil {
+				t.Fatalf("failed to read frame payload: %v", err)
+			}
+			return header[0], payload
+		}
+		for i := int32(1); i <= 2; i++ {
+			frameType, payload := readFrame()
+			n := &Number{}
+			if err := proto.Unmarshal(payload, n); frameType != 0 || err != nil || n.Value != i {
+				t.Fatalf("unexpected message frame type=%d, payload=%v", frameType, payload)
+			}
+		}
+		frameType, payload := readFrame()
+		if frameType != 1 || !strings.Contains(string(payload), `"code":"unavailable"`) {
+			t.Fatalf("unexpected error frame type=%d, payload=%s", frameType, payload)
+		}
+		if _, err := r.ReadByte(); err != io.EOF {
+			t.Fatalf("expected the end of the response, have err=%v", err)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		resp, err := http.Post(s.URL+"/twirp/Counter/Count", "application/json", strings.NewReader(`{"to":2}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		want := `{"message":{"value":1}}` + "\n" + `{"message":{"value":2}}` + "\n" + `{"end":true}` + "\n"
+		if strings.ReplaceAll(string(body), " ", "") != want {
+			t.Fatalf("unexpected JSON stream %q, want %q", body, want)
+		}
+	})
+
+	t.Run("multiline json codec", func(t *testing.T) {
+		codec := twirpruntime.NewJSONCodec(protojson.MarshalOptions{Multiline: true}, protojson.UnmarshalOptions{})
+		s := httptest.NewServer(NewCounterServer(&counter{}, twirp.WithServerCodecs(codec)))
+		defer s.Close()
+
+		resp, err := http.Post(s.URL+"/twirp/Counter/Count", "application/json", strings.NewReader(`{"to":1}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		want := `{"message":{"value":1}}` + "\n" + `{"end":true}` + "\n"
+		if string(body) != want {
+			t.Fatalf("unexpected JSON stream %q, want %q", body, want)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		resp, err := http.Get(s.URL + "/twirp/Counter/Watch?message=" + url.QueryEscape(`{"to":1}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		want := `{"message":{"value":1}}` + "\n" + `{"end":true}` + "\n"
+		if strings.ReplaceAll(string(body), " ", "") != want {
+			t.Fatalf("unexpected JSON stream %q, want %q", body, want)
+		}
+	})
+}
+
+// protobufCodec is a twirp.Codec that serializes Protobuf messages with a custom content type.
+type protobufCodec string
+
+func (c protobufCodec) ContentType() string { return string(c) }
+
+func (protobufCodec) Marshal(msg interface{}) ([]byte, error) {
+	return proto.Marshal(msg.(proto.Message))
+}
+
+func (protobufCodec) Unmarshal(data []byte, msg interface{}) error {
+	return proto.Unmarshal(data, msg.(proto.Message))
+}
+
+func TestServerStreamingCodecs(t *testing.T) {
+	codec := protobufCodec("application/x-protobuf")
+	s := httptest.NewServer(NewCounterServer(&counter{}, twirp.WithServerCodecs(codec)))
+	defer s.Close()
+
+	for name, client := range newClients(s.URL, twirp.WithClientCodec(codec)) {
+		t.Run(name, func(t *testing.T) {
+			stream, err := client.Count(context.Background(), &CountReq{To: 3})
+			if err != nil {
+				t.Fatalf("Count err=%q", err)
+			}
+			values, err := readAll(stream)
+			if err != nil {
+				t.Fatalf("stream err=%q", err)
+			}
+			if want := []int32{1, 2, 3}; !equalValues(values, want) {
+				t.Errorf("unexpected values, have=%v, want=%v", values, want)
+			}
+
+			// the error frame is still JSON
+			stream, err = client.Count(context.Background(), &CountReq{To: 1, FailWith: "oops"})
+			if err != nil {
+				t.Fatalf("Count err=%q", err)
+			}
+			_, err = readAll(stream)
+			var twerr twirp.Error
+			if !errors.As(err, &twerr) || twerr.Code() != twirp.Unavailable || twerr.Msg() != "oops" {
+				t.Errorf("expected unavailable error, have %v", err)
+			}
+		})
+	}
+
+	t.Run("wire format", func(t *testing.T) {
+		reqBody, _ := proto.Marshal(&CountReq{To: 1})
+		resp, err := http.Post(s.URL+"/twirp/Counter/Count", "application/x-protobuf", strings.NewReader(string(reqBody)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != 200 || resp.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Fatalf("unexpected response status=%d, Content-Type=%q", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		msg, _ := proto.Marshal(&Number{Value: 1})
+		want := append(append([]byte{0, 0, 0, 0, byte(len(msg))}, msg...), 2, 0, 0, 0, 0)
+		if string(body) != string(want) {
+			t.Fatalf("unexpected length-prefixed stream %v, want %v", body, want)
+		}
+	})
+}
+
+func TestServerStreamingTruncated(t *testing.T) {
+	// A stream that ends without an end frame is an error
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"message":{"value":1}}` + "\n"))
+	}))
+	defer s.Close()
+
+	client := NewCounterJSONClient(s.URL, http.DefaultClient)
+	stream, err := client.Count(context.Background(), &CountReq{To: 2})
+	if err != nil {
+		t.Fatalf("Count err=%q", err)
+	}
+	values, err := readAll(stream)
+	if len(values) != 1 {
+		t.Errorf("unexpected values %v", values)
+	}
+	var twerr twirp.Error
+	if !errors.As(err, &twerr) || twerr.Code() != twirp.Internal {
+		t.Fatalf("expected internal error, have %v", err)
+	}
+}
+
+func TestServerStreamingMock(t *testing.T) {
+	mock := &CounterMock{}
+	mock.ExpectCount(&CountReq{To: 2}).Return([]*Number{{Value: 1}, {Value: 2}}, twirp.NewError(twirp.Unavailable, "oops"))
+	s := httptest.NewServer(NewCounterServer(mock))
+	defer s.Close()
+
+	stream, err := NewCounterProtobufClient(s.URL, http.DefaultClient).Count(context.Background(), &CountReq{To: 2})
+	if err != nil {
+		t.Fatalf("Count err=%q", err)
+	}
+	values, err := readAll(stream)
+	if want := []int32{1, 2}; !equalValues(values, want) {
+		t.Errorf("unexpected values, have=%v, want=%v", values, want)
+	}
+	var twerr twirp.Error
+	if !errors.As(err, &twerr) || twerr.Code() != twirp.Unavailable {
+		t.Errorf("unexpected stream error %v", err)
+	}
+
+	if calls := mock.CountCalls(); len(calls) != 1 || calls[0].Req.To != 2 || calls[0].Err == nil {
+		t.Errorf("unexpected recorded calls %v", calls)
+	}
+	mock.AssertExpectations(t)
+}
+
+// getOnlyCounter implements only the Get method.
+type getOnlyCounter struct {
+	UnimplementedCounterServer
+}
+
+func (getOnlyCounter) Get(ctx context.Context, req *CountReq) (*Number, error) {
+	return &Number{Value: req.To}, nil
+}
+
+func TestServerStreamingUnimplemented(t *testing.T) {
+	s := httptest.NewServer(NewCounterServer(getOnlyCounter{}))
+	defer s.Close()
+
+	for name, client := range newClients(s.URL) {
+		t.Run(name, func(t *testing.T) {
+			n, err := client.Get(context.Background(), &CountReq{To: 7})
+			if err != nil || n.Value != 7 {
+				t.Errorf("unexpected Get result, n=%v, err=%v", n, err)
+			}
+
+			_, err = client.Count(context.Background(), &CountReq{To: 2})
+			var twerr twirp.Error
+			if !errors.As(err, &twerr) || twerr.Code() != twirp.Unimplemented {
+				t.Errorf("expected unimplemented error, have %v", err)
+			}
+		})
+	}
+}
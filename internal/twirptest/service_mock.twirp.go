@@ -0,0 +1,145 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: service.proto
+
+package twirptest
+
+import context "context"
+import sync "sync"
+
+import proto "google.golang.org/protobuf/proto"
+import twirp "github.com/twitchtv/twirp"
+
+// ================
+// Haberdasher Mock
+// ================
+
+// HaberdasherMock is a mock implementation of the Haberdasher interface, to be used in tests.
+//
+// Calls to each method are recorded, and answered with the first matching expectation
+// added with Expect<Method>. Calls that match no expectation are answered by the
+// <Method>Func stub if not nil, or fail with a twirp.Unimplemented error. Call
+// AssertExpectations at the end of the test to check that all the expectations were
+// met, and that there were no unexpected calls.
+type HaberdasherMock struct {
+	// MakeHatFunc stubs MakeHat for calls that match no expectation.
+	MakeHatFunc func(context.Context, *Size) (*Hat, error)
+
+	mu                  sync.Mutex
+	makeHatCalls        []*HaberdasherMockMakeHatCall
+	makeHatExpectations []*HaberdasherMockMakeHatExpectation
+	unexpected          []haberdasherMockCall
+}
+
+var _ Haberdasher = (*HaberdasherMock)(nil)
+
+// haberdasherMockCall is a call that matched no expectation or stub, reported by AssertExpectations.
+type haberdasherMockCall struct {
+	method string
+	req    proto.Message
+}
+
+// HaberdasherMockMakeHatCall is a call to HaberdasherMock.MakeHat.
+type HaberdasherMockMakeHatCall struct {
+	Ctx  context.Context
+	Req  *Size
+	Resp *Hat // nil until the call returns
+	Err  error
+}
+
+// HaberdasherMockMakeHatExpectation is an expected call to HaberdasherMock.MakeHat, added with ExpectMakeHat.
+type HaberdasherMockMakeHatExpectation struct {
+	req    *Size
+	resp   *Hat
+	err    error
+	called bool
+}
+
+// Return sets the values returned by the expected call. Use a twirp.Error to fail
+// the call with a specific error code.
+func (e *HaberdasherMockMakeHatExpectation) Return(resp *Hat, err error) *HaberdasherMockMakeHatExpectation {
+	e.resp = resp
+	e.err = err
+	return e
+}
+
+// ExpectMakeHat adds an expected call to MakeHat with a request equal to req,
+// compared with proto.Equal, or with any request if req is nil. Each expectation
+// matches a single call. By default the call returns an empty response, use Return to change it.
+func (m *HaberdasherMock) ExpectMakeHat(req *Size) *HaberdasherMockMakeHatExpectation {
+	e := &HaberdasherMockMakeHatExpectation{req: req, resp: &Hat{}}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.makeHatExpectations = append(m.makeHatExpectations, e)
+	return e
+}
+
+// MakeHatCalls returns the calls made to MakeHat, in order.
+func (m *HaberdasherMock) MakeHatCalls() []HaberdasherMockMakeHatCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := make([]HaberdasherMockMakeHatCall, len(m.makeHatCalls))
+	for i, call := range m.makeHatCalls {
+		calls[i] = *call
+	}
+	return calls
+}
+
+// MakeHat implements the Haberdasher interface.
+func (m *HaberdasherMock) MakeHat(ctx context.Context, req *Size) (*Hat, error) {
+	m.mu.Lock()
+	call := &HaberdasherMockMakeHatCall{Ctx: ctx, Req: req}
+	m.makeHatCalls = append(m.makeHatCalls, call)
+	var expected *HaberdasherMockMakeHatExpectation
+	for _, e := range m.makeHatExpectations {
+		if !e.called && (e.req == nil || proto.Equal(e.req, req)) {
+			e.called = true
+			expected = e
+			break
+		}
+	}
+	stub := m.MakeHatFunc
+	if expected == nil && stub == nil {
+		m.unexpected = append(m.unexpected, haberdasherMockCall{method: "MakeHat", req: req})
+	}
+	m.mu.Unlock()
+
+	var resp *Hat
+	var err error
+	switch {
+	case expected != nil:
+		resp, err = expected.resp, expected.err
+	case stub != nil:
+		resp, err = stub(ctx, req)
+	default:
+		err = twirp.NewError(twirp.Unimplemented, "unexpected call to HaberdasherMock.MakeHat")
+	}
+
+	m.mu.Lock()
+	call.Resp, call.Err = resp, err
+	m.mu.Unlock()
+	return resp, err
+}
+
+// AssertExpectations reports on t the expectations that were not met, and the calls
+// that matched no expectation or stub.
+func (m *HaberdasherMock) AssertExpectations(t interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.makeHatExpectations {
+		if e.called {
+			continue
+		}
+		if e.req == nil {
+			t.Errorf("HaberdasherMock: expected call to MakeHat was not made")
+		} else {
+			t.Errorf("HaberdasherMock: expected call to MakeHat with request %v was not made", e.req)
+		}
+	}
+	for _, call := range m.unexpected {
+		t.Errorf("HaberdasherMock: unexpected call to %s with request %v", call.method, call.req)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -26,13 +27,18 @@ import (
 	"os"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	pkgerrors "github.com/pkg/errors"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/twitchtv/twirp"
 	"github.com/twitchtv/twirp/internal/descriptors"
@@ -1193,6 +1199,204 @@ func TestPanicsTriggerErrorHooks(t *testing.T) {
 	}
 }
 
+func TestPanicHandler(t *testing.T) {
+	var (
+		recovered interface{}
+		stack     []byte
+	)
+	panicHandler := twirp.WithServerPanicHandler(func(ctx context.Context, r interface{}, s []byte) twirp.Error {
+		recovered, stack = r, s
+		if method, _ := twirp.MethodName(ctx); method != "MakeHat" {
+			t.Errorf("panic handler ctx has method %q, want MakeHat", method)
+		}
+		return twirp.NewError(twirp.Unavailable, "try again")
+	})
+	hooks, recorder := recorderHooks()
+	var hookErr twirp.Error
+	errHook := &twirp.ServerHooks{
+		Error: func(ctx context.Context, twerr twirp.Error) context.Context {
+			hookErr = twerr
+			return ctx
+		},
+	}
+
+	// The panic is not re-raised: a panic would crash the httptest server.
+	server := httptest.NewServer(NewHaberdasherServer(PanickyHatmaker("OH NO!"),
+		twirp.WithServerHooks(twirp.ChainHooks(hooks, errHook)), panicHandler))
+	defer server.Close()
+
+	client := NewHaberdasherProtobufClient(server.URL, http.DefaultClient)
+	_, err := client.MakeHat(context.Background(), &Size{Inches: 1})
+	twerr, ok := err.(twirp.Error)
+	if !ok {
+		t.Fatalf("expected twirp.Error type error, have %T", err)
+	}
+	if twerr.Code() != twirp.Unavailable || twerr.Msg() != "try again" {
+		t.Errorf("client err=%v, want the error of the panic handler", twerr)
+	}
+	if hookErr == nil || hookErr.Code() != twirp.Unavailable {
+		t.Errorf("error hook err=%v, want the error of the panic handler", hookErr)
+	}
+	if recovered != "OH NO!" {
+		t.Errorf("recovered=%v, want the panic value", recovered)
+	}
+	if !strings.Contains(string(stack), "PanickyHatmaker") {
+		t.Errorf("stack does not include the function that panicked:\n%s", stack)
+	}
+	recorder.assertHookCalls(t, []hookCall{received, routed, errored, sent})
+}
+
+func TestPanicHandlerDefaultError(t *testing.T) {
+	panicHandler := twirp.WithServerPanicHandler(func(ctx context.Context, r interface{}, s []byte) twirp.Error {
+		return nil // swallow the panic
+	})
+	server := httptest.NewServer(NewHaberdasherServer(PanickyHatmaker("OH NO!"), panicHandler))
+	defer server.Close()
+
+	client := NewHaberdasherJSONClient(server.URL, http.DefaultClient)
+	_, err := client.MakeHat(context.Background(), &Size{Inches: 1})
+	twerr, ok := err.(twirp.Error)
+	if !ok {
+		t.Fatalf("expected twirp.Error type error, have %T", err)
+	}
+	if twerr.Code() != twirp.Internal || twerr.Msg() != "Internal service panic" {
+		t.Errorf("client err=%v, want the default panic error", twerr)
+	}
+}
+
+func TestErrorTransformer(t *testing.T) {
+	errNoRows := errors.New("sql: no rows in result set")
+	transformer := twirp.WithServerErrorTransformer(func(ctx context.Context, err error) twirp.Error {
+		if errors.Is(err, errNoRows) {
+			return twirp.NotFoundError("hat not found")
+		}
+		var twerr twirp.Error
+		if !errors.As(err, &twerr) {
+			return twirp.InternalError("internal error") // hide the original message
+		}
+		return nil
+	})
+
+	testcase := func(name string, h Haberdasher, wantCode twirp.ErrorCode, wantMsg string, wantCause error) {
+		t.Run(name, func(t *testing.T) {
+			var hookErr twirp.Error
+			errHook := &twirp.ServerHooks{
+				Error: func(ctx context.Context, twerr twirp.Error) context.Context {
+					hookErr = twerr
+					return ctx
+				},
+			}
+			server := httptest.NewServer(NewHaberdasherServer(h, transformer, errHook))
+			defer server.Close()
+
+			client := NewHaberdasherProtobufClient(server.URL, http.DefaultClient)
+			_, err := client.MakeHat(context.Background(), &Size{Inches: 1})
+			twerr, ok := err.(twirp.Error)
+			if !ok {
+				t.Fatalf("expected twirp.Error type error, have %T", err)
+			}
+			if twerr.Code() != wantCode || twerr.Msg() != wantMsg {
+				t.Errorf("client err=%v, want code %q and message %q", twerr, wantCode, wantMsg)
+			}
+			if hookErr == nil || hookErr.Code() != wantCode {
+				t.Fatalf("error hook err=%v, want code %q", hookErr, wantCode)
+			}
+			if wantCause != nil && !errors.Is(hookErr, wantCause) {
+				t.Errorf("error hook err=%v, want the original error as cause", hookErr)
+			}
+		})
+	}
+	testcase("mapped", ErroringHatmaker(fmt.Errorf("find hat: %w", errNoRows)), twirp.NotFound, "hat not found", errNoRows)
+	leak := errors.New("connection to db.internal:5432 refused")
+	testcase("scrubbed", ErroringHatmaker(leak), twirp.Internal, "internal error", leak)
+	testcase("unchanged", ErroringHatmaker(twirp.InvalidArgumentError("inches", "too big")), twirp.InvalidArgument, "inches too big", nil)
+}
+
+func TestUnimplementedServer(t *testing.T) {
+	s, client := ServerAndClient(UnimplementedHaberdasherServer{}, nil)
+	defer s.Close()
+
+	_, err := client.MakeHat(context.Background(), &Size{Inches: 1})
+	twerr, ok := err.(twirp.Error)
+	if !ok || twerr.Code() != twirp.Unimplemented {
+		t.Fatalf("expected unimplemented error, have %v", err)
+	}
+	if twerr.Msg() != "method MakeHat is not implemented" {
+		t.Errorf("unexpected error message %q", twerr.Msg())
+	}
+}
+
+// fakeT records the errors reported by mocks.
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestHaberdasherMock(t *testing.T) {
+	mock := &HaberdasherMock{}
+	mock.ExpectMakeHat(&Size{Inches: 1}).Return(&Hat{Size: 1, Color: "red"}, nil)
+	mock.ExpectMakeHat(nil).Return(nil, twirp.NotFoundError("no hats"))
+
+	s, client := ServerAndClient(mock, nil)
+	defer s.Close()
+
+	hat, err := client.MakeHat(context.Background(), &Size{Inches: 1})
+	if err != nil || hat.Color != "red" {
+		t.Errorf("first call hat=%v err=%v", hat, err)
+	}
+	_, err = client.MakeHat(context.Background(), &Size{Inches: 2})
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.NotFound {
+		t.Errorf("second call err=%v, want not_found", err)
+	}
+
+	// no more expectations or stub: unexpected call
+	_, err = client.MakeHat(context.Background(), &Size{Inches: 3})
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.Unimplemented {
+		t.Errorf("unexpected call err=%v, want unimplemented", err)
+	}
+	ft := &fakeT{}
+	mock.AssertExpectations(ft)
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "unexpected call to MakeHat") {
+		t.Errorf("AssertExpectations errors=%q, want the unexpected call", ft.errors)
+	}
+
+	calls := mock.MakeHatCalls()
+	if len(calls) != 3 {
+		t.Fatalf("recorded %d calls, want 3", len(calls))
+	}
+	if calls[1].Req.Inches != 2 || calls[1].Err == nil || calls[0].Resp.Color != "red" {
+		t.Errorf("unexpected recorded calls %v", calls)
+	}
+}
+
+func TestHaberdasherMockStub(t *testing.T) {
+	mock := &HaberdasherMock{
+		MakeHatFunc: func(ctx context.Context, s *Size) (*Hat, error) {
+			return &Hat{Size: s.Inches}, nil
+		},
+	}
+	mock.ExpectMakeHat(&Size{Inches: 10})
+
+	s, client := ServerAndClient(mock, nil)
+	defer s.Close()
+
+	hat, err := client.MakeHat(context.Background(), &Size{Inches: 5})
+	if err != nil || hat.Size != 5 {
+		t.Errorf("stubbed call hat=%v err=%v", hat, err)
+	}
+
+	ft := &fakeT{}
+	mock.AssertExpectations(ft)
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "expected call to MakeHat with request") {
+		t.Errorf("AssertExpectations errors=%q, want the unmet expectation", ft.errors)
+	}
+}
+
 func TestCustomRequestHeaders(t *testing.T) {
 	// Create a set of headers to be sent on all requests
 	customHeader := make(http.Header)
@@ -1669,3 +1873,648 @@ func TestRequestBodyError(t *testing.T) {
 		})
 	}
 }
+
+func TestServerMaxRequestBytes(t *testing.T) {
+	var hookErr twirp.Error
+	hooks := &twirp.ServerHooks{
+		Error: func(ctx context.Context, err twirp.Error) context.Context {
+			hookErr = err
+			return ctx
+		},
+	}
+
+	t.Run("within the limit", func(t *testing.T) {
+		s := httptest.NewServer(NewHaberdasherServer(NoopHatmaker(), twirp.WithServerHooks(hooks), twirp.WithServerMaxRequestBytes(64)))
+		defer s.Close()
+
+		for name, client := range map[string]Haberdasher{
+			"json":     NewHaberdasherJSONClient(s.URL, http.DefaultClient),
+			"protobuf": NewHaberdasherProtobufClient(s.URL, http.DefaultClient),
+		} {
+			if _, err := client.MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+				t.Errorf("%s client: unexpected error: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("over the limit", func(t *testing.T) {
+		s := httptest.NewServer(NewHaberdasherServer(NoopHatmaker(), twirp.WithServerHooks(hooks), twirp.WithServerMaxRequestBytes(1)))
+		defer s.Close()
+
+		for name, client := range map[string]Haberdasher{
+			"json":     NewHaberdasherJSONClient(s.URL, http.DefaultClient),
+			"protobuf": NewHaberdasherProtobufClient(s.URL, http.DefaultClient),
+		} {
+			hookErr = nil
+			_, err := client.MakeHat(context.Background(), &Size{Inches: 1})
+			twerr, ok := err.(twirp.Error)
+			if !ok {
+				t.Fatalf("%s client: expected twirp.Error, have %T: %v", name, err, err)
+			}
+			if twerr.Code() != twirp.ResourceExhausted {
+				t.Errorf("%s client: expected error code %q, have %q", name, twirp.ResourceExhausted, twerr.Code())
+			}
+			if want := "request body exceeds the maximum size of 1 bytes"; twerr.Msg() != want {
+				t.Errorf("%s client: expected error msg %q, have %q", name, want, twerr.Msg())
+			}
+			if hookErr == nil || hookErr.Code() != twirp.ResourceExhausted {
+				t.Errorf("%s client: expected Error hook to be called with %q, have %v", name, twirp.ResourceExhausted, hookErr)
+			}
+		}
+	})
+
+	t.Run("over the limit without Content-Length", func(t *testing.T) {
+		server := NewHaberdasherServer(NoopHatmaker(), twirp.WithServerMaxRequestBytes(4))
+		body := io.MultiReader(strings.NewReader(`{"inches":`), strings.NewReader(`1}`)) // unknown length
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", body)
+		req.Header.Set("Content-Type", "application/json")
+		if req.ContentLength != 0 {
+			t.Fatalf("test request is expected to have no Content-Length, have %d", req.ContentLength)
+		}
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if have, want := w.Code, http.StatusTooManyRequests; have != want {
+			t.Errorf("expected status %d, have %d, body: %s", want, have, w.Body.String())
+		}
+	})
+
+	t.Run("method override", func(t *testing.T) {
+		s := httptest.NewServer(NewHaberdasherServer(NoopHatmaker(),
+			twirp.WithServerMaxRequestBytes(1),
+			twirp.WithServerMethodMaxRequestBytes("MakeHat", 0), // no limit
+		))
+		defer s.Close()
+
+		client := NewHaberdasherJSONClient(s.URL, http.DefaultClient)
+		if _, err := client.MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+			t.Errorf("unexpected error with per-method limit override: %v", err)
+		}
+	})
+}
+
+func TestClientMaxResponseBytes(t *testing.T) {
+	longName := strings.Repeat("x", 1000)
+	h := HaberdasherFunc(func(ctx context.Context, s *Size) (*Hat, error) {
+		if s.Inches < 0 {
+			return nil, twirp.InvalidArgumentError("Inches", longName)
+		}
+		return &Hat{Size: s.Inches, Name: longName}, nil
+	})
+	s := httptest.NewServer(NewHaberdasherServer(h))
+	defer s.Close()
+
+	for name, newClient := range map[string]func(string, HTTPClient, ...twirp.ClientOption) Haberdasher{
+		"json":     NewHaberdasherJSONClient,
+		"protobuf": NewHaberdasherProtobufClient,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var hookErr twirp.Error
+			hooks := &twirp.ClientHooks{
+				Error: func(ctx context.Context, err twirp.Error) { hookErr = err },
+			}
+
+			client := newClient(s.URL, http.DefaultClient, twirp.WithClientMaxResponseBytes(10000))
+			if _, err := client.MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+				t.Errorf("unexpected error within the limit: %v", err)
+			}
+
+			client = newClient(s.URL, http.DefaultClient, twirp.WithClientMaxResponseBytes(100), twirp.WithClientHooks(hooks))
+			for _, size := range []int32{1, -1} { // valid response and error response
+				hookErr = nil
+				_, err := client.MakeHat(context.Background(), &Size{Inches: size})
+				twerr, ok := err.(twirp.Error)
+				if !ok {
+					t.Fatalf("expected twirp.Error, have %T: %v", err, err)
+				}
+				if twerr.Code() != twirp.ResourceExhausted {
+					t.Errorf("expected error code %q, have %q: %v", twirp.ResourceExhausted, twerr.Code(), twerr)
+				}
+				if hookErr == nil || hookErr.Code() != twirp.ResourceExhausted {
+					t.Errorf("expected Error hook to be called with %q, have %v", twirp.ResourceExhausted, hookErr)
+				}
+			}
+		})
+	}
+}
+
+func TestCompression(t *testing.T) {
+	longName := strings.Repeat("top hat ", 100)
+	h := HaberdasherFunc(func(ctx context.Context, s *Size) (*Hat, error) {
+		return &Hat{Size: s.Inches, Name: longName}, nil
+	})
+	s := httptest.NewServer(NewHaberdasherServer(h, twirp.WithServerCompression("gzip"), twirp.WithServerCompressionMinBytes(100)))
+	defer s.Close()
+
+	var reqHeader, respHeader http.Header
+	rw := &reqRewriter{
+		base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			reqHeader = req.Header.Clone()
+			resp, err := http.DefaultTransport.RoundTrip(req)
+			if resp != nil {
+				respHeader = resp.Header.Clone()
+			}
+			return resp, err
+		}),
+		rewrite: func(r *http.Request) *http.Request { return r },
+	}
+	httpClient := &http.Client{Transport: rw}
+
+	for name, newClient := range map[string]func(string, HTTPClient, ...twirp.ClientOption) Haberdasher{
+		"json":     NewHaberdasherJSONClient,
+		"protobuf": NewHaberdasherProtobufClient,
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := newClient(s.URL, httpClient, twirp.WithClientCompression("gzip"), twirp.WithClientCompressionMinBytes(0))
+			hat, err := client.MakeHat(context.Background(), &Size{Inches: 1})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hat.Name != longName {
+				t.Errorf("unexpected hat name after decompression: %q", hat.Name)
+			}
+			if have, want := reqHeader.Get("Content-Encoding"), "gzip"; have != want {
+				t.Errorf("unexpected request Content-Encoding, have: %q, want: %q", have, want)
+			}
+			if have, want := reqHeader.Get("Accept-Encoding"), "gzip"; have != want {
+				t.Errorf("unexpected request Accept-Encoding, have: %q, want: %q", have, want)
+			}
+			if have, want := respHeader.Get("Content-Encoding"), "gzip"; have != want {
+				t.Errorf("unexpected response Content-Encoding, have: %q, want: %q", have, want)
+			}
+			if have, want := respHeader.Get("Vary"), "Accept-Encoding"; have != want {
+				t.Errorf("unexpected response Vary header, have: %q, want: %q", have, want)
+			}
+
+			// Small requests are not compressed
+			client = newClient(s.URL, httpClient, twirp.WithClientCompression("gzip"))
+			if _, err = client.MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if have := reqHeader.Get("Content-Encoding"); have != "" {
+				t.Errorf("small request is not expected to be compressed, have Content-Encoding: %q", have)
+			}
+		})
+	}
+
+	t.Run("not accepted by the client", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", strings.NewReader(`{"inches":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Encoding", "identity, gzip;q=0")
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if have := resp.Header.Get("Content-Encoding"); have != "" {
+			t.Errorf("response is not expected to be compressed, have Content-Encoding: %q", have)
+		}
+	})
+
+	t.Run("unsupported request encoding", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", strings.NewReader(`{"inches":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", "br")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if have, want := resp.StatusCode, http.StatusBadRequest; have != want {
+			t.Errorf("unexpected status code, have: %d, want: %d", have, want)
+		}
+	})
+}
+
+func TestRequestTimeout(t *testing.T) {
+	var handlerDeadline time.Time
+	var handlerHasDeadline bool
+	h := HaberdasherFunc(func(ctx context.Context, s *Size) (*Hat, error) {
+		handlerDeadline, handlerHasDeadline = ctx.Deadline()
+		if s.Inches == 0 { // wait for the timeout
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &Hat{Size: s.Inches}, nil
+	})
+
+	var reqHeader http.Header
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		reqHeader = req.Header.Clone()
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	t.Run("propagated from client to server", func(t *testing.T) {
+		s := httptest.NewServer(NewHaberdasherServer(h))
+		defer s.Close()
+		client := NewHaberdasherProtobufClient(s.URL, httpClient)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := client.MakeHat(ctx, &Size{Inches: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		timeoutMs, err := strconv.ParseInt(reqHeader.Get("Twirp-Timeout"), 10, 64)
+		if err != nil {
+			t.Fatalf("invalid Twirp-Timeout header %q: %v", reqHeader.Get("Twirp-Timeout"), err)
+		}
+		if timeoutMs <= 59000 || timeoutMs > 60000 {
+			t.Errorf("unexpected Twirp-Timeout header: %d", timeoutMs)
+		}
+		if !handlerHasDeadline {
+			t.Fatal("expected the handler context to have a deadline")
+		}
+		if remaining := time.Until(handlerDeadline); remaining <= 58*time.Second || remaining > time.Minute {
+			t.Errorf("unexpected handler deadline, remaining: %v", remaining)
+		}
+
+		// No deadline, no header
+		if _, err := client.MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if have := reqHeader.Get("Twirp-Timeout"); have != "" {
+			t.Errorf("unexpected Twirp-Timeout header without deadline: %q", have)
+		}
+		if handlerHasDeadline {
+			t.Error("expected the handler context to not have a deadline")
+		}
+	})
+
+	t.Run("clamped by the server", func(t *testing.T) {
+		s := httptest.NewServer(NewHaberdasherServer(h, twirp.WithServerMaxRequestTimeout(time.Second)))
+		defer s.Close()
+		client := NewHaberdasherJSONClient(s.URL, httpClient)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := client.MakeHat(ctx, &Size{Inches: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if remaining := time.Until(handlerDeadline); remaining > time.Second {
+			t.Errorf("expected the handler deadline to be clamped to 1s, remaining: %v", remaining)
+		}
+	})
+
+	t.Run("disabled by the server", func(t *testing.T) {
+		s := httptest.NewServer(NewHaberdasherServer(h, twirp.WithServerRequestTimeouts(false)))
+		defer s.Close()
+		client := NewHaberdasherProtobufClient(s.URL, httpClient)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := client.MakeHat(ctx, &Size{Inches: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if handlerHasDeadline {
+			t.Error("expected the handler context to not have a deadline")
+		}
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		s := httptest.NewServer(NewHaberdasherServer(h))
+		defer s.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", strings.NewReader(`{"inches":0}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Twirp-Timeout", "10")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if have, want := resp.StatusCode, twirp.ServerHTTPStatusFromErrorCode(twirp.DeadlineExceeded); have != want {
+			t.Errorf("unexpected status code, have: %d, want: %d", have, want)
+		}
+	})
+
+	t.Run("handler ignoring the context", func(t *testing.T) {
+		slow := HaberdasherFunc(func(ctx context.Context, s *Size) (*Hat, error) {
+			time.Sleep(50 * time.Millisecond) // does not check ctx
+			return &Hat{Size: s.Inches}, nil
+		})
+		s := httptest.NewServer(NewHaberdasherServer(slow))
+		defer s.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", strings.NewReader(`{"inches":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Twirp-Timeout", "10")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if have, want := resp.StatusCode, twirp.ServerHTTPStatusFromErrorCode(twirp.DeadlineExceeded); have != want {
+			t.Errorf("unexpected status code, have: %d, want: %d", have, want)
+		}
+	})
+
+	t.Run("deadline set by middleware", func(t *testing.T) {
+		slow := HaberdasherFunc(func(ctx context.Context, s *Size) (*Hat, error) {
+			<-ctx.Done() // the response is not replaced without a Twirp-Timeout header
+			return &Hat{Size: s.Inches}, nil
+		})
+		server := NewHaberdasherServer(slow)
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), 10*time.Millisecond)
+			defer cancel()
+			server.ServeHTTP(w, r.WithContext(ctx))
+		}))
+		defer s.Close()
+
+		client := NewHaberdasherJSONClient(s.URL, http.DefaultClient)
+		hat, err := client.MakeHat(context.Background(), &Size{Inches: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if hat.Size != 1 {
+			t.Errorf("unexpected hat size, have: %d, want: 1", hat.Size)
+		}
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		s := httptest.NewServer(NewHaberdasherServer(h))
+		defer s.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", strings.NewReader(`{"inches":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Twirp-Timeout", "1s")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if have, want := resp.StatusCode, http.StatusBadRequest; have != want {
+			t.Errorf("unexpected status code, have: %d, want: %d", have, want)
+		}
+	})
+}
+
+func TestErrorDetails(t *testing.T) {
+	h := HaberdasherFunc(func(ctx context.Context, s *Size) (*Hat, error) {
+		twerr := twirp.InvalidArgumentError("inches", "is too small")
+		return nil, twirp.WithErrorDetails(twerr,
+			&Size{Inches: 1},
+			wrapperspb.String("min size is 1"),
+			&emptypb.Empty{},
+			json.RawMessage(`{"@type":"type.googleapis.com/unknown.Type","foo":"bar"}`),
+		)
+	})
+	s := httptest.NewServer(NewHaberdasherServer(h))
+	defer s.Close()
+
+	for name, client := range map[string]Haberdasher{
+		"json":     NewHaberdasherJSONClient(s.URL, http.DefaultClient),
+		"protobuf": NewHaberdasherProtobufClient(s.URL, http.DefaultClient),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.MakeHat(context.Background(), &Size{Inches: 0})
+			var twerr twirp.Error
+			if !errors.As(err, &twerr) {
+				t.Fatalf("expected twirp error, have: %v", err)
+			}
+			if twerr.Code() != twirp.InvalidArgument || twerr.Meta("argument") != "inches" {
+				t.Errorf("unexpected error: %v", twerr)
+			}
+
+			details := twirp.ErrorDetails(err)
+			if len(details) != 4 {
+				t.Fatalf("expected 4 details, have: %v", details)
+			}
+			if size, ok := details[0].(*Size); !ok || size.Inches != 1 {
+				t.Errorf("unexpected detail: %#v", details[0])
+			}
+			if str, ok := details[1].(*wrapperspb.StringValue); !ok || str.Value != "min size is 1" {
+				t.Errorf("unexpected detail: %#v", details[1])
+			}
+			if _, ok := details[2].(*emptypb.Empty); !ok {
+				t.Errorf("unexpected detail: %#v", details[2])
+			}
+			raw, ok := details[3].(json.RawMessage)
+			if !ok {
+				t.Fatalf("unexpected detail: %#v", details[3])
+			}
+			var unknown map[string]string
+			if err := json.Unmarshal(raw, &unknown); err != nil || unknown["foo"] != "bar" {
+				t.Errorf("unexpected detail: %s", raw)
+			}
+		})
+	}
+
+	t.Run("wire format", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Twirp-Error-Details", "true")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		var body struct {
+			Code    string
+			Details []map[string]interface{}
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("unexpected error decoding response: %v", err)
+		}
+		if len(body.Details) != 4 {
+			t.Fatalf("expected 4 details, have: %v", body.Details)
+		}
+		if have, want := body.Details[0], (map[string]interface{}{"@type": "type.googleapis.com/twirp.internal.twirptest.Size", "inches": float64(1)}); !reflect.DeepEqual(have, want) {
+			t.Errorf("unexpected detail, have: %v, want: %v", have, want)
+		}
+		if have, want := body.Details[1], (map[string]interface{}{"@type": "type.googleapis.com/google.protobuf.StringValue", "value": "min size is 1"}); !reflect.DeepEqual(have, want) {
+			t.Errorf("unexpected detail, have: %v, want: %v", have, want)
+		}
+	})
+
+	t.Run("clients without details support", func(t *testing.T) {
+		resp, err := http.Post(s.URL+"/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// clients generated before v8.3.0 reject error responses with unknown fields
+		var twerrJSON struct {
+			Code string
+			Msg  string
+			Meta map[string]string
+		}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&twerrJSON); err != nil || twerrJSON.Code != "invalid_argument" {
+			t.Errorf("expected an error response without details, have: %s", body)
+		}
+	})
+}
+
+func TestMux(t *testing.T) {
+	var calls []string
+	record := func(name string) *twirp.ServerHooks {
+		return &twirp.ServerHooks{
+			RequestRouted: func(ctx context.Context) (context.Context, error) {
+				calls = append(calls, name+" hook")
+				return ctx, nil
+			},
+		}
+	}
+	interceptor := func(name string) twirp.Interceptor {
+		return func(next twirp.Method) twirp.Method {
+			return func(ctx context.Context, req interface{}) (interface{}, error) {
+				calls = append(calls, name+" interceptor")
+				return next(ctx, req)
+			}
+		}
+	}
+
+	h1 := NewHaberdasherServer(NoopHatmaker(), twirp.WithServerHooks(record("server1")), twirp.WithServerInterceptors(interceptor("server1")))
+	h2 := NewHaberdasherServer(NoopHatmaker(), twirp.WithServerPathPrefix("/other"))
+	mux, err := twirp.NewMux([]twirp.Server{h1, h2}, twirp.WithServerHooks(record("mux")), twirp.WithServerInterceptors(interceptor("mux")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if services := mux.Services(); len(services) != 2 ||
+		services[0].Name != "twirp.internal.twirptest.Haberdasher" ||
+		!reflect.DeepEqual(services[0].Methods, []string{"MakeHat"}) {
+		t.Errorf("unexpected services: %+v", services)
+	}
+
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	if _, err := NewHaberdasherJSONClient(s.URL, http.DefaultClient).MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"mux hook", "server1 hook", "mux interceptor", "server1 interceptor"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("unexpected calls, have: %v, want: %v", calls, want)
+	}
+
+	calls = nil
+	if _, err := NewHaberdasherProtobufClient(s.URL, http.DefaultClient, twirp.WithClientPathPrefix("/other")).MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"mux hook", "mux interceptor"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("unexpected calls, have: %v, want: %v", calls, want)
+	}
+
+	_, err = NewHaberdasherJSONClient(s.URL, http.DefaultClient, twirp.WithClientPathPrefix("/unknown")).MakeHat(context.Background(), &Size{Inches: 1})
+	var twerr twirp.Error
+	if !errors.As(err, &twerr) || twerr.Code() != twirp.BadRoute {
+		t.Errorf("expected bad_route error, have: %v", err)
+	}
+}
+
+// protobufCodec is a twirp.Codec that serializes Protobuf messages with a
+// custom content type, and counts the calls to Marshal and Unmarshal.
+type protobufCodec struct {
+	contentType string
+	calls       int32
+}
+
+func (c *protobufCodec) ContentType() string { return c.contentType }
+
+func (c *protobufCodec) Marshal(msg interface{}) ([]byte, error) {
+	atomic.AddInt32(&c.calls, 1)
+	return proto.Marshal(msg.(proto.Message))
+}
+
+func (c *protobufCodec) Unmarshal(data []byte, msg interface{}) error {
+	atomic.AddInt32(&c.calls, 1)
+	return proto.Unmarshal(data, msg.(proto.Message))
+}
+
+func TestCodecs(t *testing.T) {
+	serverCodec := &protobufCodec{contentType: "application/x-protobuf"}
+	s := httptest.NewServer(NewHaberdasherServer(NoopHatmaker(), twirp.WithServerCodecs(serverCodec)))
+	defer s.Close()
+
+	var reqHeader, respHeader http.Header
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		reqHeader = req.Header.Clone()
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if resp != nil {
+			respHeader = resp.Header.Clone()
+		}
+		return resp, err
+	})}
+
+	t.Run("custom content type", func(t *testing.T) {
+		clientCodec := &protobufCodec{contentType: "application/x-protobuf"}
+		client := NewHaberdasherJSONClient(s.URL, httpClient, twirp.WithClientCodec(clientCodec))
+		if _, err := client.MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if have, want := reqHeader.Get("Content-Type"), "application/x-protobuf"; have != want {
+			t.Errorf("unexpected request Content-Type, have: %q, want: %q", have, want)
+		}
+		if have, want := respHeader.Get("Content-Type"), "application/x-protobuf"; have != want {
+			t.Errorf("unexpected response Content-Type, have: %q, want: %q", have, want)
+		}
+		if have := atomic.LoadInt32(&clientCodec.calls); have != 2 {
+			t.Errorf("expected the client codec to marshal the request and unmarshal the response, have %d calls", have)
+		}
+		if have := atomic.LoadInt32(&serverCodec.calls); have != 2 {
+			t.Errorf("expected the server codec to unmarshal the request and marshal the response, have %d calls", have)
+		}
+	})
+
+	t.Run("built-in content types", func(t *testing.T) {
+		atomic.StoreInt32(&serverCodec.calls, 0)
+		for _, client := range []Haberdasher{
+			NewHaberdasherJSONClient(s.URL, httpClient),
+			NewHaberdasherProtobufClient(s.URL, httpClient),
+		} {
+			if _, err := client.MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if have := atomic.LoadInt32(&serverCodec.calls); have != 0 {
+			t.Errorf("the server codec is not expected to be used, have %d calls", have)
+		}
+	})
+
+	t.Run("replace built-in codec", func(t *testing.T) {
+		codec := &protobufCodec{contentType: "application/protobuf"}
+		s := httptest.NewServer(NewHaberdasherServer(NoopHatmaker(), twirp.WithServerCodecs(codec)))
+		defer s.Close()
+		if _, err := NewHaberdasherProtobufClient(s.URL, http.DefaultClient).MakeHat(context.Background(), &Size{Inches: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if have := atomic.LoadInt32(&codec.calls); have != 2 {
+			t.Errorf("expected the server codec to replace the built-in protobuf codec, have %d calls", have)
+		}
+	})
+
+	t.Run("malformed request", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/twirp/twirp.internal.twirptest.Haberdasher/MakeHat", strings.NewReader("not protobuf"))
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		respBytes, _ := io.ReadAll(resp.Body)
+		if have, want := resp.StatusCode, http.StatusBadRequest; have != want {
+			t.Errorf("unexpected status code, have: %d, want: %d", have, want)
+		}
+		if !strings.Contains(string(respBytes), "the application/x-protobuf request could not be decoded") {
+			t.Errorf("unexpected response: %q", respBytes)
+		}
+	})
+
+	t.Run("unknown content type", func(t *testing.T) {
+		codec := &protobufCodec{contentType: "application/x-unknown"}
+		_, err := NewHaberdasherProtobufClient(s.URL, http.DefaultClient, twirp.WithClientCodec(codec)).MakeHat(context.Background(), &Size{Inches: 1})
+		var twerr twirp.Error
+		if !errors.As(err, &twerr) || twerr.Code() != twirp.BadRoute {
+			t.Errorf("expected bad_route error, have: %v", err)
+		}
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -88,3 +88,29 @@ func TestServiceMethodNamesUnderscores(t *testing.T) {
 		t.Fatalf("expected error to be about the expected path, got err=%q", err)
 	}
 }
+
+// A twirp.Mux routes both CamelCased and literal routes to the server.
+func TestMuxServiceMethodNamesUnderscores(t *testing.T) {
+	mux, err := twirp.NewMux([]twirp.Server{NewHaberdasherV1Server(&HaberdasherService{})})
+	if err != nil {
+		t.Fatalf("NewMux err=%q", err)
+	}
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	clients := map[string]HaberdasherV1{
+		"literal":     NewHaberdasherV1ProtobufClient(s.URL, http.DefaultClient, twirp.WithClientLiteralURLs(true)),
+		"camel cased": NewHaberdasherV1ProtobufClient(s.URL, http.DefaultClient),
+	}
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			hat, err := client.MakeHatV1(context.Background(), &MakeHatArgsV1_SizeV1{Inches: 1})
+			if err != nil {
+				t.Fatalf("MakeHatV1 err=%q", err)
+			}
+			if hat.Size != 1 {
+				t.Errorf("wrong hat size returned")
+			}
+		})
+	}
+}
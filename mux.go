@@ -0,0 +1,326 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/twitchtv/twirp/internal/contextkeys"
+)
+
+// Server is the interface implemented by generated Twirp servers (the
+// TwirpServer interface in generated code), used to register them in a Mux.
+type Server interface {
+	http.Handler
+
+	// ServiceDescriptor returns gzipped bytes describing the .proto file that
+	// the service was generated from, and the index of the service in it.
+	ServiceDescriptor() ([]byte, int)
+
+	// ProtocGenTwirpVersion is the version of twirp used to generate the server.
+	ProtocGenTwirpVersion() string
+
+	// PathPrefix returns the HTTP URL path prefix for all methods handled by
+	// the service, in the form: "/<prefix>/<package>.<Service>/".
+	PathPrefix() string
+}
+
+// muxOptionsServer is implemented by servers generated since v8.3.0, which apply
+// the hooks and interceptors shared by a Mux (see twirpruntime.MuxServerOptions).
+// Older servers would silently skip them.
+type muxOptionsServer interface {
+	AppliesMuxServerOptions()
+}
+
+// MuxService describes a service registered in a Mux.
+type MuxService struct {
+	// Name is the fully qualified name of the service, i.e. "<package>.<Service>".
+	Name string
+
+	// PathPrefix is the HTTP URL path prefix for all methods of the service.
+	PathPrefix string
+
+	// Methods are the names of the service methods, as used in routes.
+	Methods []string
+
+	// ProtocGenTwirpVersion is the version of twirp used to generate the server.
+	ProtocGenTwirpVersion string
+}
+
+// Mux is an http.Handler that routes requests to multiple Twirp servers by
+// their path prefix. Requests that don't match any registered service get a
+// Twirp bad_route error response, instead of the plain text 404 response of
+// http.ServeMux.
+type Mux struct {
+	servers  map[string]Server // by path prefix, CamelCased and literal
+	services []MuxService      // sorted by path prefix
+	opts     *muxServerOptions // nil if there are no shared hooks or interceptors
+}
+
+type muxServerOptions struct {
+	hooks       *ServerHooks
+	interceptor Interceptor
+}
+
+// NewMux returns a Mux that routes requests to the given servers. It returns
+// an error if the path prefixes of two servers collide.
+//
+// Hooks and interceptors given with the options (WithServerHooks and
+// WithServerInterceptors) are shared by all the servers: they are applied
+// before the hooks and interceptors of each server. Hooks are also called for
+// requests that don't match any server. Other options are ignored; they must
+// be used when building each server. Servers generated by protoc-gen-twirp
+// versions older than v8.3.0 can't apply shared hooks and interceptors, NewMux
+// returns an error if they are registered with them.
+func NewMux(servers []Server, opts ...ServerOption) (*Mux, error) {
+	serverOpts := &ServerOptions{}
+	for _, opt := range opts {
+		opt(serverOpts)
+	}
+
+	m := &Mux{servers: make(map[string]Server, len(servers))}
+	if serverOpts.Hooks != nil || len(serverOpts.Interceptors) > 0 {
+		m.opts = &muxServerOptions{
+			hooks:       serverOpts.Hooks,
+			interceptor: ChainInterceptors(serverOpts.Interceptors...),
+		}
+	}
+
+	for _, server := range servers {
+		prefix := server.PathPrefix()
+		if !strings.HasPrefix(prefix, "/") || !strings.HasSuffix(prefix, "/") {
+			return nil, fmt.Errorf("twirp.NewMux: invalid path prefix %q, expected \"/<prefix>/<package>.<Service>/\"", prefix)
+		}
+		if _, ok := server.(muxOptionsServer); !ok && m.opts != nil {
+			return nil, fmt.Errorf("twirp.NewMux: server with path prefix %q was generated by protoc-gen-twirp %s and does not apply shared hooks and interceptors, regenerate it or set them on the server", prefix, server.ProtocGenTwirpVersion())
+		}
+		service, err := newMuxService(server)
+		if err != nil {
+			return nil, fmt.Errorf("twirp.NewMux: invalid server with path prefix %q: %w", prefix, err)
+		}
+		// Servers also accept routes with the literal (not CamelCased) service name,
+		// sent by clients in other languages and Go clients with WithClientLiteralURLs.
+		prefixes := []string{prefix}
+		if literal := literalPathPrefix(prefix, service.Name); literal != prefix {
+			prefixes = append(prefixes, literal)
+		}
+		for _, p := range prefixes {
+			for otherPrefix := range m.servers {
+				if strings.HasPrefix(p, otherPrefix) || strings.HasPrefix(otherPrefix, p) {
+					return nil, fmt.Errorf("twirp.NewMux: path prefix %q collides with path prefix %q", p, otherPrefix)
+				}
+			}
+			m.servers[p] = server
+		}
+		m.services = append(m.services, service)
+	}
+	sort.Slice(m.services, func(i, j int) bool {
+		return m.services[i].PathPrefix < m.services[j].PathPrefix
+	})
+	return m, nil
+}
+
+// literalPathPrefix replaces the "<package>.<Service>" segment at the end of a path
+// prefix, which is CamelCased by generated servers, with the literal service name.
+func literalPathPrefix(prefix, name string) string {
+	trimmed := strings.TrimSuffix(prefix, "/")
+	return trimmed[:strings.LastIndex(trimmed, "/")+1] + name + "/"
+}
+
+// Services returns the services registered in the mux, sorted by path prefix.
+func (m *Mux) Services() []MuxService {
+	services := make([]MuxService, len(m.services))
+	for i, s := range m.services {
+		s.Methods = append([]string(nil), s.Methods...)
+		services[i] = s
+	}
+	return services
+}
+
+// ServeHTTP routes the request to the server with a matching path prefix.
+func (m *Mux) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+	prefix := path[:strings.LastIndex(path, "/")+1] // routes are "<PathPrefix><Method>"
+	server, ok := m.servers[prefix]
+	if !ok {
+		m.writeBadRoute(resp, req)
+		return
+	}
+
+	if m.opts != nil {
+		// Read by generated servers with twirpruntime.MuxServerOptions
+		ctx := context.WithValue(req.Context(), contextkeys.MuxServerHooksKey, m.opts.hooks)
+		ctx = context.WithValue(ctx, contextkeys.MuxInterceptorKey, m.opts.interceptor)
+		req = req.WithContext(ctx)
+	}
+	server.ServeHTTP(resp, req)
+}
+
+// writeBadRoute writes a bad_route error for requests that don't match any
+// server, calling the shared hooks like generated servers do.
+func (m *Mux) writeBadRoute(resp http.ResponseWriter, req *http.Request) {
+	var hooks *ServerHooks
+	if m.opts != nil {
+		hooks = m.opts.hooks
+	}
+
+	ctx := req.Context()
+	ctx = context.WithValue(ctx, contextkeys.ResponseWriterKey, resp)
+
+	var err error = NewError(BadRoute, fmt.Sprintf("no handler for path %q", req.URL.Path)).
+		WithMeta("twirp_invalid_route", req.Method+" "+req.URL.Path)
+	if hooks != nil && hooks.RequestReceived != nil {
+		var hookErr error
+		ctx, hookErr = hooks.RequestReceived(ctx)
+		if hookErr != nil {
+			err = hookErr
+		}
+	}
+
+	var twerr Error
+	if !errors.As(err, &twerr) {
+		twerr = InternalErrorWith(err)
+	}
+	if hooks != nil && hooks.Error != nil {
+		statusCode := ServerHTTPStatusFromErrorCode(twerr.Code())
+		ctx = context.WithValue(ctx, contextkeys.StatusCodeKey, fmt.Sprint(statusCode))
+		ctx = hooks.Error(ctx, twerr)
+	}
+	_ = WriteError(resp, twerr) // the connection is likely broken if writing fails, like in generated servers
+	if hooks != nil && hooks.ResponseSent != nil {
+		hooks.ResponseSent(ctx)
+	}
+}
+
+// newMuxService describes the service of a generated server, reading the
+// service and method names from its gzipped FileDescriptorProto. The protobuf
+// wire format is decoded by hand, the twirp package has no protobuf dependency.
+func newMuxService(server Server) (MuxService, error) {
+	gzipped, index := server.ServiceDescriptor()
+	zr, err := gzip.NewReader(bytes.NewReader(gzipped))
+	if err != nil {
+		return MuxService{}, fmt.Errorf("failed to read service descriptor: %w", err)
+	}
+	fileDesc, err := io.ReadAll(zr)
+	if err != nil {
+		return MuxService{}, fmt.Errorf("failed to read service descriptor: %w", err)
+	}
+
+	const (
+		filePackageField   = 2 // FileDescriptorProto.package
+		fileServiceField   = 6 // FileDescriptorProto.service
+		serviceNameField   = 1 // ServiceDescriptorProto.name
+		serviceMethodField = 2 // ServiceDescriptorProto.method
+		methodNameField    = 1 // MethodDescriptorProto.name
+	)
+
+	var pkg string
+	var serviceDesc []byte
+	serviceIndex := 0
+	err = rangeProtoFields(fileDesc, func(num int, val []byte) {
+		switch num {
+		case filePackageField:
+			pkg = string(val)
+		case fileServiceField:
+			if serviceIndex == index {
+				serviceDesc = val
+			}
+			serviceIndex++
+		}
+	})
+	if err != nil {
+		return MuxService{}, fmt.Errorf("failed to parse service descriptor: %w", err)
+	}
+	if serviceDesc == nil {
+		return MuxService{}, fmt.Errorf("service index %d not found in service descriptor", index)
+	}
+
+	var name string
+	var methods []string
+	err = rangeProtoFields(serviceDesc, func(num int, val []byte) {
+		switch num {
+		case serviceNameField:
+			name = string(val)
+		case serviceMethodField:
+			_ = rangeProtoFields(val, func(num int, val []byte) {
+				if num == methodNameField {
+					methods = append(methods, string(val))
+				}
+			})
+		}
+	})
+	if err != nil {
+		return MuxService{}, fmt.Errorf("failed to parse service descriptor: %w", err)
+	}
+	if pkg != "" {
+		name = pkg + "." + name
+	}
+
+	return MuxService{
+		Name:                  name,
+		PathPrefix:            server.PathPrefix(),
+		Methods:               methods,
+		ProtocGenTwirpVersion: server.ProtocGenTwirpVersion(),
+	}, nil
+}
+
+// rangeProtoFields calls fn with the number and value of each length-delimited
+// field (strings and embedded messages) of a message in the protobuf wire
+// format. Fields of other types are skipped.
+func rangeProtoFields(msg []byte, fn func(num int, val []byte)) error {
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return errors.New("invalid field key")
+		}
+		msg = msg[n:]
+		num, wireType := int(key>>3), key&7
+		switch wireType {
+		case 0: // varint
+			if _, n = binary.Uvarint(msg); n <= 0 {
+				return errors.New("invalid varint field")
+			}
+			msg = msg[n:]
+		case 1: // fixed64
+			if len(msg) < 8 {
+				return errors.New("invalid fixed64 field")
+			}
+			msg = msg[8:]
+		case 2: // length-delimited
+			size, n := binary.Uvarint(msg)
+			if n <= 0 || size > uint64(len(msg)-n) {
+				return errors.New("invalid length-delimited field")
+			}
+			fn(num, msg[n:n+int(size)])
+			msg = msg[n+int(size):]
+		case 5: // fixed32
+			if len(msg) < 4 {
+				return errors.New("invalid fixed32 field")
+			}
+			msg = msg[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d", wireType)
+		}
+	}
+	return nil
+}
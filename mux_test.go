@@ -0,0 +1,170 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/twitchtv/twirp"
+)
+
+// fakeServer implements twirp.Server with a hand-encoded service descriptor.
+type fakeServer struct {
+	pkg, service string
+	methods      []string
+	prefix       string
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(s.service))
+}
+
+func (s *fakeServer) ProtocGenTwirpVersion() string { return "v0.0.0-test" }
+func (s *fakeServer) PathPrefix() string            { return s.prefix }
+func (s *fakeServer) AppliesMuxServerOptions()      {}
+
+// legacyServer is like a server generated before v8.3.0, which does not apply the
+// hooks and interceptors shared by a Mux.
+type legacyServer struct {
+	twirp.Server
+}
+
+func (s *fakeServer) ServiceDescriptor() ([]byte, int) {
+	var serviceDesc []byte
+	serviceDesc = appendProtoField(serviceDesc, 1, []byte(s.service))
+	for _, m := range s.methods {
+		serviceDesc = appendProtoField(serviceDesc, 2, appendProtoField(nil, 1, []byte(m)))
+	}
+	var fileDesc []byte
+	fileDesc = appendProtoField(fileDesc, 1, []byte("test.proto"))
+	fileDesc = appendProtoField(fileDesc, 2, []byte(s.pkg))
+	fileDesc = appendProtoField(fileDesc, 6, appendProtoField(nil, 1, []byte("OtherService")))
+	fileDesc = appendProtoField(fileDesc, 6, serviceDesc)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(fileDesc)
+	_ = zw.Close()
+	return buf.Bytes(), 1
+}
+
+func appendProtoField(b []byte, num int, val []byte) []byte {
+	varint := make([]byte, binary.MaxVarintLen64)
+	b = append(b, varint[:binary.PutUvarint(varint, uint64(num<<3|2))]...)
+	b = append(b, varint[:binary.PutUvarint(varint, uint64(len(val)))]...)
+	return append(b, val...)
+}
+
+func TestMux(t *testing.T) {
+	foo := &fakeServer{pkg: "pkg", service: "Foo", methods: []string{"A", "B"}, prefix: "/twirp/pkg.Foo/"}
+	bar := &fakeServer{pkg: "pkg", service: "Bar", methods: []string{"C"}, prefix: "/custom/pkg.Bar/"}
+
+	var hooksCalled []string
+	hooks := &twirp.ServerHooks{
+		RequestReceived: func(ctx context.Context) (context.Context, error) {
+			hooksCalled = append(hooksCalled, "RequestReceived")
+			return ctx, nil
+		},
+		Error: func(ctx context.Context, err twirp.Error) context.Context {
+			if status, _ := twirp.StatusCode(ctx); status != "404" {
+				t.Errorf("unexpected status code in Error hook: %q", status)
+			}
+			hooksCalled = append(hooksCalled, "Error")
+			return ctx
+		},
+		ResponseSent: func(ctx context.Context) {
+			hooksCalled = append(hooksCalled, "ResponseSent")
+		},
+	}
+
+	mux, err := twirp.NewMux([]twirp.Server{foo, bar}, twirp.WithServerHooks(hooks))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantServices := []twirp.MuxService{
+		{Name: "pkg.Bar", PathPrefix: "/custom/pkg.Bar/", Methods: []string{"C"}, ProtocGenTwirpVersion: "v0.0.0-test"},
+		{Name: "pkg.Foo", PathPrefix: "/twirp/pkg.Foo/", Methods: []string{"A", "B"}, ProtocGenTwirpVersion: "v0.0.0-test"},
+	}
+	if have := mux.Services(); !reflect.DeepEqual(have, wantServices) {
+		t.Errorf("unexpected services, have: %+v, want: %+v", have, wantServices)
+	}
+
+	for path, want := range map[string]string{
+		"/twirp/pkg.Foo/A":  "Foo",
+		"/custom/pkg.Bar/C": "Bar",
+	} {
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, path, nil))
+		if have := resp.Body.String(); have != want {
+			t.Errorf("unexpected server for path %q, have: %q, want: %q", path, have, want)
+		}
+	}
+
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/twirp/pkg.Baz/A", strings.NewReader("{}")))
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code for unknown route: %d", resp.Code)
+	}
+	var gotTwerrJSON twerrJSON
+	if err := json.NewDecoder(resp.Body).Decode(&gotTwerrJSON); err != nil {
+		t.Fatalf("unexpected error decoding response: %v", err)
+	}
+	if gotTwerrJSON.Code != string(twirp.BadRoute) || gotTwerrJSON.Meta["twirp_invalid_route"] != "POST /twirp/pkg.Baz/A" {
+		t.Errorf("unexpected error response: %+v", gotTwerrJSON)
+	}
+	if want := []string{"RequestReceived", "Error", "ResponseSent"}; !reflect.DeepEqual(hooksCalled, want) {
+		t.Errorf("unexpected hooks called, have: %v, want: %v", hooksCalled, want)
+	}
+}
+
+func TestMuxPathPrefixCollisions(t *testing.T) {
+	foo := &fakeServer{pkg: "pkg", service: "Foo", prefix: "/twirp/pkg.Foo/"}
+	fooAgain := &fakeServer{pkg: "pkg", service: "Foo", prefix: "/twirp/pkg.Foo/"}
+	nested := &fakeServer{pkg: "pkg", service: "Foo", prefix: "/twirp/pkg.Foo/twirp/pkg.Foo/"}
+	invalid := &fakeServer{pkg: "pkg", service: "Foo", prefix: "twirp/pkg.Foo"}
+
+	for name, servers := range map[string][]twirp.Server{
+		"duplicated":     {foo, fooAgain},
+		"nested":         {nested, foo},
+		"invalid prefix": {invalid},
+	} {
+		if _, err := twirp.NewMux(servers); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMuxLegacyServers(t *testing.T) {
+	legacy := legacyServer{&fakeServer{pkg: "pkg", service: "Foo", prefix: "/twirp/pkg.Foo/"}}
+
+	if _, err := twirp.NewMux([]twirp.Server{legacy}); err != nil {
+		t.Errorf("unexpected error without shared options: %v", err)
+	}
+	if _, err := twirp.NewMux([]twirp.Server{legacy}, twirp.WithServerHooks(&twirp.ServerHooks{})); err == nil {
+		t.Errorf("expected an error with shared hooks")
+	}
+	if _, err := twirp.NewMux([]twirp.Server{legacy}, twirp.WithServerInterceptors()); err != nil {
+		t.Errorf("unexpected error without interceptors: %v", err)
+	}
+}
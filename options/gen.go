@@ -0,0 +1,20 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package options contains the Go types of twirp/options.proto, with options
+// read by protoc-gen-twirp to configure generated code, like method timeouts.
+// Generated code does not depend on this package, it is only needed to read
+// the options from descriptors.
+package options
+
+//go:generate protoc --go_out=module=github.com/twitchtv/twirp/options:. twirp/options.proto
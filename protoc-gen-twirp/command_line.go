@@ -19,10 +19,13 @@ import (
 )
 
 type commandLineParams struct {
-	importMap    map[string]string // Mapping from .proto file name to import path.
-	paths        string            // paths flag, used to control file output directory.
-	module       string            // module flag, Go import path prefix that is removed from the output filename.
-	importPrefix string            // prefix added to imported package file names.
+	importMap     map[string]string // Mapping from .proto file name to import path.
+	paths         string            // paths flag, used to control file output directory.
+	module        string            // module flag, Go import path prefix that is removed from the output filename.
+	importPrefix  string            // prefix added to imported package file names.
+	mocks         bool              // mocks flag, generate mock implementations of the service interfaces.
+	unimplemented bool              // unimplemented flag, generate Unimplemented<Service>Server structs.
+	generate      string            // generate flag, "client" or "server" to generate only one side, both if empty.
 }
 
 // parseCommandLineParams breaks the comma-separated list of key=value pairs
@@ -76,6 +79,37 @@ func parseCommandLineParams(parameter string) (*commandLineParams, error) {
 		case k == "import_prefix":
 			clp.importPrefix = v
 
+		// If mocks=true, a <Service>Mock type is generated for each service in a separate _mock.twirp.go file
+		case k == "mocks":
+			switch v {
+			case "true":
+				clp.mocks = true
+			case "false":
+			default:
+				return nil, fmt.Errorf("invalid command line flag %s=%s", k, v)
+			}
+
+		// If unimplemented=true, an Unimplemented<Service>Server struct is generated for each service,
+		// to be embedded in implementations that don't implement all the methods
+		case k == "unimplemented":
+			switch v {
+			case "true":
+				clp.unimplemented = true
+			case "false":
+			default:
+				return nil, fmt.Errorf("invalid command line flag %s=%s", k, v)
+			}
+
+		// If generate=client or generate=server, only clients or servers are generated (default is both)
+		case k == "generate":
+			switch v {
+			case "both":
+			case "client", "server":
+				clp.generate = v
+			default:
+				return nil, fmt.Errorf("invalid command line flag %s=%s", k, v)
+			}
+
 		default:
 			return nil, fmt.Errorf("invalid command line flag %s=%s", k, v)
 		}
@@ -135,6 +135,76 @@ func TestParseCommandLineParams(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"mocks true",
+			"mocks=true",
+			&commandLineParams{
+				importMap: map[string]string{},
+				mocks:     true,
+			},
+			nil,
+		},
+		{
+			"mocks false",
+			"mocks=false",
+			&commandLineParams{
+				importMap: map[string]string{},
+			},
+			nil,
+		},
+		{
+			"mocks invalidstuff",
+			"mocks=invalidstuff",
+			nil,
+			errors.New(`invalid command line flag mocks=invalidstuff`),
+		},
+		{
+			"unimplemented true",
+			"unimplemented=true",
+			&commandLineParams{
+				importMap:     map[string]string{},
+				unimplemented: true,
+			},
+			nil,
+		},
+		{
+			"unimplemented invalidstuff",
+			"unimplemented=invalidstuff",
+			nil,
+			errors.New(`invalid command line flag unimplemented=invalidstuff`),
+		},
+		{
+			"generate both",
+			"generate=both",
+			&commandLineParams{
+				importMap: map[string]string{},
+			},
+			nil,
+		},
+		{
+			"generate client",
+			"generate=client",
+			&commandLineParams{
+				importMap: map[string]string{},
+				generate:  "client",
+			},
+			nil,
+		},
+		{
+			"generate server",
+			"generate=server",
+			&commandLineParams{
+				importMap: map[string]string{},
+				generate:  "server",
+			},
+			nil,
+		},
+		{
+			"generate invalidstuff",
+			"generate=invalidstuff",
+			nil,
+			errors.New(`invalid command line flag generate=invalidstuff`),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 	descriptor "google.golang.org/protobuf/types/descriptorpb"
@@ -34,6 +35,7 @@ import (
 	"github.com/twitchtv/twirp/internal/gen"
 	"github.com/twitchtv/twirp/internal/gen/stringutils"
 	"github.com/twitchtv/twirp/internal/gen/typemap"
+	"github.com/twitchtv/twirp/options"
 )
 
 type twirp struct {
@@ -60,6 +62,17 @@ type twirp struct {
 	// the struct so we can write a header for the file that lists its inputs.
 	genFiles []*descriptor.FileDescriptorProto
 
+	// Whether to generate mock implementations of the service interfaces.
+	mocks bool
+
+	// Whether to generate Unimplemented<Service>Server structs.
+	unimplemented bool
+
+	// Whether to generate clients and servers, with the utils that each of them needs.
+	// Both are generated by default, the generate=client|server parameter disables the other one.
+	genClient bool
+	genServer bool
+
 	// Output buffer that holds the bytes we want to write out for a single file.
 	// Gets reset after working on a file.
 	output *bytes.Buffer
@@ -86,28 +99,40 @@ func (t *twirp) Generate(in *plugin.CodeGeneratorRequest) *plugin.CodeGeneratorR
 	t.importMap = params.importMap
 	t.sourceRelativePaths = params.paths == "source_relative"
 	t.modulePrefix = params.module
+	t.mocks = params.mocks
+	t.unimplemented = params.unimplemented
+	t.genClient = params.generate != "server"
+	t.genServer = params.generate != "client"
 
 	t.genFiles = gen.FilesToGenerate(in)
+	for _, f := range t.genFiles {
+		for _, service := range f.Service {
+			for _, method := range service.Method {
+				if method.GetClientStreaming() {
+					gen.Fail(fmt.Sprintf("method %s.%s: client and bidirectional streaming are not supported, only server streaming", service.GetName(), method.GetName()))
+				}
+				if _, err := methodTimeout(method); err != nil {
+					gen.Fail(fmt.Sprintf("method %s.%s: %s", service.GetName(), method.GetName(), err.Error()))
+				}
+			}
+		}
+	}
 
 	// Collect information on types.
 	t.reg = typemap.New(in.ProtoFile)
 
 	// Register names of packages that we import.
-	t.registerPackageName("bytes")
-	t.registerPackageName("strings")
-	t.registerPackageName("path")
 	t.registerPackageName("ctxsetters")
+	t.registerPackageName("twirpruntime")
 	t.registerPackageName("context")
 	t.registerPackageName("http")
 	t.registerPackageName("io")
-	t.registerPackageName("json")
-	t.registerPackageName("protojson")
 	t.registerPackageName("proto")
 	t.registerPackageName("strconv")
 	t.registerPackageName("twirp")
-	t.registerPackageName("url")
 	t.registerPackageName("fmt")
-	t.registerPackageName("errors")
+	t.registerPackageName("time")
+	t.registerPackageName("sync")
 
 	// Time to figure out package names of objects defined in protobuf. First,
 	// we'll figure out the name for the package we're generating.
@@ -149,7 +174,7 @@ func (t *twirp) Generate(in *plugin.CodeGeneratorRequest) *plugin.CodeGeneratorR
 			name = stringutils.BaseName(f.GetName())
 		}
 		name = stringutils.CleanIdentifier(name)
-		alias := t.registerPackageName(name)
+		alias := t.reservePackageName(name)
 		t.fileToGoPackageName[f] = alias
 	}
 
@@ -160,12 +185,27 @@ func (t *twirp) Generate(in *plugin.CodeGeneratorRequest) *plugin.CodeGeneratorR
 		respFile := t.generate(f)
 		if respFile != nil {
 			resp.File = append(resp.File, respFile)
+			if t.mocks {
+				resp.File = append(resp.File, t.generateMocks(f))
+			}
 		}
 	}
 	return resp
 }
 
+// registerPackageName reserves an alias for a package imported by generated code,
+// which is then referenced as t.pkgs[name].
 func (t *twirp) registerPackageName(name string) (alias string) {
+	alias = t.reservePackageName(name)
+	t.pkgs[name] = alias
+	return alias
+}
+
+// reservePackageName returns a package alias that is not in use yet, name or name
+// followed by a number, and marks it as in use. Unlike registerPackageName, it
+// doesn't change t.pkgs, so the packages of .proto dependencies (like twirp from
+// twirp/options.proto) never take the names of packages used by generated code.
+func (t *twirp) reservePackageName(name string) (alias string) {
 	alias = name
 	i := 1
 	for t.pkgNamesInUse[alias] {
@@ -173,7 +213,6 @@ func (t *twirp) registerPackageName(name string) (alias string) {
 		i++
 	}
 	t.pkgNamesInUse[alias] = true
-	t.pkgs[name] = alias
 	return alias
 }
 
@@ -223,9 +262,6 @@ func (t *twirp) generate(file *descriptor.FileDescriptorProto) *plugin.CodeGener
 	t.generateFileHeader(file)
 
 	t.generateImports(file)
-	if t.filesHandled == 0 {
-		t.generateUtilImports()
-	}
 
 	t.generateVersionCheck(file)
 
@@ -254,7 +290,7 @@ func (t *twirp) generateVersionCheck(file *descriptor.FileDescriptorProto) {
 	t.P(`// If the constant is not defined in the package, that likely means`)
 	t.P(`// the package needs to be updated to work with this generated code.`)
 	t.P(`// See https://twitchtv.github.io/twirp/docs/version_matrix.html`)
-	t.P(`const _ = `, t.pkgs["twirp"], `.TwirpPackageMinVersion_8_1_0`)
+	t.P(`const _ = `, t.pkgs["twirp"], `.TwirpPackageMinVersion_8_3_0`)
 }
 
 func (t *twirp) generateFileHeader(file *descriptor.FileDescriptorProto) {
@@ -281,23 +317,54 @@ func (t *twirp) generateImports(file *descriptor.FileDescriptorProto) {
 		return
 	}
 
+	// Most imports are only used by servers, and some of them only by method handlers.
+	hasMethods, hasUnaryMethods, hasTimeouts := false, false, false
+	for _, service := range file.Service {
+		for _, method := range service.Method {
+			hasMethods = true
+			if !method.GetServerStreaming() {
+				hasUnaryMethods = true
+			}
+			if timeout, _ := methodTimeout(method); timeout > 0 {
+				hasTimeouts = true
+			}
+		}
+	}
+	serverMethods := t.genServer && hasMethods
+	usesContext := t.genServer || hasMethods // clients use contexts in methods
+
 	// stdlib imports
-	t.P(`import `, t.pkgs["context"], ` "context"`)
-	t.P(`import `, t.pkgs["fmt"], ` "fmt"`)
+	if usesContext {
+		t.P(`import `, t.pkgs["context"], ` "context"`)
+	}
+	if t.genServer {
+		t.P(`import `, t.pkgs["fmt"], ` "fmt"`)
+	}
 	t.P(`import `, t.pkgs["http"], ` "net/http"`)
-	t.P(`import `, t.pkgs["io"], ` "io"`)
-	t.P(`import `, t.pkgs["json"], ` "encoding/json"`)
-	t.P(`import `, t.pkgs["strconv"], ` "strconv"`)
-	t.P(`import `, t.pkgs["strings"], ` "strings"`)
+	if t.genServer {
+		t.P(`import `, t.pkgs["io"], ` "io"`)
+	}
+	if serverMethods && hasUnaryMethods {
+		t.P(`import `, t.pkgs["strconv"], ` "strconv"`)
+	}
+	if t.genServer || hasTimeouts {
+		t.P(`import `, t.pkgs["time"], ` "time"`)
+	}
 	t.P()
 
 	// dependency imports
-	t.P(`import `, t.pkgs["protojson"], ` "google.golang.org/protobuf/encoding/protojson"`)
-	t.P(`import `, t.pkgs["proto"], ` "google.golang.org/protobuf/proto"`)
 	t.P(`import `, t.pkgs["twirp"], ` "github.com/twitchtv/twirp"`)
-	t.P(`import `, t.pkgs["ctxsetters"], ` "github.com/twitchtv/twirp/ctxsetters"`)
+	if usesContext {
+		t.P(`import `, t.pkgs["ctxsetters"], ` "github.com/twitchtv/twirp/ctxsetters"`)
+	}
+	t.P(`import `, t.pkgs["twirpruntime"], ` "github.com/twitchtv/twirp/twirpruntime"`)
 	t.P()
 
+	t.generateMessageImports(file)
+}
+
+// generateMessageImports imports the packages of the messages used by methods of the services.
+func (t *twirp) generateMessageImports(file *descriptor.FileDescriptorProto) {
 	// It's legal to import a message and use it as an input or output for a
 	// method. Make sure to import the package of any such message. First, dedupe
 	// them.
@@ -343,579 +410,66 @@ func (t *twirp) generateImports(file *descriptor.FileDescriptorProto) {
 	}
 }
 
-// generateUtilImports are imports that are only used on utility functions.
-// If there are multiple proto files being generated, they are only included in the first one of them.
-func (t *twirp) generateUtilImports() {
-	t.P(`import `, t.pkgs["bytes"], ` "bytes"`)
-	t.P(`import `, t.pkgs["errors"], ` "errors"`)
-	t.P(`import `, t.pkgs["path"], ` "path"`)
-	t.P(`import `, t.pkgs["url"], ` "net/url"`)
-}
-
-// Generate utility functions used in Twirp code.
-// These functions are generated only once per package; when generating for
-// multiple services they are declared only once.
+// Generate utility types and functions used in Twirp code.
+// These are generated only once per package; when generating for
+// multiple services they are declared only once. Everything else is
+// in the twirpruntime package.
 func (t *twirp) generateUtils() {
 	t.sectionComment(`Utils`)
 
-	t.P(`// HTTPClient is the interface used by generated clients to send HTTP requests.`)
-	t.P(`// It is fulfilled by *(net/http).Client, which is sufficient for most users.`)
-	t.P(`// Users can provide their own implementation for special retry policies.`)
-	t.P(`// `)
-	t.P(`// HTTPClient implementations should not follow redirects. Redirects are`)
-	t.P(`// automatically disabled if *(net/http).Client is passed to client`)
-	t.P(`// constructors. See the withoutRedirects function in this file for more`)
-	t.P(`// details.`)
-	t.P(`type HTTPClient interface {`)
-	t.P(`	Do(req *`, t.pkgs["http"], `.Request) (*`, t.pkgs["http"], `.Response, error)`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// TwirpServer is the interface generated server structs will support: they're`)
-	t.P(`// HTTP handlers with additional methods for accessing metadata about the`)
-	t.P(`// service. Those accessors are a low-level API for building reflection tools.`)
-	t.P(`// Most people can think of TwirpServers as just http.Handlers.`)
-	t.P(`type TwirpServer interface {`)
-	t.P(`  `, t.pkgs["http"], `.Handler`)
-	t.P()
-
-	t.P(`  // ServiceDescriptor returns gzipped bytes describing the .proto file that`)
-	t.P(`  // this service was generated from. Once unzipped, the bytes can be`)
-	t.P(`  // unmarshalled as a`)
-	t.P(`  // google.golang.org/protobuf/types/descriptorpb.FileDescriptorProto.`)
-	t.P(`  //`)
-	t.P(`  // The returned integer is the index of this particular service within that`)
-	t.P(`  // FileDescriptorProto's 'Service' slice of ServiceDescriptorProtos. This is a`)
-	t.P(`  // low-level field, expected to be used for reflection.`)
-	t.P(`  ServiceDescriptor() ([]byte, int)`)
-	t.P()
-	t.P(`  // ProtocGenTwirpVersion is the semantic version string of the version of`)
-	t.P(`  // twirp used to generate this file.`)
-	t.P(`  ProtocGenTwirpVersion() string`)
-	t.P()
-	t.P(`  // PathPrefix returns the HTTP URL path prefix for all methods handled by this`)
-	t.P(`  // service. This can be used with an HTTP mux to route Twirp requests.`)
-	t.P(`  // The path prefix is in the form: "/<prefix>/<package>.<Service>/"`)
-	t.P(`  // that is, everything in a Twirp route except for the <Method> at the end.`)
-	t.P(`  PathPrefix() string`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`func newServerOpts(opts []interface{}) *`, t.pkgs["twirp"], `.ServerOptions {`)
-	t.P(`  serverOpts := &`, t.pkgs["twirp"], `.ServerOptions{}`)
-	t.P(`  for _, opt := range opts {`)
-	t.P(`    switch o := opt.(type) {`)
-	t.P(`    case `, t.pkgs["twirp"], `.ServerOption:`)
-	t.P(`      o(serverOpts)`)
-	t.P(`    case *`, t.pkgs["twirp"], `.ServerHooks: // backwards compatibility, allow to specify hooks as an argument`)
-	t.P(`      twirp.WithServerHooks(o)(serverOpts)`)
-	t.P(`    case nil: // backwards compatibility, allow nil value for the argument`)
-	t.P(`      continue`)
-	t.P(`    default:`)
-	t.P(`      panic(`, t.pkgs["fmt"], `.Sprintf("Invalid option type %T, please use a twirp.ServerOption", o))`)
-	t.P(`    }`)
-	t.P(`  }`)
-	t.P(`  return serverOpts`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).`)
-	t.P(`// Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.`)
-	t.P(`// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)`)
-	t.P(`func WriteError(resp `, t.pkgs["http"], `.ResponseWriter, err error) {`)
-	t.P(`  writeError(`, t.pkgs["context"], `.Background(), resp, err, nil)`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// writeError writes Twirp errors in the response and triggers hooks.`)
-	t.P(`func writeError(ctx `, t.pkgs["context"], `.Context, resp `, t.pkgs["http"], `.ResponseWriter, err error, hooks *`, t.pkgs["twirp"], `.ServerHooks) {`)
-	t.P(`  // Convert to a twirp.Error. Non-twirp errors are converted to internal errors.`)
-	t.P(`  var twerr `, t.pkgs["twirp"], `.Error`)
-	t.P(`  if !`, t.pkgs["errors"], `.As(err, &twerr) {`)
-	t.P(`    twerr = `, t.pkgs["twirp"], `.InternalErrorWith(err)`)
-	t.P(`  }`)
-	t.P(`  `)
-	t.P(`  statusCode := `, t.pkgs["twirp"], `.ServerHTTPStatusFromErrorCode(twerr.Code())`)
-	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithStatusCode(ctx, statusCode)`)
-	t.P(`  ctx = callError(ctx, hooks, twerr)`)
-	t.P(`  `)
-	t.P(`  respBody := marshalErrorToJSON(twerr)`)
-	t.P(`  `)
-	t.P(`  resp.Header().Set("Content-Type", "application/json") // Error responses are always JSON`)
-	t.P(`  resp.Header().Set("Content-Length", strconv.Itoa(len(respBody)))`)
-	t.P(`  resp.WriteHeader(statusCode) // set HTTP status code and send response`)
-	t.P(`  `)
-	t.P(`  _, writeErr := resp.Write(respBody)`)
-	t.P(`  if writeErr != nil {`)
-	t.P(`    // We have three options here. We could log the error, call the Error`)
-	t.P(`    // hook, or just silently ignore the error.`)
-	t.P(`    //`)
-	t.P(`    // Logging is unacceptable because we don't have a user-controlled `)
-	t.P(`    // logger; writing out to stderr without permission is too rude.`)
-	t.P(`    //`)
-	t.P(`    // Calling the Error hook would confuse users: it would mean the Error`)
-	t.P(`    // hook got called twice for one request, which is likely to lead to`)
-	t.P(`    // duplicated log messages and metrics, no matter how well we document`)
-	t.P(`    // the behavior.`)
-	t.P(`    //`)
-	t.P(`    // Silently ignoring the error is our least-bad option. It's highly`)
-	t.P(`    // likely that the connection is broken and the original 'err' says`)
-	t.P(`    // so anyway.`)
-	t.P(`    _ = writeErr`)
-	t.P(`  }`)
-	t.P(`  `)
-	t.P(`  callResponseSent(ctx, hooks)`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// sanitizeBaseURL parses the baseURL, and adds the "http" scheme if needed.`)
-	t.P(`// If the URL is unparsable, the baseURL is returned unchanged.`)
-	t.P(`func sanitizeBaseURL(baseURL string) string {`)
-	t.P(`  u, err := `, t.pkgs["url"], `.Parse(baseURL)`)
-	t.P(`  if err != nil {`)
-	t.P(`    return baseURL // invalid URL will fail later when making requests`)
-	t.P(`  }`)
-	t.P(`  if u.Scheme == "" {`)
-	t.P(`    u.Scheme = "http"`)
-	t.P(`  }`)
-	t.P(`  return u.String()`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// baseServicePath composes the path prefix for the service (without <Method>).`)
-	t.P(`// e.g.: baseServicePath("/twirp", "my.pkg", "MyService")`)
-	t.P(`//       returns => "/twirp/my.pkg.MyService/"`)
-	t.P(`// e.g.: baseServicePath("", "", "MyService")`)
-	t.P(`//       returns => "/MyService/"`)
-	t.P(`func baseServicePath(prefix, pkg, service string) string {`)
-	t.P(`  fullServiceName := service`)
-	t.P(`  if pkg != "" {`)
-	t.P(`    fullServiceName = pkg + "." + service`)
-	t.P(`  }`)
-	t.P(`  return path.Join("/", prefix, fullServiceName) + "/"`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// parseTwirpPath extracts path components form a valid Twirp route.`)
-	t.P(`// Expected format: "[<prefix>]/<package>.<Service>/<Method>"`)
-	t.P(`// e.g.: prefix, pkgService, method := parseTwirpPath("/twirp/pkg.Svc/MakeHat")`)
-	t.P(`func parseTwirpPath(path string) (string, string, string) {`)
-	t.P(`  parts := `, t.pkgs["strings"], `.Split(path, "/")`)
-	t.P(`  if len(parts) < 2 {`)
-	t.P(`    return "", "", ""`)
-	t.P(`  }`)
-	t.P(`  method := parts[len(parts)-1]`)
-	t.P(`  pkgService := parts[len(parts)-2]`)
-	t.P(`  prefix := `, t.pkgs["strings"], `.Join(parts[0:len(parts)-2], "/")`)
-	t.P(`  return prefix, pkgService, method`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// getCustomHTTPReqHeaders retrieves a copy of any headers that are set in`)
-	t.P(`// a context through the twirp.WithHTTPRequestHeaders function.`)
-	t.P(`// If there are no headers set, or if they have the wrong type, nil is returned.`)
-	t.P(`func getCustomHTTPReqHeaders(ctx `, t.pkgs["context"], `.Context) `, t.pkgs["http"], `.Header {`)
-	t.P(`  header, ok := `, t.pkgs["twirp"], `.HTTPRequestHeaders(ctx)`)
-	t.P(`  if !ok || header == nil {`)
-	t.P(`    return nil`)
-	t.P(`  }`)
-	t.P(`  copied := make(`, t.pkgs["http"], `.Header)`)
-	t.P(`  for k, vv := range header {`)
-	t.P(`    if vv == nil {`)
-	t.P(`      copied[k] = nil`)
-	t.P(`      continue`)
-	t.P(`    }`)
-	t.P(`    copied[k] = make([]string, len(vv))`)
-	t.P(`    copy(copied[k], vv)`)
-	t.P(`  }`)
-	t.P(`  return copied`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// newRequest makes an http.Request from a client, adding common headers.`)
-	t.P(`func newRequest(ctx `, t.pkgs["context"], `.Context, url string, reqBody io.Reader, contentType string) (*`, t.pkgs["http"], `.Request, error) {`)
-	t.P(`  req, err := `, t.pkgs["http"], `.NewRequest("POST", url, reqBody)`)
-	t.P(`  if err != nil {`)
-	t.P(`    return nil, err`)
-	t.P(`  }`)
-	t.P(`  req = req.WithContext(ctx)`)
-	t.P(`  if customHeader := getCustomHTTPReqHeaders(ctx); customHeader != nil {`)
-	t.P(`    req.Header = customHeader`)
-	t.P(`  }`)
-	t.P(`  req.Header.Set("Accept", contentType)`)
-	t.P(`  req.Header.Set("Content-Type", contentType)`)
-	t.P(`  req.Header.Set("Twirp-Version", "`, gen.Version, `")`)
-	t.P(`  return req, nil`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// JSON serialization for errors`)
-	t.P(`type twerrJSON struct {`)
-	t.P("  Code string            `json:\"code\"`")
-	t.P("  Msg  string            `json:\"msg\"`")
-	t.P("  Meta map[string]string `json:\"meta,omitempty\"`")
-	t.P(`}`)
-	t.P()
-	t.P(`// marshalErrorToJSON returns JSON from a twirp.Error, that can be used as HTTP error response body.`)
-	t.P(`// If serialization fails, it will use a descriptive Internal error instead.`)
-	t.P(`func marshalErrorToJSON(twerr `, t.pkgs["twirp"], `.Error) []byte {`)
-	t.P(`  // make sure that msg is not too large`)
-	t.P(`  msg := twerr.Msg()`)
-	t.P(`  if len(msg) > 1e6 {`)
-	t.P(`    msg = msg[:1e6]`)
-	t.P(`  }`)
-	t.P(``)
-	t.P(`  tj := twerrJSON{`)
-	t.P(`    Code: string(twerr.Code()),`)
-	t.P(`    Msg:  msg,`)
-	t.P(`    Meta: twerr.MetaMap(),`)
-	t.P(`  }`)
-	t.P(``)
-	t.P(`  buf, err := `, t.pkgs["json"], `.Marshal(&tj)`)
-	t.P(`  if err != nil {`)
-	t.P(`    buf = []byte("{\"type\": \"" + `, t.pkgs["twirp"], `.Internal +"\", \"msg\": \"There was an error but it could not be serialized into JSON\"}") // fallback`)
-	t.P(`  }`)
-	t.P(``)
-	t.P(`  return buf`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// errorFromResponse builds a twirp.Error from a non-200 HTTP response.`)
-	t.P(`// If the response has a valid serialized Twirp error, then it's returned.`)
-	t.P(`// If not, the response status code is used to generate a similar twirp`)
-	t.P(`// error. See twirpErrorFromIntermediary for more info on intermediary errors.`)
-	t.P(`func errorFromResponse(resp *`, t.pkgs["http"], `.Response) `, t.pkgs["twirp"], `.Error {`)
-	t.P(`  statusCode := resp.StatusCode`)
-	t.P(`  statusText := `, t.pkgs["http"], `.StatusText(statusCode)`)
-	t.P(``)
-	t.P(`  if isHTTPRedirect(statusCode) {`)
-	t.P(`    // Unexpected redirect: it must be an error from an intermediary.`)
-	t.P(`    // Twirp clients don't follow redirects automatically, Twirp only handles`)
-	t.P(`    // POST requests, redirects should only happen on GET and HEAD requests.`)
-	t.P(`    location := resp.Header.Get("Location")`)
-	t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("unexpected HTTP status code %d %q received, Location=%q", statusCode, statusText, location)`)
-	t.P(`    return twirpErrorFromIntermediary(statusCode, msg, location)`)
-	t.P(`  }`)
-	t.P(``)
-	t.P(`  respBodyBytes, err := `, t.pkgs["io"], `.ReadAll(resp.Body)`)
-	t.P(`  if err != nil {`)
-	t.P(`    return wrapInternal(err, "failed to read server error response body")`)
-	t.P(`  }`)
-	t.P(``)
-	t.P(`  var tj twerrJSON`)
-	t.P(`  dec := `, t.pkgs["json"], `.NewDecoder(`, t.pkgs["bytes"], `.NewReader(respBodyBytes))`)
-	t.P(`  dec.DisallowUnknownFields()`)
-	t.P(`  if err := dec.Decode(&tj); err != nil || tj.Code == "" {`)
-	t.P(`    // Invalid JSON response; it must be an error from an intermediary.`)
-	t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("Error from intermediary with HTTP status code %d %q", statusCode, statusText)`)
-	t.P(`    return twirpErrorFromIntermediary(statusCode, msg, string(respBodyBytes))`)
-	t.P(`  }`)
-	t.P(``)
-	t.P(`  errorCode := `, t.pkgs["twirp"], `.ErrorCode(tj.Code)`)
-	t.P(`  if !`, t.pkgs["twirp"], `.IsValidErrorCode(errorCode) {`)
-	t.P(`    msg := "invalid type returned from server error response: "+tj.Code`)
-	t.P(`    return `, t.pkgs["twirp"], `.InternalError(msg).WithMeta("body", string(respBodyBytes))`)
-	t.P(`  }`)
-	t.P(``)
-	t.P(`  twerr := `, t.pkgs["twirp"], `.NewError(errorCode, tj.Msg)`)
-	t.P(`  for k, v := range(tj.Meta) {`)
-	t.P(`    twerr = twerr.WithMeta(k, v)`)
-	t.P(`  }`)
-	t.P(`  return twerr`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// twirpErrorFromIntermediary maps HTTP errors from non-twirp sources to twirp errors.`)
-	t.P(`// The mapping is similar to gRPC: https://github.com/grpc/grpc/blob/master/doc/http-grpc-status-mapping.md.`)
-	t.P(`// Returned twirp Errors have some additional metadata for inspection.`)
-	t.P(`func twirpErrorFromIntermediary(status int, msg string, bodyOrLocation string) `, t.pkgs["twirp"], `.Error {`)
-	t.P(`  var code `, t.pkgs["twirp"], `.ErrorCode`)
-	t.P(`  if isHTTPRedirect(status) { // 3xx`)
-	t.P(`    code = `, t.pkgs["twirp"], `.Internal`)
-	t.P(`  } else {`)
-	t.P(`    switch status {`)
-	t.P(`    case 400: // Bad Request`)
-	t.P(`      code = `, t.pkgs["twirp"], `.Internal`)
-	t.P(`    case 401: // Unauthorized`)
-	t.P(`      code = `, t.pkgs["twirp"], `.Unauthenticated`)
-	t.P(`    case 403: // Forbidden`)
-	t.P(`      code = `, t.pkgs["twirp"], `.PermissionDenied`)
-	t.P(`    case 404: // Not Found`)
-	t.P(`      code = `, t.pkgs["twirp"], `.BadRoute`)
-	t.P(`    case 429: // Too Many Requests`)
-	t.P(`      code = `, t.pkgs["twirp"], `.ResourceExhausted`)
-	t.P(`    case 502, 503, 504: // Bad Gateway, Service Unavailable, Gateway Timeout`)
-	t.P(`      code = `, t.pkgs["twirp"], `.Unavailable`)
-	t.P(`    default: // All other codes`)
-	t.P(`      code = `, t.pkgs["twirp"], `.Unknown`)
-	t.P(`    }`)
-	t.P(`  }`)
-	t.P(``)
-	t.P(`  twerr := `, t.pkgs["twirp"], `.NewError(code, msg)`)
-	t.P(`  twerr = twerr.WithMeta("http_error_from_intermediary", "true") // to easily know if this error was from intermediary`)
-	t.P(`  twerr = twerr.WithMeta("status_code", `, t.pkgs["strconv"], `.Itoa(status))`)
-	t.P(`  if isHTTPRedirect(status) {`)
-	t.P(`    twerr = twerr.WithMeta("location", bodyOrLocation)`)
-	t.P(`  } else {`)
-	t.P(`    twerr = twerr.WithMeta("body", bodyOrLocation)`)
-	t.P(`  }`)
-	t.P(`  return twerr`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`func isHTTPRedirect(status int) bool {`)
-	t.P(`	return status >= 300 && status <= 399`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// wrapInternal wraps an error with a prefix as an Internal error.`)
-	t.P(`// The original error cause is accessible by github.com/pkg/errors.Cause.`)
-	t.P(`func wrapInternal(err error, prefix string) `, t.pkgs["twirp"], `.Error {`)
-	t.P(`	return `, t.pkgs["twirp"], `.InternalErrorWith(&wrappedError{prefix: prefix, cause: err})`)
-	t.P(`}`)
-	t.P(`type wrappedError struct {`)
-	t.P(`	prefix string`)
-	t.P(`	cause  error`)
-	t.P(`}`)
-	t.P(`func (e *wrappedError) Error() string { return e.prefix + ": " + e.cause.Error() }`)
-	t.P(`func (e *wrappedError) Unwrap() error  { return e.cause } // for go1.13 + errors.Is/As `)
-	t.P(`func (e *wrappedError) Cause() error  { return e.cause } // for github.com/pkg/errors`)
-	t.P()
-
-	t.P(`// ensurePanicResponses makes sure that rpc methods causing a panic still result in a Twirp Internal`)
-	t.P(`// error response (status 500), and error hooks are properly called with the panic wrapped as an error.`)
-	t.P(`// The panic is re-raised so it can be handled normally with middleware.`)
-	t.P(`func ensurePanicResponses(ctx `, t.pkgs["context"], `.Context, resp `, t.pkgs["http"], `.ResponseWriter, hooks *`, t.pkgs["twirp"], `.ServerHooks) {`)
-	t.P(`	if r := recover(); r != nil {`)
-	t.P(`		// Wrap the panic as an error so it can be passed to error hooks.`)
-	t.P(`		// The original error is accessible from error hooks, but not visible in the response.`)
-	t.P(`		err := errFromPanic(r)`)
-	t.P(`		twerr := &internalWithCause{msg: "Internal service panic", cause: err}`)
-	t.P(`		// Actually write the error`)
-	t.P(`		writeError(ctx, resp, twerr, hooks)`)
-	t.P(`		// If possible, flush the error to the wire.`)
-	t.P(`		f, ok := resp.(`, t.pkgs["http"], `.Flusher)`)
-	t.P(`		if ok {`)
-	t.P(`			f.Flush()`)
-	t.P(`		}`)
-	t.P(``)
-	t.P(`		panic(r)`)
-	t.P(`	}`)
-	t.P(`}`)
-	t.P(``)
-	t.P(`// errFromPanic returns the typed error if the recovered panic is an error, otherwise formats as error.`)
-	t.P(`func errFromPanic(p interface{}) error {`)
-	t.P(`	if err, ok := p.(error); ok {`)
-	t.P(`	  return err`)
-	t.P(`	}`)
-	t.P(`	return fmt.Errorf("panic: %v", p)`)
-	t.P(`}`)
-	t.P(``)
-	t.P(`// internalWithCause is a Twirp Internal error wrapping an original error cause,`)
-	t.P(`// but the original error message is not exposed on Msg(). The original error`)
-	t.P(`// can be checked with go1.13+ errors.Is/As, and also by (github.com/pkg/errors).Unwrap`)
-	t.P(`type internalWithCause struct {`)
-	t.P(`	msg    string`)
-	t.P(`	cause  error`)
-	t.P(`}`)
-	t.P(`func (e *internalWithCause) Unwrap() error  { return e.cause } // for go1.13 + errors.Is/As`)
-	t.P(`func (e *internalWithCause) Cause() error  { return e.cause } // for github.com/pkg/errors`)
-	t.P(`func (e *internalWithCause) Error() string { return e.msg + ": " + e.cause.Error()}`)
-	t.P(`func (e *internalWithCause) Code() `, t.pkgs["twirp"], `.ErrorCode { return `, t.pkgs["twirp"], `.Internal }`)
-	t.P(`func (e *internalWithCause) Msg() string                { return e.msg }`)
-	t.P(`func (e *internalWithCause) Meta(key string) string     { return "" }`)
-	t.P(`func (e *internalWithCause) MetaMap() map[string]string { return nil }`)
-	t.P(`func (e *internalWithCause) WithMeta(key string, val string) `, t.pkgs["twirp"], `.Error { return e }`)
-	t.P()
-
-	t.P(`// malformedRequestError is used when the twirp server cannot unmarshal a request`)
-	t.P(`func malformedRequestError(msg string) `, t.pkgs["twirp"], `.Error {`)
-	t.P(`	return `, t.pkgs["twirp"], `.NewError(`, t.pkgs["twirp"], `.Malformed, msg)`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// badRouteError is used when the twirp server cannot route a request`)
-	t.P(`func badRouteError(msg string, method, url string) `, t.pkgs["twirp"], `.Error {`)
-	t.P(`	err := `, t.pkgs["twirp"], `.NewError(`, t.pkgs["twirp"], `.BadRoute, msg)`)
-	t.P(`	err = err.WithMeta("twirp_invalid_route", method+" "+url)`)
-	t.P(`	return err`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// withoutRedirects makes sure that the POST request can not be redirected.`)
-	t.P(`// The standard library will, by default, redirect requests (including POSTs) if it gets a 302 or`)
-	t.P(`// 303 response, and also 301s in go1.8. It redirects by making a second request, changing the`)
-	t.P(`// method to GET and removing the body. This produces very confusing error messages, so instead we`)
-	t.P(`// set a redirect policy that always errors. This stops Go from executing the redirect.`)
-	t.P(`//`)
-	t.P(`// We have to be a little careful in case the user-provided http.Client has its own CheckRedirect`)
-	t.P(`// policy - if so, we'll run through that policy first.`)
-	t.P(`//`)
-	t.P(`// Because this requires modifying the http.Client, we make a new copy of the client and return it.`)
-	t.P(`func withoutRedirects(in *`, t.pkgs["http"], `.Client) *`, t.pkgs["http"], `.Client {`)
-	t.P(`	copy := *in`)
-	t.P(`	copy.CheckRedirect = func(req *`, t.pkgs["http"], `.Request, via []*`, t.pkgs["http"], `.Request) error {`)
-	t.P(`		if in.CheckRedirect != nil {`)
-	t.P(`			// Run the input's redirect if it exists, in case it has side effects, but ignore any error it`)
-	t.P(`			// returns, since we want to use ErrUseLastResponse.`)
-	t.P(`			err := in.CheckRedirect(req, via)`)
-	t.P(`			_ = err // Silly, but this makes sure generated code passes errcheck -blank, which some people use.`)
-	t.P(`		}`)
-	t.P(`		return `, t.pkgs["http"], `.ErrUseLastResponse`)
-	t.P(`	}`)
-	t.P(`	return &copy`)
-	t.P(`}`)
-	t.P()
-
-	t.P(`// doProtobufRequest makes a Protobuf request to the remote Twirp service.`)
-	t.P(`func doProtobufRequest(ctx `, t.pkgs["context"], `.Context, client HTTPClient, hooks *`, t.pkgs["twirp"], `.ClientHooks, url string, in, out `, t.pkgs["proto"], `.Message) (_ `, t.pkgs["context"], `.Context, err error) {`)
-	t.P(`  reqBodyBytes, err := `, t.pkgs["proto"], `.Marshal(in)`)
-	t.P(`  if err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "failed to marshal proto request")`)
-	t.P(`  }`)
-	t.P(`  reqBody := `, t.pkgs["bytes"], `.NewBuffer(reqBodyBytes)`)
-	t.P(`  if err = ctx.Err(); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "aborted because context was done")`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  req, err := newRequest(ctx, url, reqBody, "application/protobuf")`)
-	t.P(`  if err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "could not build request")`)
-	t.P(`  }`)
-	t.P(`  ctx, err = callClientRequestPrepared(ctx, hooks, req)`)
-	t.P(`	 if err != nil {`)
-	t.P(`    return ctx, err`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  req = req.WithContext(ctx)`)
-	t.P(`  resp, err := client.Do(req)`)
-	t.P(`  if err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "failed to do request")`)
-	t.P(`  }`)
-	t.P(`  defer func() { _ = resp.Body.Close() }()`)
-	t.P()
-	t.P(`  if err = ctx.Err(); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "aborted because context was done")`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  if resp.StatusCode != 200 {`)
-	t.P(`    return ctx, errorFromResponse(resp)`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  respBodyBytes, err := `, t.pkgs["io"], `.ReadAll(resp.Body)`)
-	t.P(`  if err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "failed to read response body")`)
-	t.P(`  }`)
-	t.P(`  if err = ctx.Err(); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "aborted because context was done")`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  if err = `, t.pkgs["proto"], `.Unmarshal(respBodyBytes, out); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "failed to unmarshal proto response")`)
-	t.P(`  }`)
-	t.P(`  return ctx, nil`)
-	t.P(`}`)
-	t.P()
+	if t.genClient {
+		t.P(`// HTTPClient is the interface used by generated clients to send HTTP requests.`)
+		t.P(`// It is fulfilled by *(net/http).Client, which is sufficient for most users.`)
+		t.P(`// Users can provide their own implementation for special retry policies.`)
+		t.P(`// `)
+		t.P(`// HTTPClient implementations should not follow redirects. Redirects are`)
+		t.P(`// automatically disabled if *(net/http).Client is passed to client`)
+		t.P(`// constructors. See twirpruntime.WithoutRedirects for more details.`)
+		t.P(`type HTTPClient interface {`)
+		t.P(`	Do(req *`, t.pkgs["http"], `.Request) (*`, t.pkgs["http"], `.Response, error)`)
+		t.P(`}`)
+		t.P()
+	}
 
-	t.P(`// doJSONRequest makes a JSON request to the remote Twirp service.`)
-	t.P(`func doJSONRequest(ctx `, t.pkgs["context"], `.Context, client HTTPClient, hooks *`, t.pkgs["twirp"], `.ClientHooks, url string, in, out `, t.pkgs["proto"], `.Message) (_ `, t.pkgs["context"], `.Context, err error) {`)
-	t.P(`  marshaler := &`, t.pkgs["protojson"], `.MarshalOptions{UseProtoNames: true}`)
-	t.P(`  reqBytes, err := marshaler.Marshal(in)`)
-	t.P(`  if err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "failed to marshal json request")`)
-	t.P(`  }`)
-	t.P(`  if err = ctx.Err(); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "aborted because context was done")`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  req, err := newRequest(ctx, url, `, t.pkgs["bytes"], `.NewReader(reqBytes), "application/json")`)
-	t.P(`  if err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "could not build request")`)
-	t.P(`  }`)
-	t.P(`  ctx, err = callClientRequestPrepared(ctx, hooks, req)`)
-	t.P(`	 if err != nil {`)
-	t.P(`    return ctx, err`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  req = req.WithContext(ctx)`)
-	t.P(`  resp, err := client.Do(req)`)
-	t.P(`  if err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "failed to do request")`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  defer func() {`)
-	t.P(`    cerr := resp.Body.Close()`)
-	t.P(`    if err == nil && cerr != nil {`)
-	t.P(`      err = wrapInternal(cerr, "failed to close response body")`)
-	t.P(`    }`)
-	t.P(`  }()`)
-	t.P()
-	t.P(`  if err = ctx.Err(); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "aborted because context was done")`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  if resp.StatusCode != 200 {`)
-	t.P(`    return ctx, errorFromResponse(resp)`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  d := `, t.pkgs["json"], `.NewDecoder(resp.Body)`)
-	t.P(`  rawRespBody := `, t.pkgs["json"], `.RawMessage{}`)
-	t.P(`  if err := d.Decode(&rawRespBody); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "failed to unmarshal json response")`)
-	t.P(`  }`)
-	t.P(`  unmarshaler := `, t.pkgs["protojson"], `.UnmarshalOptions{DiscardUnknown: true}`)
-	t.P(`  if err = unmarshaler.Unmarshal(rawRespBody, out); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "failed to unmarshal json response")`)
-	t.P(`  }`)
-	t.P(`  if err = ctx.Err(); err != nil {`)
-	t.P(`    return ctx, wrapInternal(err, "aborted because context was done")`)
-	t.P(`  }`)
-	t.P(`  return ctx, nil`)
-	t.P(`}`)
-	t.P()
+	if t.genServer {
+		t.P(`// TwirpServer is the interface generated server structs will support: they're`)
+		t.P(`// HTTP handlers with additional methods for accessing metadata about the`)
+		t.P(`// service. Those accessors are a low-level API for building reflection tools.`)
+		t.P(`// Most people can think of TwirpServers as just http.Handlers.`)
+		t.P(`type TwirpServer interface {`)
+		t.P(`  `, t.pkgs["http"], `.Handler`)
+		t.P()
 
-	t.P(`// Call twirp.ServerHooks.RequestReceived if the hook is available`)
-	t.P(`func callRequestReceived(ctx `, t.pkgs["context"], `.Context, h *`, t.pkgs["twirp"], `.ServerHooks) (`, t.pkgs["context"], `.Context, error) {`)
-	t.P(`  if h == nil || h.RequestReceived == nil {`)
-	t.P(`    return ctx, nil`)
-	t.P(`  }`)
-	t.P(`  return h.RequestReceived(ctx)`)
-	t.P(`}`)
-	t.P()
-	t.P(`// Call twirp.ServerHooks.RequestRouted if the hook is available`)
-	t.P(`func callRequestRouted(ctx `, t.pkgs["context"], `.Context, h *`, t.pkgs["twirp"], `.ServerHooks) (`, t.pkgs["context"], `.Context, error) {`)
-	t.P(`  if h == nil || h.RequestRouted == nil {`)
-	t.P(`    return ctx, nil`)
-	t.P(`  }`)
-	t.P(`  return h.RequestRouted(ctx)`)
-	t.P(`}`)
-	t.P()
-	t.P(`// Call twirp.ServerHooks.ResponsePrepared if the hook is available`)
-	t.P(`func callResponsePrepared(ctx `, t.pkgs["context"], `.Context, h *`, t.pkgs["twirp"], `.ServerHooks) `, t.pkgs["context"], `.Context {`)
-	t.P(`  if h == nil || h.ResponsePrepared == nil {`)
-	t.P(`    return ctx`)
-	t.P(`  }`)
-	t.P(`  return h.ResponsePrepared(ctx)`)
-	t.P(`}`)
-	t.P()
-	t.P(`// Call twirp.ServerHooks.ResponseSent if the hook is available`)
-	t.P(`func callResponseSent(ctx `, t.pkgs["context"], `.Context, h *`, t.pkgs["twirp"], `.ServerHooks) {`)
-	t.P(`  if h == nil || h.ResponseSent == nil {`)
-	t.P(`    return`)
-	t.P(`  }`)
-	t.P(`  h.ResponseSent(ctx)`)
-	t.P(`}`)
-	t.P()
-	t.P(`// Call twirp.ServerHooks.Error if the hook is available`)
-	t.P(`func callError(ctx `, t.pkgs["context"], `.Context, h *`, t.pkgs["twirp"], `.ServerHooks, err `, t.pkgs["twirp"], `.Error) `, t.pkgs["context"], `.Context {`)
-	t.P(`  if h == nil || h.Error == nil {`)
-	t.P(`    return ctx`)
-	t.P(`  }`)
-	t.P(`  return h.Error(ctx, err)`)
-	t.P(`}`)
-	t.P()
+		t.P(`  // ServiceDescriptor returns gzipped bytes describing the .proto file that`)
+		t.P(`  // this service was generated from. Once unzipped, the bytes can be`)
+		t.P(`  // unmarshalled as a`)
+		t.P(`  // google.golang.org/protobuf/types/descriptorpb.FileDescriptorProto.`)
+		t.P(`  //`)
+		t.P(`  // The returned integer is the index of this particular service within that`)
+		t.P(`  // FileDescriptorProto's 'Service' slice of ServiceDescriptorProtos. This is a`)
+		t.P(`  // low-level field, expected to be used for reflection.`)
+		t.P(`  ServiceDescriptor() ([]byte, int)`)
+		t.P()
+		t.P(`  // ProtocGenTwirpVersion is the semantic version string of the version of`)
+		t.P(`  // twirp used to generate this file.`)
+		t.P(`  ProtocGenTwirpVersion() string`)
+		t.P()
+		t.P(`  // PathPrefix returns the HTTP URL path prefix for all methods handled by this`)
+		t.P(`  // service. This can be used with an HTTP mux to route Twirp requests.`)
+		t.P(`  // The path prefix is in the form: "/<prefix>/<package>.<Service>/"`)
+		t.P(`  // that is, everything in a Twirp route except for the <Method> at the end.`)
+		t.P(`  PathPrefix() string`)
+		t.P(`}`)
+		t.P()
 
-	t.generateClientHooks()
+		t.P(`// WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).`)
+		t.P(`// Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.`)
+		t.P(`// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)`)
+		t.P(`func WriteError(resp `, t.pkgs["http"], `.ResponseWriter, err error) {`)
+		t.P(`  `, t.pkgs["twirpruntime"], `.WriteError(`, t.pkgs["context"], `.Background(), resp, err, nil)`)
+		t.P(`}`)
+		t.P()
+	}
 }
 
 // P forwards to g.gen.P, which prints output.
@@ -941,15 +495,24 @@ func (t *twirp) generateService(file *descriptor.FileDescriptorProto, service *d
 	t.sectionComment(servName + ` Interface`)
 	t.generateTwirpInterface(file, service)
 
-	t.sectionComment(servName + ` Protobuf Client`)
-	t.generateClient("Protobuf", file, service)
+	if t.unimplemented && t.genServer {
+		t.sectionComment(servName + ` Unimplemented Server`)
+		t.generateUnimplementedServer(service)
+	}
+
+	if t.genClient {
+		t.sectionComment(servName + ` Protobuf Client`)
+		t.generateClient("Protobuf", file, service)
 
-	t.sectionComment(servName + ` JSON Client`)
-	t.generateClient("JSON", file, service)
+		t.sectionComment(servName + ` JSON Client`)
+		t.generateClient("JSON", file, service)
+	}
 
 	// Server
-	t.sectionComment(servName + ` Server Handler`)
-	t.generateServer(file, service)
+	if t.genServer {
+		t.sectionComment(servName + ` Server Handler`)
+		t.generateServer(file, service)
+	}
 }
 
 func (t *twirp) generateTwirpInterface(file *descriptor.FileDescriptorProto, service *descriptor.ServiceDescriptorProto) {
@@ -965,19 +528,183 @@ func (t *twirp) generateTwirpInterface(file *descriptor.FileDescriptorProto, ser
 		if err == nil {
 			t.printComments(comments)
 		}
-		t.P(t.generateSignature(method))
+		t.P(t.generateSignature(service, method))
 		t.P()
 	}
 	t.P(`}`)
+
+	if !hasStreamingMethods(service) {
+		return
+	}
+
+	if t.genClient {
+		// Clients of services with streaming methods implement a different interface,
+		// where streaming methods return a stream to read the response messages.
+		t.P()
+		t.P(`// `, servName, `Client is the interface implemented by `, servName, ` clients. Unary methods`)
+		t.P(`// are the same as in the `, servName, ` interface, server-streaming methods return a stream`)
+		t.P(`// to read the response messages.`)
+		t.P(`type `, servName, `Client interface {`)
+		for _, method := range service.Method {
+			comments, err = t.reg.MethodComments(file, service, method)
+			if err == nil {
+				t.printComments(comments)
+			}
+			t.P(t.generateClientSignature(service, method))
+			t.P()
+		}
+		t.P(`}`)
+	}
+
+	for _, method := range service.Method {
+		if !method.GetServerStreaming() {
+			continue
+		}
+		outputType := t.goTypeName(method.GetOutputType())
+		serverStream := serverStreamName(service, method)
+		t.P()
+		t.P(`// `, serverStream, ` is used by implementations of `, servName, `.`, methodNameCamelCased(method), ` to send response messages.`)
+		t.P(`type `, serverStream, ` interface {`)
+		t.P(`  // Send sends a message to the client. If it returns an error (e.g. the client is gone),`)
+		t.P(`  // the method should stop and return.`)
+		t.P(`  Send(*`, outputType, `) error`)
+		t.P(`}`)
+		if t.genServer {
+			t.P()
+			t.P(`type `, unexported(serverStream), ` struct {`)
+			t.P(`  *`, t.pkgs["twirpruntime"], `.ServerStream`)
+			t.P(`}`)
+			t.P()
+			t.P(`func (s *`, unexported(serverStream), `) Send(msg *`, outputType, `) error {`)
+			t.P(`  return s.ServerStream.Send(msg)`)
+			t.P(`}`)
+		}
+
+		if !t.genClient {
+			continue
+		}
+		clientStream := clientStreamName(service, method)
+		t.P()
+		t.P(`// `, clientStream, ` is returned by `, servName, `Client.`, methodNameCamelCased(method), ` to read response messages:`)
+		t.P(`//`)
+		t.P(`//   for stream.Next() {`)
+		t.P(`//     msg := stream.Msg()`)
+		t.P(`//   }`)
+		t.P(`//   if err := stream.Err(); err != nil {`)
+		t.P(`//     // handle error`)
+		t.P(`//   }`)
+		t.P(`type `, clientStream, ` interface {`)
+		t.P(`  // Next reads the next message, available with Msg. It returns false when the`)
+		t.P(`  // stream is over, either successfully or with an error (see Err).`)
+		t.P(`  Next() bool`)
+		t.P()
+		t.P(`  // Msg returns the message read by the last call to Next.`)
+		t.P(`  Msg() *`, outputType)
+		t.P()
+		t.P(`  // Err returns the error that ended the stream, or nil if it ended successfully.`)
+		t.P(`  Err() error`)
+		t.P()
+		t.P(`  // Close stops reading the stream. It must be called if the stream is not read`)
+		t.P(`  // until Next returns false, to release the connection.`)
+		t.P(`  Close() error`)
+		t.P(`}`)
+		t.P()
+		t.P(`type `, unexported(clientStream), ` struct {`)
+		t.P(`  *`, t.pkgs["twirpruntime"], `.ClientStream // provides Err and Close`)
+		t.P(`  msg *`, outputType)
+		t.P(`}`)
+		t.P()
+		t.P(`func (s *`, unexported(clientStream), `) Next() bool {`)
+		t.P(`  msg := new(`, outputType, `)`)
+		t.P(`  if !s.Recv(msg) {`)
+		t.P(`    return false`)
+		t.P(`  }`)
+		t.P(`  s.msg = msg`)
+		t.P(`  return true`)
+		t.P(`}`)
+		t.P()
+		t.P(`func (s *`, unexported(clientStream), `) Msg() *`, outputType, ` { return s.msg }`)
+	}
 }
 
-func (t *twirp) generateSignature(method *descriptor.MethodDescriptorProto) string {
+// generateUnimplementedServer generates a struct implementing the service interface, where
+// every method returns an Unimplemented error. Implementations can embed it to keep compiling
+// when methods are added to the service.
+func (t *twirp) generateUnimplementedServer(service *descriptor.ServiceDescriptorProto) {
+	servName := serviceNameCamelCased(service)
+	structName := `Unimplemented` + servName + `Server`
+
+	t.P(`// `, structName, ` can be embedded in implementations of the `, servName, ` interface`)
+	t.P(`// for forward compatibility: methods that are not implemented return a twirp.Unimplemented`)
+	t.P(`// error, and new methods added to the service don't break the build.`)
+	t.P(`type `, structName, ` struct{}`)
+	t.P()
+	t.P(`var _ `, servName, ` = `, structName, `{}`)
+	for _, method := range service.Method {
+		methName := methodNameCamelCased(method)
+		inputType := t.goTypeName(method.GetInputType())
+		outputType := t.goTypeName(method.GetOutputType())
+		unimplementedErr := t.pkgs["twirp"] + `.NewError(` + t.pkgs["twirp"] + `.Unimplemented, "method ` + methName + ` is not implemented")`
+		t.P()
+		if method.GetServerStreaming() {
+			t.P(`func (`, structName, `) `, methName, `(`, t.pkgs["context"], `.Context, *`, inputType, `, `, serverStreamName(service, method), `) error {`)
+			t.P(`  return `, unimplementedErr)
+		} else {
+			t.P(`func (`, structName, `) `, methName, `(`, t.pkgs["context"], `.Context, *`, inputType, `) (*`, outputType, `, error) {`)
+			t.P(`  return nil, `, unimplementedErr)
+		}
+		t.P(`}`)
+	}
+}
+
+func (t *twirp) generateSignature(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) string {
 	methName := methodNameCamelCased(method)
 	inputType := t.goTypeName(method.GetInputType())
 	outputType := t.goTypeName(method.GetOutputType())
+	if method.GetServerStreaming() {
+		return fmt.Sprintf(`	%s(%s.Context, *%s, %s) error`, methName, t.pkgs["context"], inputType, serverStreamName(service, method))
+	}
 	return fmt.Sprintf(`	%s(%s.Context, *%s) (*%s, error)`, methName, t.pkgs["context"], inputType, outputType)
 }
 
+// generateClientSignature is the signature of methods in the client interface of services
+// with streaming methods, where server-streaming methods return a stream.
+func (t *twirp) generateClientSignature(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) string {
+	if !method.GetServerStreaming() {
+		return t.generateSignature(service, method)
+	}
+	methName := methodNameCamelCased(method)
+	inputType := t.goTypeName(method.GetInputType())
+	return fmt.Sprintf(`	%s(%s.Context, *%s) (%s, error)`, methName, t.pkgs["context"], inputType, clientStreamName(service, method))
+}
+
+// clientInterfaceName is the interface implemented by clients of the service, which is
+// the service interface unless there are streaming methods.
+func clientInterfaceName(service *descriptor.ServiceDescriptorProto) string {
+	if hasStreamingMethods(service) {
+		return serviceNameCamelCased(service) + "Client"
+	}
+	return serviceNameCamelCased(service)
+}
+
+func serverStreamName(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) string {
+	return serviceNameCamelCased(service) + methodNameCamelCased(method) + "ServerStream"
+}
+
+func clientStreamName(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) string {
+	return serviceNameCamelCased(service) + methodNameCamelCased(method) + "ClientStream"
+}
+
+// hasStreamingMethods returns true if any method of the service is server-streaming.
+func hasStreamingMethods(service *descriptor.ServiceDescriptorProto) bool {
+	for _, method := range service.Method {
+		if method.GetServerStreaming() {
+			return true
+		}
+	}
+	return false
+}
+
 // valid names: 'JSON', 'Protobuf'
 func (t *twirp) generateClient(name string, file *descriptor.FileDescriptorProto, service *descriptor.ServiceDescriptorProto) {
 	servPkg := pkgName(file)
@@ -993,14 +720,22 @@ func (t *twirp) generateClient(name string, file *descriptor.FileDescriptorProto
 	t.P(`  urls  [`, methCnt, `]string`)
 	t.P(`  interceptor `, t.pkgs["twirp"], `.Interceptor`)
 	t.P(`  opts `, t.pkgs["twirp"], `.ClientOptions`)
+	t.P(`  maxResponseBytes int64`)
+	t.P(`  compression `, t.pkgs["twirpruntime"], `.Compression`)
+	t.P(`  codec `, t.pkgs["twirp"], `.Codec // `, name, ` unless replaced with twirp.WithClientCodec`)
+	t.P(`  methodDescriptors `, t.pkgs["twirpruntime"], `.MethodDescriptors // added to request contexts`)
+	if hasNoSideEffectsMethods(service) {
+		t.P(`  httpGet bool // use GET requests for methods without side effects`)
+	}
 	t.P(`}`)
 	t.P()
 
-	t.P(`// `, newClientFunc, ` creates a `, name, ` client that implements the `, servName, ` interface.`)
+	clientInterface := clientInterfaceName(service)
+	t.P(`// `, newClientFunc, ` creates a `, name, ` client that implements the `, clientInterface, ` interface.`)
 	t.P(`// It communicates using `, name, ` and can be configured with a custom HTTPClient.`)
-	t.P(`func `, newClientFunc, `(baseURL string, client HTTPClient, opts ...`, t.pkgs["twirp"], `.ClientOption) `, servName, ` {`)
+	t.P(`func `, newClientFunc, `(baseURL string, client HTTPClient, opts ...`, t.pkgs["twirp"], `.ClientOption) `, clientInterface, ` {`)
 	t.P(`  if c, ok := client.(*`, t.pkgs["http"], `.Client); ok {`)
-	t.P(`    client = withoutRedirects(c)`)
+	t.P(`    client = `, t.pkgs["twirpruntime"], `.WithoutRedirects(c)`)
 	t.P(`  }`)
 	t.P()
 	t.P(`  clientOpts := `, t.pkgs["twirp"], `.ClientOptions{}`)
@@ -1016,18 +751,30 @@ func (t *twirp) generateClient(name string, file *descriptor.FileDescriptorProto
 	t.P(`  if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {`)
 	t.P(`    pathPrefix = "/twirp" // default prefix`)
 	t.P(`  }`)
+	t.P(`  var maxResponseBytes int64`)
+	t.P(`  _ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)`)
+	if name == "JSON" {
+		t.P(`  codec := `, t.pkgs["twirpruntime"], `.ReadJSONCodec(&clientOpts)`)
+	} else {
+		t.P(`  codec := `, t.pkgs["twirpruntime"], `.ProtobufCodec`)
+	}
+	t.P(`  _ = clientOpts.ReadOpt("codec", &codec)`)
+	if hasNoSideEffectsMethods(service) {
+		t.P(`  httpGet := false`)
+		t.P(`  _ = clientOpts.ReadOpt("httpGet", &httpGet)`)
+	}
 
 	t.P()
 	if len(service.Method) > 0 {
 		t.P(`  // Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>`)
-		t.P(`  serviceURL := sanitizeBaseURL(baseURL)`)
+		t.P(`  serviceURL := `, t.pkgs["twirpruntime"], `.SanitizeBaseURL(baseURL)`)
 		if servNameLit == servNameCc {
-			t.P(`  serviceURL += baseServicePath(pathPrefix, "`, servPkg, `", "`, servNameCc, `")`)
+			t.P(`  serviceURL += `, t.pkgs["twirpruntime"], `.BaseServicePath(pathPrefix, "`, servPkg, `", "`, servNameCc, `")`)
 		} else { // proto service name is not CamelCased, then it needs to check client option to decide if needs to change case
 			t.P(`  if literalURLs {`)
-			t.P(`    serviceURL += baseServicePath(pathPrefix, "`, servPkg, `", "`, servNameLit, `")`)
+			t.P(`    serviceURL += `, t.pkgs["twirpruntime"], `.BaseServicePath(pathPrefix, "`, servPkg, `", "`, servNameLit, `")`)
 			t.P(`  } else {`)
-			t.P(`    serviceURL += baseServicePath(pathPrefix, "`, servPkg, `", "`, servNameCc, `")`)
+			t.P(`    serviceURL += `, t.pkgs["twirpruntime"], `.BaseServicePath(pathPrefix, "`, servPkg, `", "`, servNameCc, `")`)
 			t.P(`  }`)
 		}
 	}
@@ -1062,11 +809,22 @@ func (t *twirp) generateClient(name string, file *descriptor.FileDescriptorProto
 	t.P(`    urls:   urls,`)
 	t.P(`    interceptor: `, t.pkgs["twirp"], `.ChainInterceptors(clientOpts.Interceptors...),`)
 	t.P(`    opts: clientOpts,`)
+	t.P(`    maxResponseBytes: maxResponseBytes,`)
+	t.P(`    compression: `, t.pkgs["twirpruntime"], `.ReadCompression(&clientOpts),`)
+	t.P(`    codec: codec,`)
+	t.P(`    methodDescriptors: `, t.pkgs["twirpruntime"], `.NewMethodDescriptors(`, t.serviceMetadataVarName(), `, `, strconv.Itoa(serviceIndex(file, service)), `),`)
+	if hasNoSideEffectsMethods(service) {
+		t.P(`    httpGet: httpGet,`)
+	}
 	t.P(`  }`)
 	t.P(`}`)
 	t.P()
 
 	for i, method := range service.Method {
+		if method.GetServerStreaming() {
+			t.generateClientStreamingMethod(name, structName, file, service, method, i)
+			continue
+		}
 		methName := methodNameCamelCased(method)
 		pkgName := pkgName(file)
 		inputType := t.goTypeName(method.GetInputType())
@@ -1075,6 +833,7 @@ func (t *twirp) generateClient(name string, file *descriptor.FileDescriptorProto
 		t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithPackageName(ctx, "`, pkgName, `")`)
 		t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithServiceName(ctx, "`, servName, `")`)
 		t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithMethodName(ctx, "`, methName, `")`)
+		t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithMethodDescriptor(ctx, c.methodDescriptors["`, method.GetName(), `"])`)
 		t.P(`  caller := c.call`, methName)
 		t.P(`  if c.interceptor != nil {`)
 		t.generateClientInterceptorCaller(method)
@@ -1083,46 +842,105 @@ func (t *twirp) generateClient(name string, file *descriptor.FileDescriptorProto
 		t.P(`}`)
 		t.P()
 		t.P(`func (c *`, structName, `) call`, methName, `(ctx `, t.pkgs["context"], `.Context, in *`, inputType, `) (*`, outputType, `, error) {`)
+		if timeout, _ := methodTimeout(method); timeout > 0 {
+			t.P(`  ctx, cancel := `, t.pkgs["context"], `.WithTimeout(ctx, `, t.durationLiteral(timeout), `) // (twirp.method).timeout option, unless ctx has an earlier deadline`)
+			t.P(`  defer cancel()`)
+		}
 		t.P(`  out := new(`, outputType, `)`)
-		t.P(`  ctx, err := do`, name, `Request(ctx, c.client, c.opts.Hooks, c.urls[`, strconv.Itoa(i), `], in, out)`)
+		httpGet := "false"
+		if hasNoSideEffects(method) {
+			httpGet = "c.httpGet"
+		}
+		t.P(`  ctx, err := `, t.pkgs["twirpruntime"], `.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[`, strconv.Itoa(i), `], in, out, c.codec, c.maxResponseBytes, c.compression, `, httpGet, `)`)
 		t.P(`  if err != nil {`)
 		t.P(`    twerr, ok := err.(`, t.pkgs["twirp"], `.Error)`)
 		t.P(`    if !ok {`)
 		t.P(`      twerr = `, t.pkgs["twirp"], `.InternalErrorWith(err)`)
 		t.P(`    }`)
-		t.P(`    callClientError(ctx, c.opts.Hooks, twerr)`)
+		t.P(`    `, t.pkgs["twirpruntime"], `.CallClientError(ctx, c.opts.Hooks, twerr)`)
 		t.P(`    return nil, err`)
 		t.P(`  }`)
 		t.P()
-		t.P(`  callClientResponseReceived(ctx, c.opts.Hooks)`)
+		t.P(`  `, t.pkgs["twirpruntime"], `.CallClientResponseReceived(ctx, c.opts.Hooks)`)
 		t.P()
 		t.P(`  return out, nil`)
 		t.P(`}`)
 		t.P()
 	}
-}
 
-func (t *twirp) generateClientHooks() {
-	t.P(`func callClientResponseReceived(ctx `, t.pkgs["context"], `.Context, h *`, t.pkgs["twirp"], `.ClientHooks) {`)
-	t.P(`  if h == nil || h.ResponseReceived == nil {`)
-	t.P(`    return`)
-	t.P(`  }`)
-	t.P(`  h.ResponseReceived(ctx)`)
+	t.P(`// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was`)
+	t.P(`// generated from, and the index of the service in it. Used by the descriptors package.`)
+	t.P(`func (c *`, structName, `) ServiceDescriptor() ([]byte, int) {`)
+	t.P(`  return `, t.serviceMetadataVarName(), `, `, strconv.Itoa(serviceIndex(file, service)))
 	t.P(`}`)
 	t.P()
-	t.P(`func callClientRequestPrepared(ctx `, t.pkgs["context"], `.Context, h *`, t.pkgs["twirp"], `.ClientHooks, req *`, t.pkgs["http"], `.Request) (`, t.pkgs["context"], `.Context, error) {`)
-	t.P(`  if h == nil || h.RequestPrepared == nil {`)
-	t.P(`    return ctx, nil`)
+}
+
+// generateClientStreamingMethod generates a client method for a server-streaming method,
+// which returns a stream to read the response messages.
+func (t *twirp) generateClientStreamingMethod(name, structName string, file *descriptor.FileDescriptorProto, service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto, index int) {
+	methName := methodNameCamelCased(method)
+	servName := serviceNameCamelCased(service)
+	inputType := t.goTypeName(method.GetInputType())
+	clientStream := clientStreamName(service, method)
+	httpGet := "false"
+	if hasNoSideEffects(method) {
+		httpGet = "c.httpGet"
+	}
+
+	t.P(`func (c *`, structName, `) `, methName, `(ctx `, t.pkgs["context"], `.Context, in *`, inputType, `) (`, clientStream, `, error) {`)
+	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithPackageName(ctx, "`, pkgName(file), `")`)
+	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithServiceName(ctx, "`, servName, `")`)
+	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithMethodName(ctx, "`, methName, `")`)
+	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithMethodDescriptor(ctx, c.methodDescriptors["`, method.GetName(), `"])`)
+	t.P(`  caller := c.call`, methName)
+	t.P(`  if c.interceptor != nil {`)
+	t.P(`    caller = func(ctx `, t.pkgs["context"], `.Context, req *`, inputType, `) (`, clientStream, `, error) {`)
+	t.P(`      resp, err := c.interceptor(`)
+	t.P(`        func(ctx `, t.pkgs["context"], `.Context, req interface{}) (interface{}, error) {`)
+	t.P(`          typedReq, ok := req.(*`, inputType, `)`)
+	t.P(`          if !ok {`)
+	t.P(`            return nil, `, t.pkgs["twirp"], `.InternalError("failed type assertion req.(*`, inputType, `) when calling interceptor")`)
+	t.P(`          }`)
+	t.P(`          return c.call`, methName, `(ctx, typedReq)`)
+	t.P(`        },`)
+	t.P(`      )(ctx, req)`)
+	t.P(`      if resp != nil {`)
+	t.P(`        typedResp, ok := resp.(`, clientStream, `)`)
+	t.P(`        if !ok {`)
+	t.P(`          return nil, `, t.pkgs["twirp"], `.InternalError("failed type assertion resp.(`, clientStream, `) when calling interceptor")`)
+	t.P(`        }`)
+	t.P(`        return typedResp, err`)
+	t.P(`      }`)
+	t.P(`      return nil, err`)
+	t.P(`    }`)
 	t.P(`  }`)
-	t.P(`  return h.RequestPrepared(ctx, req)`)
+	t.P(`  return caller(ctx, in)`)
 	t.P(`}`)
 	t.P()
-	t.P(`func callClientError(ctx `, t.pkgs["context"], `.Context, h *`, t.pkgs["twirp"], `.ClientHooks, err `, t.pkgs["twirp"], `.Error) {`)
-	t.P(`  if h == nil || h.Error == nil {`)
-	t.P(`    return`)
+	t.P(`func (c *`, structName, `) call`, methName, `(ctx `, t.pkgs["context"], `.Context, in *`, inputType, `) (`, clientStream, `, error) {`)
+	timeout, _ := methodTimeout(method)
+	if timeout > 0 {
+		t.P(`  ctx, cancel := `, t.pkgs["context"], `.WithTimeout(ctx, `, t.durationLiteral(timeout), `) // (twirp.method).timeout option, unless ctx has an earlier deadline`)
+	}
+	t.P(`  ctx, stream, err := `, t.pkgs["twirpruntime"], `.DoStreamRequest(ctx, c.client, c.opts.Hooks, c.urls[`, strconv.Itoa(index), `], in, c.codec, c.maxResponseBytes, c.compression, `, httpGet, `)`)
+	t.P(`  if err != nil {`)
+	if timeout > 0 {
+		t.P(`    cancel()`)
+	}
+	t.P(`    twerr, ok := err.(`, t.pkgs["twirp"], `.Error)`)
+	t.P(`    if !ok {`)
+	t.P(`      twerr = `, t.pkgs["twirp"], `.InternalErrorWith(err)`)
+	t.P(`    }`)
+	t.P(`    `, t.pkgs["twirpruntime"], `.CallClientError(ctx, c.opts.Hooks, twerr)`)
+	t.P(`    return nil, err`)
 	t.P(`  }`)
-	t.P(`  h.Error(ctx, err)`)
+	if timeout > 0 {
+		t.P(`  stream.SetCancel(cancel) // the timeout applies to the whole stream`)
+	}
+	t.P(`  return &`, unexported(clientStream), `{ClientStream: stream}, nil`)
 	t.P(`}`)
+	t.P()
 }
 
 func (t *twirp) generateServer(file *descriptor.FileDescriptorProto, service *descriptor.ServiceDescriptorProto) {
@@ -1135,8 +953,15 @@ func (t *twirp) generateServer(file *descriptor.FileDescriptorProto, service *de
 	t.P(`  interceptor `, t.pkgs["twirp"], `.Interceptor`)
 	t.P(`  hooks     *`, t.pkgs["twirp"], `.ServerHooks`)
 	t.P(`  pathPrefix string // prefix for routing`)
-	t.P(`  jsonSkipDefaults bool // do not include unpopulated fields (default values) in the response`)
-	t.P(`  jsonCamelCase bool // JSON fields are serialized as lowerCamelCase rather than keeping the original proto names`)
+	t.P(`  maxRequestBytes int64 // limit for request bodies, no limit if 0 or less`)
+	t.P(`  methodMaxRequestBytes map[string]int64 // per-method overrides of maxRequestBytes`)
+	t.P(`  compression `, t.pkgs["twirpruntime"], `.Compression // request decompression and response compression`)
+	t.P(`  requestTimeouts bool // apply timeouts from the Twirp-Timeout request header`)
+	t.P(`  maxRequestTimeout `, t.pkgs["time"], `.Duration // limit for timeouts from the Twirp-Timeout request header`)
+	t.P(`  panicHandler func(`, t.pkgs["context"], `.Context, interface{}, []byte) `, t.pkgs["twirp"], `.Error // handles panics in methods, re-panic if nil`)
+	t.P(`  errorTransformer func(`, t.pkgs["context"], `.Context, error) `, t.pkgs["twirp"], `.Error // maps errors before they are sent, optional`)
+	t.P(`  codecs map[string]`, t.pkgs["twirp"], `.Codec // codecs by content type, including the built-in Protobuf and JSON codecs`)
+	t.P(`  methodDescriptors `, t.pkgs["twirpruntime"], `.MethodDescriptors // added to request contexts`)
 	t.P(`}`)
 	t.P()
 
@@ -1145,13 +970,21 @@ func (t *twirp) generateServer(file *descriptor.FileDescriptorProto, service *de
 	t.P(`// HTTP requests that are routed to the right method in the provided svc implementation.`)
 	t.P(`// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).`)
 	t.P(`func New`, servName, `Server(svc `, servName, `, opts ...interface{}) TwirpServer {`)
-	t.P(`  serverOpts := newServerOpts(opts)`)
+	t.P(`  serverOpts := `, t.pkgs["twirpruntime"], `.NewServerOptions(opts)`)
 	t.P()
 	t.P(`  // Using ReadOpt allows backwards and forwards compatibility with new options in the future`)
-	t.P(`  jsonSkipDefaults := false`)
-	t.P(`  _ = serverOpts.ReadOpt("jsonSkipDefaults", &jsonSkipDefaults)`)
-	t.P(`  jsonCamelCase := false`)
-	t.P(`  _ = serverOpts.ReadOpt("jsonCamelCase", &jsonCamelCase)`)
+	t.P(`  var maxRequestBytes int64`)
+	t.P(`  _ = serverOpts.ReadOpt("maxRequestBytes", &maxRequestBytes)`)
+	t.P(`  var methodMaxRequestBytes map[string]int64`)
+	t.P(`  _ = serverOpts.ReadOpt("methodMaxRequestBytes", &methodMaxRequestBytes)`)
+	t.P(`  requestTimeouts := true`)
+	t.P(`  _ = serverOpts.ReadOpt("requestTimeouts", &requestTimeouts)`)
+	t.P(`  var maxRequestTimeout `, t.pkgs["time"], `.Duration`)
+	t.P(`  _ = serverOpts.ReadOpt("maxRequestTimeout", &maxRequestTimeout)`)
+	t.P(`  var panicHandler func(`, t.pkgs["context"], `.Context, interface{}, []byte) `, t.pkgs["twirp"], `.Error`)
+	t.P(`  _ = serverOpts.ReadOpt("panicHandler", &panicHandler)`)
+	t.P(`  var errorTransformer func(`, t.pkgs["context"], `.Context, error) `, t.pkgs["twirp"], `.Error`)
+	t.P(`  _ = serverOpts.ReadOpt("errorTransformer", &errorTransformer)`)
 	t.P(`  var pathPrefix string`)
 	t.P(`  if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {`)
 	t.P(`    pathPrefix = "/twirp" // default prefix`)
@@ -1162,23 +995,39 @@ func (t *twirp) generateServer(file *descriptor.FileDescriptorProto, service *de
 	t.P(`    hooks: serverOpts.Hooks,`)
 	t.P(`    interceptor: `, t.pkgs["twirp"], `.ChainInterceptors(serverOpts.Interceptors...),`)
 	t.P(`    pathPrefix: pathPrefix,`)
-	t.P(`    jsonSkipDefaults: jsonSkipDefaults,`)
-	t.P(`    jsonCamelCase: jsonCamelCase,`)
+	t.P(`    maxRequestBytes: maxRequestBytes,`)
+	t.P(`    methodMaxRequestBytes: methodMaxRequestBytes,`)
+	t.P(`    compression: `, t.pkgs["twirpruntime"], `.ReadCompression(serverOpts),`)
+	t.P(`    requestTimeouts: requestTimeouts,`)
+	t.P(`    maxRequestTimeout: maxRequestTimeout,`)
+	t.P(`    panicHandler: panicHandler,`)
+	t.P(`    errorTransformer: errorTransformer,`)
+	t.P(`    codecs: `, t.pkgs["twirpruntime"], `.ReadServerCodecs(serverOpts),`)
+	t.P(`    methodDescriptors: `, t.pkgs["twirpruntime"], `.NewMethodDescriptors(`, t.serviceMetadataVarName(), `, `, strconv.Itoa(serviceIndex(file, service)), `),`)
 	t.P(`  }`)
 	t.P(`}`)
 	t.P()
 
 	// Write Errors
 	t.P(`// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.`)
+	t.P(`// The error is first mapped by the error transformer, if any (see twirp.WithServerErrorTransformer).`)
 	t.P(`// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)`)
 	t.P(`func (s *`, servStruct, `) writeError(ctx `, t.pkgs["context"], `.Context, resp `, t.pkgs["http"], `.ResponseWriter, err error) {`)
-	t.P(`  writeError(ctx, resp, err, s.hooks)`)
+	t.P(`  `, t.pkgs["twirpruntime"], `.WriteError(ctx, resp, `, t.pkgs["twirpruntime"], `.TransformError(ctx, err, s.errorTransformer), s.hooks)`)
 	t.P(`}`)
 	t.P()
 
 	// Write request body read issue
 	t.P(`// handleRequestBodyError is used to handle error when the twirp server cannot read request`)
 	t.P(`func (s *`, servStruct, `) handleRequestBodyError(ctx `, t.pkgs["context"], `.Context, resp `, t.pkgs["http"], `.ResponseWriter, msg string, err error) {`)
+	t.P(`  if twerr, ok := err.(`, t.pkgs["twirp"], `.Error); ok {`)
+	t.P(`    s.writeError(ctx, resp, twerr)`)
+	t.P(`    return`)
+	t.P(`  }`)
+	t.P(`  if twerr := `, t.pkgs["twirpruntime"], `.BodyTooLargeTwirpError(err, "request "); twerr != nil {`)
+	t.P(`    s.writeError(ctx, resp, twerr)`)
+	t.P(`    return`)
+	t.P(`  }`)
 	t.P(`  if `, t.pkgs["context"], `.Canceled == ctx.Err() {`)
 	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirp"], `.NewError(`, t.pkgs["twirp"], `.Canceled, "failed to read request: context canceled"))`)
 	t.P(`    return`)
@@ -1187,7 +1036,18 @@ func (t *twirp) generateServer(file *descriptor.FileDescriptorProto, service *de
 	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirp"], `.NewError(`, t.pkgs["twirp"], `.DeadlineExceeded, "failed to read request: deadline exceeded"))`)
 	t.P(`    return`)
 	t.P(`  }`)
-	t.P(`  s.writeError(ctx, resp, `, t.pkgs["twirp"], `.WrapError(malformedRequestError(msg), err))`)
+	t.P(`  s.writeError(ctx, resp, `, t.pkgs["twirp"], `.WrapError(`, t.pkgs["twirpruntime"], `.MalformedRequestError(msg), err))`)
+	t.P(`}`)
+	t.P()
+
+	t.P(`// requestBody returns the request body, decompressed if it has a Content-Encoding,`)
+	t.P(`// and limited to the max size configured for the method. It must be closed after reading.`)
+	t.P(`func (s *`, servStruct, `) requestBody(req *`, t.pkgs["http"], `.Request, method string) (`, t.pkgs["io"], `.ReadCloser, error) {`)
+	t.P(`  maxBytes := s.maxRequestBytes`)
+	t.P(`  if n, ok := s.methodMaxRequestBytes[method]; ok {`)
+	t.P(`    maxBytes = n`)
+	t.P(`  }`)
+	t.P(`  return `, t.pkgs["twirpruntime"], `.RequestBody(req, maxBytes, s.compression)`)
 	t.P(`}`)
 	t.P()
 
@@ -1217,37 +1077,53 @@ func (t *twirp) generateServerRouting(servStruct string, file *descriptor.FileDe
 
 	t.P(`func (s *`, servStruct, `) ServeHTTP(resp `, t.pkgs["http"], `.ResponseWriter, req *`, t.pkgs["http"], `.Request) {`)
 	t.P(`  ctx := req.Context()`)
+	t.P(`  if hooks, interceptor, ok := `, t.pkgs["twirpruntime"], `.MuxServerOptions(ctx); ok {`)
+	t.P(`    // Served by a twirp.Mux: apply the shared hooks and interceptors before the ones from this server`)
+	t.P(`    shared := *s`)
+	t.P(`    shared.hooks = `, t.pkgs["twirp"], `.ChainHooks(hooks, s.hooks)`)
+	t.P(`    shared.interceptor = `, t.pkgs["twirp"], `.ChainInterceptors(interceptor, s.interceptor)`)
+	t.P(`    s = &shared`)
+	t.P(`  }`)
 	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithPackageName(ctx, "`, pkgName, `")`)
 	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithServiceName(ctx, "`, servName, `")`)
 	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithResponseWriter(ctx, resp)`)
+	t.P(`  ctx = `, t.pkgs["twirpruntime"], `.WithErrorDetailsAccepted(ctx, req) // only clients that send Twirp-Error-Details get error details`)
 	t.P()
 	t.P(`  var err error`)
-	t.P(`  ctx, err = callRequestReceived(ctx, s.hooks)`)
+	t.P(`  ctx, err = `, t.pkgs["twirpruntime"], `.CallRequestReceived(ctx, s.hooks)`)
 	t.P(`  if err != nil {`)
 	t.P(`    s.writeError(ctx, resp, err)`)
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P()
-	t.P(`  if req.Method != "POST" {`)
-	t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("unsupported method %q (only POST is allowed)", req.Method)`)
-	t.P(`    s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))`)
-	t.P(`    return`)
-	t.P(`  }`)
+	if hasNoSideEffectsMethods(service) {
+		t.P(`  if req.Method != "POST" && req.Method != "GET" {`)
+		t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("unsupported method %q (only POST and GET are allowed)", req.Method)`)
+		t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.BadRouteError(msg, req.Method, req.URL.Path))`)
+		t.P(`    return`)
+		t.P(`  }`)
+	} else {
+		t.P(`  if req.Method != "POST" {`)
+		t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("unsupported method %q (only POST is allowed)", req.Method)`)
+		t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.BadRouteError(msg, req.Method, req.URL.Path))`)
+		t.P(`    return`)
+		t.P(`  }`)
+	}
 	t.P()
 	t.P(`  // Verify path format: [<prefix>]/<package>.<Service>/<Method>`)
-	t.P(`  prefix, pkgService, method := parseTwirpPath(req.URL.Path)`)
+	t.P(`  prefix, pkgService, method := `, t.pkgs["twirpruntime"], `.ParseTwirpPath(req.URL.Path)`)
 	if pkgServNameLit == pkgServNameCc {
 		t.P(`  if pkgService != `, strconv.Quote(pkgServNameLit), ` {`)
 	} else { // proto service name is not CamelCased, but need to support CamelCased routes for Go clients (https://github.com/twitchtv/twirp/pull/257)
 		t.P(`  if pkgService != `, strconv.Quote(pkgServNameLit), ` && pkgService != `, strconv.Quote(pkgServNameCc), ` {`)
 	}
 	t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("no handler for path %q", req.URL.Path)`)
-	t.P(`    s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))`)
+	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.BadRouteError(msg, req.Method, req.URL.Path))`)
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P(`  if prefix != s.pathPrefix {`)
 	t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)`)
-	t.P(`    s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))`)
+	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.BadRouteError(msg, req.Method, req.URL.Path))`)
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P()
@@ -1261,69 +1137,108 @@ func (t *twirp) generateServerRouting(servStruct string, file *descriptor.FileDe
 		} else { // proto method name is not CamelCased, but need to support CamelCased routes for Go clients (https://github.com/twitchtv/twirp/pull/257)
 			t.P(`  case `, strconv.Quote(methNameLit), `, `, strconv.Quote(methNameCc), `:`)
 		}
+		if hasNoSideEffectsMethods(service) && !hasNoSideEffects(method) {
+			t.P(`    if req.Method != "POST" {`)
+			t.P(`      msg := `, t.pkgs["fmt"], `.Sprintf("unsupported method %q (only POST is allowed, the method may have side effects)", req.Method)`)
+			t.P(`      s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.BadRouteError(msg, req.Method, req.URL.Path))`)
+			t.P(`      return`)
+			t.P(`    }`)
+		}
 		t.P(`    s.serve`, methNameCc, `(ctx, resp, req)`)
 		t.P(`    return`)
 	}
 	t.P(`  default:`)
 	t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("no handler for path %q", req.URL.Path)`)
-	t.P(`    s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))`)
+	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.BadRouteError(msg, req.Method, req.URL.Path))`)
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P(`}`)
 	t.P()
 }
 
+// hasNoSideEffects returns true if the method is marked with the option
+// idempotency_level = NO_SIDE_EFFECTS, so it can be called with HTTP GET requests.
+func hasNoSideEffects(method *descriptor.MethodDescriptorProto) bool {
+	return method.GetOptions().GetIdempotencyLevel() == descriptor.MethodOptions_NO_SIDE_EFFECTS
+}
+
+// hasNoSideEffectsMethods returns true if any method of the service has no side effects.
+func hasNoSideEffectsMethods(service *descriptor.ServiceDescriptorProto) bool {
+	for _, method := range service.Method {
+		if hasNoSideEffects(method) {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *twirp) generateServerMethod(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) {
 	methName := methodNameCamelCased(method)
 	servStruct := serviceStruct(service)
 	t.P(`func (s *`, servStruct, `) serve`, methName, `(ctx `, t.pkgs["context"], `.Context, resp `, t.pkgs["http"], `.ResponseWriter, req *`, t.pkgs["http"], `.Request) {`)
-	t.P(`  header := req.Header.Get("Content-Type")`)
-	t.P(`  i := `, t.pkgs["strings"], `.Index(header, ";")`)
-	t.P(`  if i == -1 {`)
-	t.P(`    i = len(header)`)
-	t.P(`  }`)
-	t.P(`  switch `, t.pkgs["strings"], `.TrimSpace(`, t.pkgs["strings"], `.ToLower(header[:i])) {`)
-	t.P(`  case "application/json":`)
-	t.P(`    s.serve`, methName, `JSON(ctx, resp, req)`)
-	t.P(`  case "application/protobuf":`)
-	t.P(`    s.serve`, methName, `Protobuf(ctx, resp, req)`)
-	t.P(`  default:`)
+	if hasNoSideEffects(method) {
+		t.P(`  if req.Method == "GET" { // allowed for methods without side effects`)
+		t.P(`    var err error`)
+		t.P(`    if req, err = `, t.pkgs["twirpruntime"], `.RequestFromQuery(req); err != nil {`)
+		t.P(`      s.writeError(ctx, resp, err)`)
+		t.P(`      return`)
+		t.P(`    }`)
+		t.P(`  }`)
+		t.P()
+	}
+	t.P(`  codec, ok := `, t.pkgs["twirpruntime"], `.RequestCodec(s.codecs, req)`)
+	t.P(`  if !ok {`)
 	t.P(`    msg := `, t.pkgs["fmt"], `.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))`)
-	t.P(`    twerr := badRouteError(msg, req.Method, req.URL.Path)`)
+	t.P(`    twerr := `, t.pkgs["twirpruntime"], `.BadRouteError(msg, req.Method, req.URL.Path)`)
 	t.P(`    s.writeError(ctx, resp, twerr)`)
+	t.P(`    return`)
 	t.P(`  }`)
+	t.P(`  s.serve`, methName, `Codec(ctx, resp, req, codec)`)
 	t.P(`}`)
 	t.P()
-	t.generateServerJSONMethod(service, method)
-	t.generateServerProtobufMethod(service, method)
+	t.generateServerCodecMethod(service, method)
 }
 
-func (t *twirp) generateServerJSONMethod(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) {
+// generateServerCodecMethod generates the serve<Method>Codec function, which reads the
+// request and writes the response with the codec of the request content type: the built-in
+// Protobuf or JSON codec, or a custom codec (see twirp.WithServerCodecs).
+func (t *twirp) generateServerCodecMethod(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) {
 	servStruct := serviceStruct(service)
 	methName := methodNameCamelCased(method)
 	servName := serviceNameCamelCased(service)
-	t.P(`func (s *`, servStruct, `) serve`, methName, `JSON(ctx `, t.pkgs["context"], `.Context, resp `, t.pkgs["http"], `.ResponseWriter, req *`, t.pkgs["http"], `.Request) {`)
+	t.P(`func (s *`, servStruct, `) serve`, methName, `Codec(ctx `, t.pkgs["context"], `.Context, resp `, t.pkgs["http"], `.ResponseWriter, req *`, t.pkgs["http"], `.Request, codec `, t.pkgs["twirp"], `.Codec) {`)
 	t.P(`  var err error`)
+	t.generateServerRequestTimeout(method)
 	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithMethodName(ctx, "`, methName, `")`)
-	t.P(`  ctx, err = callRequestRouted(ctx, s.hooks)`)
+	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithMethodDescriptor(ctx, s.methodDescriptors["`, method.GetName(), `"])`)
+	t.P(`  ctx, err = `, t.pkgs["twirpruntime"], `.CallRequestRouted(ctx, s.hooks)`)
 	t.P(`  if err != nil {`)
 	t.P(`    s.writeError(ctx, resp, err)`)
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P()
-	t.P(`  d := `, t.pkgs["json"], `.NewDecoder(req.Body)`)
-	t.P(`  rawReqBody := `, t.pkgs["json"], `.RawMessage{}`)
-	t.P(`  if err := d.Decode(&rawReqBody); err != nil {`)
-	t.P(`    s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)`)
+	t.P(`  reqBody, err := s.requestBody(req, "`, methName, `")`)
+	t.P(`  if err != nil {`)
+	t.P(`    s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)`)
+	t.P(`    return`)
+	t.P(`  }`)
+	t.P(`  defer func() { _ = reqBody.Close() }()`)
+	t.P()
+	t.P(`  buf, err := `, t.pkgs["io"], `.ReadAll(reqBody)`)
+	t.P(`  if err != nil {`)
+	t.P(`    s.handleRequestBodyError(ctx, resp, `, t.pkgs["twirpruntime"], `.RequestBodyErrorMessage(codec), err)`)
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P(`  reqContent := new(`, t.goTypeName(method.GetInputType()), `)`)
-	t.P(`  unmarshaler := `, t.pkgs["protojson"], `.UnmarshalOptions{DiscardUnknown: true}`)
-	t.P(`  if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {`)
-	t.P(`    s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)`)
+	t.P(`  if err = codec.Unmarshal(buf, reqContent); err != nil {`)
+	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.RequestDecodingError(codec, err))`)
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P()
+	if method.GetServerStreaming() {
+		t.generateServerStreamingCall(service, method)
+		return
+	}
 	t.P(`  handler := s.`, servName, `.`, methName)
 	t.P(`  if s.interceptor != nil {`)
 	t.generateServerInterceptorHandler(service, method)
@@ -1332,8 +1247,10 @@ func (t *twirp) generateServerJSONMethod(service *descriptor.ServiceDescriptorPr
 	t.P(`  // Call service method`)
 	t.P(`  var respContent *`, t.goTypeName(method.GetOutputType()))
 	t.P(`  func() {`)
-	t.P(`    defer ensurePanicResponses(ctx, resp, s.hooks)`)
+	t.generateServerMethodTimeout(method)
+	t.P(`    defer `, t.pkgs["twirpruntime"], `.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)`)
 	t.P(`    respContent, err = handler(ctx, reqContent)`)
+	t.generateServerMethodTimeoutError(method)
 	t.P(`  }()`)
 	t.P()
 	t.P(`  if err != nil {`)
@@ -1345,97 +1262,158 @@ func (t *twirp) generateServerJSONMethod(service *descriptor.ServiceDescriptorPr
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P()
-	t.P(`  ctx = callResponsePrepared(ctx, s.hooks)`)
+	t.P(`  ctx = `, t.pkgs["twirpruntime"], `.CallResponsePrepared(ctx, s.hooks)`)
 	t.P()
-	t.P(`  marshaler := &`, t.pkgs["protojson"], `.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}`)
-	t.P(`  respBytes, err := marshaler.Marshal(respContent)`)
+	t.P(`  respBytes, err := codec.Marshal(respContent)`)
 	t.P(`  if err != nil {`)
-	t.P(`    s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))`)
+	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.WrapInternal(err, "failed to marshal response"))`)
+	t.P(`    return`)
+	t.P(`  }`)
+	t.P()
+	t.P(`  respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)`)
+	t.P(`  if err != nil {`)
+	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirpruntime"], `.WrapInternal(err, "failed to compress response"))`)
 	t.P(`    return`)
 	t.P(`  }`)
 	t.P()
 	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithStatusCode(ctx, `, t.pkgs["http"], `.StatusOK)`)
-	t.P(`  resp.Header().Set("Content-Type", "application/json")`)
+	t.P(`  resp.Header().Set("Content-Type", codec.ContentType())`)
+	t.P(`  s.compression.SetResponseHeaders(resp.Header(), contentEncoding)`)
 	t.P(`  resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))`)
 	t.P(`  resp.WriteHeader(`, t.pkgs["http"], `.StatusOK)`)
-	t.P()
 	t.P(`  if n, err := resp.Write(respBytes); err != nil {`)
 	t.P(`    msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())`)
 	t.P(`    twerr := `, t.pkgs["twirp"], `.NewError(`, t.pkgs["twirp"], `.Unknown, msg)`)
-	t.P(`    ctx = callError(ctx, s.hooks, twerr)`)
+	t.P(`    ctx = `, t.pkgs["twirpruntime"], `.CallError(ctx, s.hooks, twerr)`)
 	t.P(`  }`)
-	t.P(`  callResponseSent(ctx, s.hooks)`)
+	t.P(`  `, t.pkgs["twirpruntime"], `.CallResponseSent(ctx, s.hooks)`)
 	t.P(`}`)
 	t.P()
 }
 
-func (t *twirp) generateServerProtobufMethod(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) {
-	servStruct := serviceStruct(service)
+// generateServerStreamingCall generates the end of the serve<Method>Codec function of
+// server-streaming methods, where the response is written by the stream with messages
+// serialized by the codec.
+func (t *twirp) generateServerStreamingCall(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) {
 	methName := methodNameCamelCased(method)
 	servName := serviceNameCamelCased(service)
-	t.P(`func (s *`, servStruct, `) serve`, methName, `Protobuf(ctx `, t.pkgs["context"], `.Context, resp `, t.pkgs["http"], `.ResponseWriter, req *`, t.pkgs["http"], `.Request) {`)
-	t.P(`  var err error`)
-	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithMethodName(ctx, "`, methName, `")`)
-	t.P(`  ctx, err = callRequestRouted(ctx, s.hooks)`)
-	t.P(`  if err != nil {`)
-	t.P(`    s.writeError(ctx, resp, err)`)
-	t.P(`    return`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  buf, err := `, t.pkgs["io"], `.ReadAll(req.Body)`)
-	t.P(`  if err != nil {`)
-	t.P(`    s.handleRequestBodyError(ctx, resp, "failed to read request body", err)`)
-	t.P(`    return`)
-	t.P(`  }`)
-	t.P(`  reqContent := new(`, t.goTypeName(method.GetInputType()), `)`)
-	t.P(`  if err = `, t.pkgs["proto"], `.Unmarshal(buf, reqContent); err != nil {`)
-	t.P(`    s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))`)
-	t.P(`    return`)
-	t.P(`  }`)
+	inputType := t.goTypeName(method.GetInputType())
+	serverStream := serverStreamName(service, method)
+
+	t.P(`  stream := &`, unexported(serverStream), `{ServerStream: `, t.pkgs["twirpruntime"], `.NewServerStream(ctx, resp, s.hooks, s.panicHandler, s.errorTransformer, codec)}`)
 	t.P()
 	t.P(`  handler := s.`, servName, `.`, methName)
 	t.P(`  if s.interceptor != nil {`)
-	t.generateServerInterceptorHandler(service, method)
+	t.P(`    handler = func(ctx `, t.pkgs["context"], `.Context, req *`, inputType, `, stream `, serverStream, `) error {`)
+	t.P(`      // Interceptors are called once per stream, the response is always nil`)
+	t.P(`      _, err := s.interceptor(`)
+	t.P(`        func(ctx `, t.pkgs["context"], `.Context, req interface{}) (interface{}, error) {`)
+	t.P(`          typedReq, ok := req.(*`, inputType, `)`)
+	t.P(`          if !ok {`)
+	t.P(`            return nil, `, t.pkgs["twirp"], `.InternalError("failed type assertion req.(*`, inputType, `) when calling interceptor")`)
+	t.P(`          }`)
+	t.P(`          return nil, s.`, servName, `.`, methName, `(ctx, typedReq, stream)`)
+	t.P(`        },`)
+	t.P(`      )(ctx, req)`)
+	t.P(`      return err`)
+	t.P(`    }`)
 	t.P(`  }`)
 	t.P()
-	t.P(`  // Call service method`)
-	t.P(`  var respContent *`, t.goTypeName(method.GetOutputType()))
+	t.P(`  // Call service method, response messages are written by the stream`)
 	t.P(`  func() {`)
-	t.P(`    defer ensurePanicResponses(ctx, resp, s.hooks)`)
-	t.P(`    respContent, err = handler(ctx, reqContent)`)
+	t.generateServerMethodTimeout(method)
+	t.P(`    defer stream.ServerStream.EnsurePanicResponses(&err)`)
+	t.P(`    err = handler(ctx, reqContent, stream)`)
+	t.generateServerMethodTimeoutError(method)
 	t.P(`  }()`)
+	t.P(`  stream.Finish(err)`)
+	t.P(`}`)
 	t.P()
-	t.P(`  if err != nil {`)
-	t.P(`    s.writeError(ctx, resp, err)`)
-	t.P(`    return`)
-	t.P(`  }`)
-	t.P(`  if respContent == nil {`)
-	t.P(`    s.writeError(ctx, resp, `, t.pkgs["twirp"], `.InternalError("received a nil *`, t.goTypeName(method.GetOutputType()), ` and nil error while calling `, methName, `. nil responses are not supported"))`)
-	t.P(`    return`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  ctx = callResponsePrepared(ctx, s.hooks)`)
-	t.P()
-	t.P(`  respBytes, err := `, t.pkgs["proto"], `.Marshal(respContent)`)
-	t.P(`  if err != nil {`)
-	t.P(`    s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))`)
-	t.P(`    return`)
-	t.P(`  }`)
-	t.P()
-	t.P(`  ctx = `, t.pkgs["ctxsetters"], `.WithStatusCode(ctx, `, t.pkgs["http"], `.StatusOK)`)
-	t.P(`  resp.Header().Set("Content-Type", "application/protobuf")`)
-	t.P(`  resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))`)
-	t.P(`  resp.WriteHeader(`, t.pkgs["http"], `.StatusOK)`)
-	t.P(`  if n, err := resp.Write(respBytes); err != nil {`)
-	t.P(`    msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())`)
-	t.P(`    twerr := `, t.pkgs["twirp"], `.NewError(`, t.pkgs["twirp"], `.Unknown, msg)`)
-	t.P(`    ctx = callError(ctx, s.hooks, twerr)`)
+}
+
+// generateServerRequestTimeout generates the start of the serve<Method>Codec function,
+// which applies the timeout from the Twirp-Timeout request header to the context. Methods
+// without a (twirp.method).timeout option keep whether it was applied in requestTimeout.
+func (t *twirp) generateServerRequestTimeout(method *descriptor.MethodDescriptorProto) {
+	timeout, _ := methodTimeout(method)
+	if timeout <= 0 {
+		t.P(`  requestTimeout := false`)
+	}
+	t.P(`  if s.requestTimeouts {`)
+	t.P(`    var cancel `, t.pkgs["context"], `.CancelFunc`)
+	if timeout > 0 {
+		t.P(`    ctx, cancel, _, err = `, t.pkgs["twirpruntime"], `.WithRequestTimeout(ctx, req, s.maxRequestTimeout)`)
+	} else {
+		t.P(`    ctx, cancel, requestTimeout, err = `, t.pkgs["twirpruntime"], `.WithRequestTimeout(ctx, req, s.maxRequestTimeout)`)
+	}
+	t.P(`    defer cancel()`)
+	t.P(`    if err != nil {`)
+	t.P(`      s.writeError(ctx, resp, err)`)
+	t.P(`      return`)
+	t.P(`    }`)
 	t.P(`  }`)
-	t.P(`  callResponseSent(ctx, s.hooks)`)
-	t.P(`}`)
 	t.P()
 }
 
+// generateServerMethodTimeout generates the start of the closure that calls the method
+// implementation, which applies the (twirp.method).timeout option to the context if any.
+func (t *twirp) generateServerMethodTimeout(method *descriptor.MethodDescriptorProto) {
+	if timeout, _ := methodTimeout(method); timeout > 0 {
+		t.P(`    ctx, cancel := `, t.pkgs["context"], `.WithTimeout(ctx, `, t.durationLiteral(timeout), `) // (twirp.method).timeout option`)
+		t.P(`    defer cancel()`)
+	}
+}
+
+// generateServerMethodTimeoutError generates the end of the closure that calls the method
+// implementation, which responds with a deadline_exceeded error if the timeout of the
+// method, or the timeout from the Twirp-Timeout request header, expired. Other deadlines
+// of the request context are left to the method implementation.
+func (t *twirp) generateServerMethodTimeoutError(method *descriptor.MethodDescriptorProto) {
+	if timeout, _ := methodTimeout(method); timeout > 0 {
+		t.P(`    err = `, t.pkgs["twirpruntime"], `.MethodTimeoutError(ctx, err)`)
+		return
+	}
+	t.P(`    if requestTimeout {`)
+	t.P(`      err = `, t.pkgs["twirpruntime"], `.MethodTimeoutError(ctx, err)`)
+	t.P(`    }`)
+}
+
+// methodTimeout returns the timeout of the method declared with the option
+// (twirp.method).timeout from twirp/options.proto, or 0 if it has none.
+func methodTimeout(method *descriptor.MethodDescriptorProto) (time.Duration, error) {
+	if method.GetOptions() == nil {
+		return 0, nil
+	}
+	opts, _ := proto.GetExtension(method.GetOptions(), options.E_Method).(*options.MethodOptions)
+	if opts.GetTimeout() == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(opts.GetTimeout())
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid (twirp.method).timeout option %q, expected a positive Go duration like \"2s\"", opts.GetTimeout())
+	}
+	return timeout, nil
+}
+
+// durationLiteral returns a Go expression for the duration, like 2*time.Second.
+func (t *twirp) durationLiteral(d time.Duration) string {
+	for _, unit := range []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "Hour"},
+		{time.Minute, "Minute"},
+		{time.Second, "Second"},
+		{time.Millisecond, "Millisecond"},
+		{time.Microsecond, "Microsecond"},
+	} {
+		if d%unit.d == 0 {
+			return fmt.Sprintf("%d*%s.%s", d/unit.d, t.pkgs["time"], unit.name)
+		}
+	}
+	return fmt.Sprintf("%s.Duration(%d)", t.pkgs["time"], int64(d))
+}
+
 func (t *twirp) generateClientInterceptorCaller(method *descriptor.MethodDescriptorProto) {
 	methName := methodNameCamelCased(method)
 	t.generateInterceptorFunc("c", "caller", "c.call"+methName, method)
@@ -1488,30 +1466,39 @@ func (t *twirp) serviceMetadataVarName() string {
 	return fmt.Sprintf("twirpFileDescriptor%d", t.filesHandled)
 }
 
-func (t *twirp) generateServiceMetadataAccessors(file *descriptor.FileDescriptorProto, service *descriptor.ServiceDescriptorProto) {
-	servStruct := serviceStruct(service)
-	servPkg := pkgName(file)
-
+// serviceIndex returns the index of the service in the file, as returned by the
+// generated ServiceDescriptor methods.
+func serviceIndex(file *descriptor.FileDescriptorProto, service *descriptor.ServiceDescriptorProto) int {
 	index := 0
 	for i, s := range file.Service {
 		if s.GetName() == service.GetName() {
 			index = i
 		}
 	}
+	return index
+}
+
+func (t *twirp) generateServiceMetadataAccessors(file *descriptor.FileDescriptorProto, service *descriptor.ServiceDescriptorProto) {
+	servStruct := serviceStruct(service)
+	servPkg := pkgName(file)
+
 	t.P(`func (s *`, servStruct, `) ServiceDescriptor() ([]byte, int) {`)
-	t.P(`  return `, t.serviceMetadataVarName(), `, `, strconv.Itoa(index))
+	t.P(`  return `, t.serviceMetadataVarName(), `, `, strconv.Itoa(serviceIndex(file, service)))
 	t.P(`}`)
 	t.P()
 	t.P(`func (s *`, servStruct, `) ProtocGenTwirpVersion() (string) {`)
 	t.P(`  return `, strconv.Quote(gen.Version))
 	t.P(`}`)
 	t.P()
+	t.P(`// AppliesMuxServerOptions marks servers that apply the hooks and interceptors shared by a twirp.Mux.`)
+	t.P(`func (s *`, servStruct, `) AppliesMuxServerOptions() {}`)
+	t.P()
 	t.P(`// PathPrefix returns the base service path, in the form: "/<prefix>/<package>.<Service>/"`)
 	t.P(`// that is everything in a Twirp route except for the <Method>. This can be used for routing,`)
 	t.P(`// for example to identify the requests that are targeted to this service in a mux.`)
 	t.P(`func (s *`, servStruct, `) PathPrefix() (string) {`)
 	servName := serviceNameCamelCased(service) // it should be serviceNameLiteral(service), but needs to use CamelCase routes for backwards compatibility
-	t.P(`  return baseServicePath(s.pathPrefix, "`, servPkg, `", "`, servName, `") `)
+	t.P(`  return `, t.pkgs["twirpruntime"], `.BaseServicePath(s.pathPrefix, "`, servPkg, `", "`, servName, `") `)
 	t.P(`}`)
 }
 
@@ -17,9 +17,13 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
 
 	"google.golang.org/protobuf/proto"
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
 	plugin "google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/twitchtv/twirp/options"
 )
 
 func TestGenerateParseCommandLineParamsError(t *testing.T) {
@@ -38,3 +42,59 @@ func TestGenerateParseCommandLineParamsError(t *testing.T) {
 	}
 	t.Fatalf("process ran with err %v, want exit status 1", err)
 }
+
+func TestMethodTimeout(t *testing.T) {
+	tests := []struct {
+		timeout string
+		want    time.Duration
+		literal string
+		wantErr bool
+	}{
+		{"", 0, "", false},
+		{"2s", 2 * time.Second, "2*time.Second", false},
+		{"1m30s", 90 * time.Second, "90*time.Second", false},
+		{"250ms", 250 * time.Millisecond, "250*time.Millisecond", false},
+		{"1.5us", 1500 * time.Nanosecond, "time.Duration(1500)", false},
+		{"0s", 0, "", true},
+		{"-1s", 0, "", true},
+		{"soon", 0, "", true},
+	}
+	g := &twirp{pkgs: map[string]string{"time": "time"}}
+	for _, tt := range tests {
+		method := &descriptor.MethodDescriptorProto{Options: &descriptor.MethodOptions{}}
+		if tt.timeout != "" {
+			proto.SetExtension(method.Options, options.E_Method, &options.MethodOptions{Timeout: tt.timeout})
+		}
+		have, err := methodTimeout(method)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("methodTimeout(%q) expected an error", tt.timeout)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("methodTimeout(%q) err=%q", tt.timeout, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("methodTimeout(%q) = %v, want %v", tt.timeout, have, tt.want)
+		}
+		if have != 0 {
+			if literal := g.durationLiteral(have); literal != tt.literal {
+				t.Errorf("durationLiteral(%v) = %q, want %q", have, literal, tt.literal)
+			}
+		}
+	}
+}
+
+func TestReservePackageName(t *testing.T) {
+	g := newGenerator()
+	g.registerPackageName("twirp")
+	// the package of twirp/options.proto is also named twirp
+	if alias := g.reservePackageName("twirp"); alias != "twirp1" {
+		t.Errorf("reservePackageName(%q) = %q, want %q", "twirp", alias, "twirp1")
+	}
+	if g.pkgs["twirp"] != "twirp" {
+		t.Errorf("the twirp package alias changed to %q", g.pkgs["twirp"])
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/twitchtv/twirp/internal/gen"
+	"google.golang.org/protobuf/proto"
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+	plugin "google.golang.org/protobuf/types/pluginpb"
+)
+
+// generateMocks generates the _mock.twirp.go file with a mock implementation of the
+// interface of each service in the file. Enabled with the mocks=true parameter.
+func (t *twirp) generateMocks(file *descriptor.FileDescriptorProto) *plugin.CodeGeneratorResponse_File {
+	t.P("// Code generated by protoc-gen-twirp ", gen.Version, ", DO NOT EDIT.")
+	t.P("// source: ", file.GetName())
+	t.P()
+	t.P(`package `, t.genPkgName)
+	t.P()
+	hasMethods := false
+	for _, service := range file.Service {
+		hasMethods = hasMethods || len(service.Method) > 0
+	}
+	if hasMethods {
+		t.P(`import `, t.pkgs["context"], ` "context"`)
+	}
+	t.P(`import `, t.pkgs["sync"], ` "sync"`)
+	t.P()
+	t.P(`import `, t.pkgs["proto"], ` "google.golang.org/protobuf/proto"`)
+	if hasMethods {
+		t.P(`import `, t.pkgs["twirp"], ` "github.com/twitchtv/twirp"`)
+	}
+	t.P()
+	t.generateMessageImports(file)
+
+	for _, service := range file.Service {
+		t.sectionComment(serviceNameCamelCased(service) + ` Mock`)
+		t.generateMock(service)
+	}
+
+	resp := new(plugin.CodeGeneratorResponse_File)
+	resp.Name = proto.String(strings.TrimSuffix(t.goFileName(file), ".twirp.go") + "_mock.twirp.go")
+	resp.Content = proto.String(t.formattedOutput())
+	t.output.Reset()
+	return resp
+}
+
+func mockName(service *descriptor.ServiceDescriptorProto) string {
+	return serviceNameCamelCased(service) + "Mock"
+}
+
+func (t *twirp) generateMock(service *descriptor.ServiceDescriptorProto) {
+	servName := serviceNameCamelCased(service)
+	mock := mockName(service)
+
+	t.P(`// `, mock, ` is a mock implementation of the `, servName, ` interface, to be used in tests.`)
+	t.P(`//`)
+	t.P(`// Calls to each method are recorded, and answered with the first matching expectation`)
+	t.P(`// added with Expect<Method>. Calls that match no expectation are answered by the`)
+	t.P(`// <Method>Func stub if not nil, or fail with a twirp.Unimplemented error. Call`)
+	t.P(`// AssertExpectations at the end of the test to check that all the expectations were`)
+	t.P(`// met, and that there were no unexpected calls.`)
+	t.P(`type `, mock, ` struct {`)
+	for _, method := range service.Method {
+		methName := methodNameCamelCased(method)
+		t.P(`  // `, methName, `Func stubs `, methName, ` for calls that match no expectation.`)
+		t.P(`  `, methName, `Func func`, t.mockSignature(service, method))
+		t.P()
+	}
+	t.P(`  mu         `, t.pkgs["sync"], `.Mutex`)
+	for _, method := range service.Method {
+		methName := methodNameCamelCased(method)
+		t.P(`  `, unexported(methName), `Calls        []*`, mock, methName, `Call`)
+		t.P(`  `, unexported(methName), `Expectations []*`, mock, methName, `Expectation`)
+	}
+	t.P(`  unexpected []`, unexported(mock), `Call`)
+	t.P(`}`)
+	t.P()
+	t.P(`var _ `, servName, ` = (*`, mock, `)(nil)`)
+	t.P()
+	t.P(`// `, unexported(mock), `Call is a call that matched no expectation or stub, reported by AssertExpectations.`)
+	t.P(`type `, unexported(mock), `Call struct {`)
+	t.P(`  method string`)
+	t.P(`  req    `, t.pkgs["proto"], `.Message`)
+	t.P(`}`)
+
+	for _, method := range service.Method {
+		t.generateMockMethod(service, method)
+	}
+
+	t.P()
+	t.P(`// AssertExpectations reports on t the expectations that were not met, and the calls`)
+	t.P(`// that matched no expectation or stub.`)
+	t.P(`func (m *`, mock, `) AssertExpectations(t interface {`)
+	t.P(`  Helper()`)
+	t.P(`  Errorf(format string, args ...interface{})`)
+	t.P(`}) {`)
+	t.P(`  t.Helper()`)
+	t.P(`  m.mu.Lock()`)
+	t.P(`  defer m.mu.Unlock()`)
+	for _, method := range service.Method {
+		methName := methodNameCamelCased(method)
+		t.P(`  for _, e := range m.`, unexported(methName), `Expectations {`)
+		t.P(`    if e.called {`)
+		t.P(`      continue`)
+		t.P(`    }`)
+		t.P(`    if e.req == nil {`)
+		t.P(`      t.Errorf("`, mock, `: expected call to `, methName, ` was not made")`)
+		t.P(`    } else {`)
+		t.P(`      t.Errorf("`, mock, `: expected call to `, methName, ` with request %v was not made", e.req)`)
+		t.P(`    }`)
+		t.P(`  }`)
+	}
+	t.P(`  for _, call := range m.unexpected {`)
+	t.P(`    t.Errorf("`, mock, `: unexpected call to %s with request %v", call.method, call.req)`)
+	t.P(`  }`)
+	t.P(`}`)
+}
+
+// mockSignature is the signature of the method in the service interface, without the name.
+func (t *twirp) mockSignature(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) string {
+	return strings.TrimPrefix(strings.TrimSpace(t.generateSignature(service, method)), methodNameCamelCased(method))
+}
+
+func (t *twirp) generateMockMethod(service *descriptor.ServiceDescriptorProto, method *descriptor.MethodDescriptorProto) {
+	mock := mockName(service)
+	methName := methodNameCamelCased(method)
+	inputType := t.goTypeName(method.GetInputType())
+	outputType := t.goTypeName(method.GetOutputType())
+	callType := mock + methName + `Call`
+	expectationType := mock + methName + `Expectation`
+	calls := `m.` + unexported(methName) + `Calls`
+	expectations := `m.` + unexported(methName) + `Expectations`
+	streaming := method.GetServerStreaming()
+
+	respType := `*` + outputType
+	if streaming {
+		respType = `[]*` + outputType
+	}
+
+	t.P()
+	t.P(`// `, callType, ` is a call to `, mock, `.`, methName, `.`)
+	t.P(`type `, callType, ` struct {`)
+	t.P(`  Ctx `, t.pkgs["context"], `.Context`)
+	t.P(`  Req *`, inputType)
+	if !streaming {
+		t.P(`  Resp *`, outputType, ` // nil until the call returns`)
+	}
+	t.P(`  Err error`)
+	t.P(`}`)
+	t.P()
+	t.P(`// `, expectationType, ` is an expected call to `, mock, `.`, methName, `, added with Expect`, methName, `.`)
+	t.P(`type `, expectationType, ` struct {`)
+	t.P(`  req    *`, inputType)
+	t.P(`  resp   `, respType)
+	t.P(`  err    error`)
+	t.P(`  called bool`)
+	t.P(`}`)
+	t.P()
+	if streaming {
+		t.P(`// Return sets the messages sent by the expected call, and the error returned after`)
+		t.P(`// sending them. Use a twirp.Error to end the stream with a specific error code.`)
+	} else {
+		t.P(`// Return sets the values returned by the expected call. Use a twirp.Error to fail`)
+		t.P(`// the call with a specific error code.`)
+	}
+	t.P(`func (e *`, expectationType, `) Return(resp `, respType, `, err error) *`, expectationType, ` {`)
+	t.P(`  e.resp = resp`)
+	t.P(`  e.err = err`)
+	t.P(`  return e`)
+	t.P(`}`)
+	t.P()
+	t.P(`// Expect`, methName, ` adds an expected call to `, methName, ` with a request equal to req,`)
+	t.P(`// compared with proto.Equal, or with any request if req is nil. Each expectation`)
+	if streaming {
+		t.P(`// matches a single call. By default the call sends no messages, use Return to change it.`)
+	} else {
+		t.P(`// matches a single call. By default the call returns an empty response, use Return to change it.`)
+	}
+	t.P(`func (m *`, mock, `) Expect`, methName, `(req *`, inputType, `) *`, expectationType, ` {`)
+	if streaming {
+		t.P(`  e := &`, expectationType, `{req: req}`)
+	} else {
+		t.P(`  e := &`, expectationType, `{req: req, resp: &`, outputType, `{}}`)
+	}
+	t.P(`  m.mu.Lock()`)
+	t.P(`  defer m.mu.Unlock()`)
+	t.P(`  `, expectations, ` = append(`, expectations, `, e)`)
+	t.P(`  return e`)
+	t.P(`}`)
+	t.P()
+	t.P(`// `, methName, `Calls returns the calls made to `, methName, `, in order.`)
+	t.P(`func (m *`, mock, `) `, methName, `Calls() []`, callType, ` {`)
+	t.P(`  m.mu.Lock()`)
+	t.P(`  defer m.mu.Unlock()`)
+	t.P(`  calls := make([]`, callType, `, len(`, calls, `))`)
+	t.P(`  for i, call := range `, calls, ` {`)
+	t.P(`    calls[i] = *call`)
+	t.P(`  }`)
+	t.P(`  return calls`)
+	t.P(`}`)
+	t.P()
+
+	t.P(`// `, methName, ` implements the `, serviceNameCamelCased(service), ` interface.`)
+	if streaming {
+		t.P(`func (m *`, mock, `) `, methName, `(ctx `, t.pkgs["context"], `.Context, req *`, inputType, `, stream `, serverStreamName(service, method), `) error {`)
+	} else {
+		t.P(`func (m *`, mock, `) `, methName, `(ctx `, t.pkgs["context"], `.Context, req *`, inputType, `) (*`, outputType, `, error) {`)
+	}
+	t.P(`  m.mu.Lock()`)
+	t.P(`  call := &`, callType, `{Ctx: ctx, Req: req}`)
+	t.P(`  `, calls, ` = append(`, calls, `, call)`)
+	t.P(`  var expected *`, expectationType)
+	t.P(`  for _, e := range `, expectations, ` {`)
+	t.P(`    if !e.called && (e.req == nil || `, t.pkgs["proto"], `.Equal(e.req, req)) {`)
+	t.P(`      e.called = true`)
+	t.P(`      expected = e`)
+	t.P(`      break`)
+	t.P(`    }`)
+	t.P(`  }`)
+	t.P(`  stub := m.`, methName, `Func`)
+	t.P(`  if expected == nil && stub == nil {`)
+	t.P(`    m.unexpected = append(m.unexpected, `, unexported(mock), `Call{method: "`, methName, `", req: req})`)
+	t.P(`  }`)
+	t.P(`  m.mu.Unlock()`)
+	t.P()
+	if streaming {
+		t.P(`  var err error`)
+		t.P(`  switch {`)
+		t.P(`  case expected != nil:`)
+		t.P(`    err = expected.err`)
+		t.P(`    for _, msg := range expected.resp {`)
+		t.P(`      if sendErr := stream.Send(msg); sendErr != nil {`)
+		t.P(`        err = sendErr`)
+		t.P(`        break`)
+		t.P(`      }`)
+		t.P(`    }`)
+		t.P(`  case stub != nil:`)
+		t.P(`    err = stub(ctx, req, stream)`)
+		t.P(`  default:`)
+		t.P(`    err = `, t.pkgs["twirp"], `.NewError(`, t.pkgs["twirp"], `.Unimplemented, "unexpected call to `, mock, `.`, methName, `")`)
+		t.P(`  }`)
+		t.P()
+		t.P(`  m.mu.Lock()`)
+		t.P(`  call.Err = err`)
+		t.P(`  m.mu.Unlock()`)
+		t.P(`  return err`)
+	} else {
+		t.P(`  var resp *`, outputType)
+		t.P(`  var err error`)
+		t.P(`  switch {`)
+		t.P(`  case expected != nil:`)
+		t.P(`    resp, err = expected.resp, expected.err`)
+		t.P(`  case stub != nil:`)
+		t.P(`    resp, err = stub(ctx, req)`)
+		t.P(`  default:`)
+		t.P(`    err = `, t.pkgs["twirp"], `.NewError(`, t.pkgs["twirp"], `.Unimplemented, "unexpected call to `, mock, `.`, methName, `")`)
+		t.P(`  }`)
+		t.P()
+		t.P(`  m.mu.Lock()`)
+		t.P(`  call.Resp, call.Err = resp, err`)
+		t.P(`  m.mu.Unlock()`)
+		t.P(`  return resp, err`)
+	}
+	t.P(`}`)
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package reflection implements the twirp.reflection.v1.Reflection service,
+// that describes the Twirp services served by a server. Tools like CLIs and
+// web explorers can use it to discover APIs without local .proto files.
+//
+// The reflection service is opt-in: build it with the servers to describe,
+// and serve it next to them, for example in a twirp.Mux:
+//
+//	haberdasherServer := haberdasher.NewHaberdasherServer(impl)
+//	reflectionService, err := reflection.NewService(haberdasherServer)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	mux, err := twirp.NewMux([]twirp.Server{
+//	    haberdasherServer,
+//	    reflection.NewReflectionServer(reflectionService),
+//	})
+package reflection
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/internal/descriptors"
+)
+
+// service implements the Reflection interface.
+type service struct {
+	services []*Service                                   // sorted by name
+	files    map[string]*descriptorpb.FileDescriptorProto // by file name, with all transitive dependencies
+}
+
+// NewService returns an implementation of the Reflection service that
+// describes the given servers. Serve it with NewReflectionServer.
+//
+// Dependencies of the .proto files that define the services are looked up in
+// the global protobuf registry (protoregistry.GlobalFiles), where they are
+// registered by the Go packages generated with protoc-gen-go. An error is
+// returned if a dependency can not be found.
+func NewService(servers ...twirp.Server) (Reflection, error) {
+	s := &service{files: make(map[string]*descriptorpb.FileDescriptorProto)}
+
+	for _, server := range servers {
+		fd, sd, err := descriptors.ServiceDescriptor(server)
+		if err != nil {
+			return nil, fmt.Errorf("reflection: invalid service descriptor for path prefix %q: %w", server.PathPrefix(), err)
+		}
+		if _, ok := s.files[fd.GetName()]; !ok {
+			s.files[fd.GetName()] = fd
+		}
+
+		svc := &Service{
+			Name:                  fullName(fd.GetPackage(), sd.GetName()),
+			PathPrefix:            server.PathPrefix(),
+			File:                  fd.GetName(),
+			ProtocGenTwirpVersion: server.ProtocGenTwirpVersion(),
+		}
+		for _, md := range sd.GetMethod() {
+			svc.Methods = append(svc.Methods, &Method{
+				Name:       md.GetName(),
+				InputType:  strings.TrimPrefix(md.GetInputType(), "."),
+				OutputType: strings.TrimPrefix(md.GetOutputType(), "."),
+			})
+		}
+		s.services = append(s.services, svc)
+	}
+	sort.Slice(s.services, func(i, j int) bool {
+		return s.services[i].Name < s.services[j].Name
+	})
+
+	// Load all transitive dependencies up front, so missing files are reported early
+	pending := make([]*descriptorpb.FileDescriptorProto, 0, len(s.files))
+	for _, fd := range s.files {
+		pending = append(pending, fd)
+	}
+	for len(pending) > 0 {
+		fd := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, dep := range fd.GetDependency() {
+			if _, ok := s.files[dep]; ok {
+				continue
+			}
+			depDesc, err := protoregistry.GlobalFiles.FindFileByPath(dep)
+			if err != nil {
+				return nil, fmt.Errorf("reflection: dependency %q of %q not found: %w", dep, fd.GetName(), err)
+			}
+			depFile := protodesc.ToFileDescriptorProto(depDesc)
+			s.files[dep] = depFile
+			pending = append(pending, depFile)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *service) ListServices(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error) {
+	resp := &ListServicesResponse{}
+	for _, svc := range s.services {
+		resp.Services = append(resp.Services, proto.Clone(svc).(*Service))
+	}
+	return resp, nil
+}
+
+func (s *service) GetFileDescriptorSet(ctx context.Context, req *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error) {
+	var serviceFiles []string
+	if len(req.GetServices()) == 0 {
+		for _, svc := range s.services {
+			serviceFiles = append(serviceFiles, svc.File)
+		}
+	}
+	for _, name := range req.GetServices() {
+		svc := s.findService(name)
+		if svc == nil {
+			return nil, twirp.NotFoundError(fmt.Sprintf("service %q not found", name)).WithMeta("service", name)
+		}
+		serviceFiles = append(serviceFiles, svc.File)
+	}
+
+	// Add files after their dependencies (depth-first post-order)
+	set := &descriptorpb.FileDescriptorSet{}
+	added := make(map[string]bool)
+	var add func(name string)
+	add = func(name string) {
+		if added[name] {
+			return
+		}
+		added[name] = true
+		fd := s.files[name]
+		for _, dep := range fd.GetDependency() {
+			add(dep)
+		}
+		set.File = append(set.File, proto.Clone(fd).(*descriptorpb.FileDescriptorProto))
+	}
+	for _, name := range serviceFiles {
+		add(name)
+	}
+
+	return &GetFileDescriptorSetResponse{FileDescriptorSet: set}, nil
+}
+
+func (s *service) findService(name string) *Service {
+	i := sort.Search(len(s.services), func(i int) bool { return s.services[i].Name >= name })
+	if i < len(s.services) && s.services[i].Name == name {
+		return s.services[i]
+	}
+	return nil
+}
+
+func fullName(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
@@ -0,0 +1,851 @@
+// Code generated by protoc-gen-twirp v8.3.0, DO NOT EDIT.
+// source: reflection.proto
+
+package reflection
+
+import context "context"
+import fmt "fmt"
+import http "net/http"
+import io "io"
+import strconv "strconv"
+import time "time"
+
+import twirp "github.com/twitchtv/twirp"
+import ctxsetters "github.com/twitchtv/twirp/ctxsetters"
+import twirpruntime "github.com/twitchtv/twirp/twirpruntime"
+
+// Version compatibility assertion.
+// If the constant is not defined in the package, that likely means
+// the package needs to be updated to work with this generated code.
+// See https://twitchtv.github.io/twirp/docs/version_matrix.html
+const _ = twirp.TwirpPackageMinVersion_8_3_0
+
+// ====================
+// Reflection Interface
+// ====================
+
+// Reflection describes the Twirp services served by a server, so tools can
+// discover APIs without local copies of the .proto files.
+type Reflection interface {
+	// ListServices returns the services and methods registered in the
+	// reflection service.
+	ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error)
+
+	// GetFileDescriptorSet returns the descriptors of the .proto files that
+	// define the services, including all their transitive dependencies.
+	GetFileDescriptorSet(context.Context, *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error)
+}
+
+// ==========================
+// Reflection Protobuf Client
+// ==========================
+
+type reflectionProtobufClient struct {
+	client            HTTPClient
+	urls              [2]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // Protobuf unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+}
+
+// NewReflectionProtobufClient creates a Protobuf client that implements the Reflection interface.
+// It communicates using Protobuf and can be configured with a custom HTTPClient.
+func NewReflectionProtobufClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) Reflection {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ProtobufCodec
+	_ = clientOpts.ReadOpt("codec", &codec)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "twirp.reflection.v1", "Reflection")
+	urls := [2]string{
+		serviceURL + "ListServices",
+		serviceURL + "GetFileDescriptorSet",
+	}
+
+	return &reflectionProtobufClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+func (c *reflectionProtobufClient) ListServices(ctx context.Context, in *ListServicesRequest) (*ListServicesResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.reflection.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "Reflection")
+	ctx = ctxsetters.WithMethodName(ctx, "ListServices")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["ListServices"])
+	caller := c.callListServices
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListServicesRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListServicesRequest) when calling interceptor")
+					}
+					return c.callListServices(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListServicesResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListServicesResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *reflectionProtobufClient) callListServices(ctx context.Context, in *ListServicesRequest) (*ListServicesResponse, error) {
+	out := new(ListServicesResponse)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *reflectionProtobufClient) GetFileDescriptorSet(ctx context.Context, in *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.reflection.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "Reflection")
+	ctx = ctxsetters.WithMethodName(ctx, "GetFileDescriptorSet")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["GetFileDescriptorSet"])
+	caller := c.callGetFileDescriptorSet
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*GetFileDescriptorSetRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*GetFileDescriptorSetRequest) when calling interceptor")
+					}
+					return c.callGetFileDescriptorSet(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*GetFileDescriptorSetResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*GetFileDescriptorSetResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *reflectionProtobufClient) callGetFileDescriptorSet(ctx context.Context, in *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error) {
+	out := new(GetFileDescriptorSetResponse)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *reflectionProtobufClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// ======================
+// Reflection JSON Client
+// ======================
+
+type reflectionJSONClient struct {
+	client            HTTPClient
+	urls              [2]string
+	interceptor       twirp.Interceptor
+	opts              twirp.ClientOptions
+	maxResponseBytes  int64
+	compression       twirpruntime.Compression
+	codec             twirp.Codec                    // JSON unless replaced with twirp.WithClientCodec
+	methodDescriptors twirpruntime.MethodDescriptors // added to request contexts
+}
+
+// NewReflectionJSONClient creates a JSON client that implements the Reflection interface.
+// It communicates using JSON and can be configured with a custom HTTPClient.
+func NewReflectionJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOption) Reflection {
+	if c, ok := client.(*http.Client); ok {
+		client = twirpruntime.WithoutRedirects(c)
+	}
+
+	clientOpts := twirp.ClientOptions{}
+	for _, o := range opts {
+		o(&clientOpts)
+	}
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	literalURLs := false
+	_ = clientOpts.ReadOpt("literalURLs", &literalURLs)
+	var pathPrefix string
+	if ok := clientOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+	var maxResponseBytes int64
+	_ = clientOpts.ReadOpt("maxResponseBytes", &maxResponseBytes)
+	codec := twirpruntime.ReadJSONCodec(&clientOpts)
+	_ = clientOpts.ReadOpt("codec", &codec)
+
+	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
+	serviceURL := twirpruntime.SanitizeBaseURL(baseURL)
+	serviceURL += twirpruntime.BaseServicePath(pathPrefix, "twirp.reflection.v1", "Reflection")
+	urls := [2]string{
+		serviceURL + "ListServices",
+		serviceURL + "GetFileDescriptorSet",
+	}
+
+	return &reflectionJSONClient{
+		client:            client,
+		urls:              urls,
+		interceptor:       twirp.ChainInterceptors(clientOpts.Interceptors...),
+		opts:              clientOpts,
+		maxResponseBytes:  maxResponseBytes,
+		compression:       twirpruntime.ReadCompression(&clientOpts),
+		codec:             codec,
+		methodDescriptors: twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+func (c *reflectionJSONClient) ListServices(ctx context.Context, in *ListServicesRequest) (*ListServicesResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.reflection.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "Reflection")
+	ctx = ctxsetters.WithMethodName(ctx, "ListServices")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["ListServices"])
+	caller := c.callListServices
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListServicesRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListServicesRequest) when calling interceptor")
+					}
+					return c.callListServices(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListServicesResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListServicesResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *reflectionJSONClient) callListServices(ctx context.Context, in *ListServicesRequest) (*ListServicesResponse, error) {
+	out := new(ListServicesResponse)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[0], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *reflectionJSONClient) GetFileDescriptorSet(ctx context.Context, in *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.reflection.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "Reflection")
+	ctx = ctxsetters.WithMethodName(ctx, "GetFileDescriptorSet")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, c.methodDescriptors["GetFileDescriptorSet"])
+	caller := c.callGetFileDescriptorSet
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*GetFileDescriptorSetRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*GetFileDescriptorSetRequest) when calling interceptor")
+					}
+					return c.callGetFileDescriptorSet(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*GetFileDescriptorSetResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*GetFileDescriptorSetResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *reflectionJSONClient) callGetFileDescriptorSet(ctx context.Context, in *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error) {
+	out := new(GetFileDescriptorSetResponse)
+	ctx, err := twirpruntime.DoRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out, c.codec, c.maxResponseBytes, c.compression, false)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		twirpruntime.CallClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	twirpruntime.CallClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ServiceDescriptor returns gzipped bytes describing the .proto file that this service was
+// generated from, and the index of the service in it. Used by the descriptors package.
+func (c *reflectionJSONClient) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+// =========================
+// Reflection Server Handler
+// =========================
+
+type reflectionServer struct {
+	Reflection
+	interceptor           twirp.Interceptor
+	hooks                 *twirp.ServerHooks
+	pathPrefix            string                                                 // prefix for routing
+	maxRequestBytes       int64                                                  // limit for request bodies, no limit if 0 or less
+	methodMaxRequestBytes map[string]int64                                       // per-method overrides of maxRequestBytes
+	compression           twirpruntime.Compression                               // request decompression and response compression
+	requestTimeouts       bool                                                   // apply timeouts from the Twirp-Timeout request header
+	maxRequestTimeout     time.Duration                                          // limit for timeouts from the Twirp-Timeout request header
+	panicHandler          func(context.Context, interface{}, []byte) twirp.Error // handles panics in methods, re-panic if nil
+	errorTransformer      func(context.Context, error) twirp.Error               // maps errors before they are sent, optional
+	codecs                map[string]twirp.Codec                                 // codecs by content type, including the built-in Protobuf and JSON codecs
+	methodDescriptors     twirpruntime.MethodDescriptors                         // added to request contexts
+}
+
+// NewReflectionServer builds a TwirpServer that can be used as an http.Handler to handle
+// HTTP requests that are routed to the right method in the provided svc implementation.
+// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
+func NewReflectionServer(svc Reflection, opts ...interface{}) TwirpServer {
+	serverOpts := twirpruntime.NewServerOptions(opts)
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	var maxRequestBytes int64
+	_ = serverOpts.ReadOpt("maxRequestBytes", &maxRequestBytes)
+	var methodMaxRequestBytes map[string]int64
+	_ = serverOpts.ReadOpt("methodMaxRequestBytes", &methodMaxRequestBytes)
+	requestTimeouts := true
+	_ = serverOpts.ReadOpt("requestTimeouts", &requestTimeouts)
+	var maxRequestTimeout time.Duration
+	_ = serverOpts.ReadOpt("maxRequestTimeout", &maxRequestTimeout)
+	var panicHandler func(context.Context, interface{}, []byte) twirp.Error
+	_ = serverOpts.ReadOpt("panicHandler", &panicHandler)
+	var errorTransformer func(context.Context, error) twirp.Error
+	_ = serverOpts.ReadOpt("errorTransformer", &errorTransformer)
+	var pathPrefix string
+	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+
+	return &reflectionServer{
+		Reflection:            svc,
+		hooks:                 serverOpts.Hooks,
+		interceptor:           twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:            pathPrefix,
+		maxRequestBytes:       maxRequestBytes,
+		methodMaxRequestBytes: methodMaxRequestBytes,
+		compression:           twirpruntime.ReadCompression(serverOpts),
+		requestTimeouts:       requestTimeouts,
+		maxRequestTimeout:     maxRequestTimeout,
+		panicHandler:          panicHandler,
+		errorTransformer:      errorTransformer,
+		codecs:                twirpruntime.ReadServerCodecs(serverOpts),
+		methodDescriptors:     twirpruntime.NewMethodDescriptors(twirpFileDescriptor0, 0),
+	}
+}
+
+// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// The error is first mapped by the error transformer, if any (see twirp.WithServerErrorTransformer).
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func (s *reflectionServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(ctx, resp, twirpruntime.TransformError(ctx, err, s.errorTransformer), s.hooks)
+}
+
+// handleRequestBodyError is used to handle error when the twirp server cannot read request
+func (s *reflectionServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if twerr, ok := err.(twirp.Error); ok {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if twerr := twirpruntime.BodyTooLargeTwirpError(err, "request "); twerr != nil {
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	if context.Canceled == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
+		return
+	}
+	if context.DeadlineExceeded == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
+		return
+	}
+	s.writeError(ctx, resp, twirp.WrapError(twirpruntime.MalformedRequestError(msg), err))
+}
+
+// requestBody returns the request body, decompressed if it has a Content-Encoding,
+// and limited to the max size configured for the method. It must be closed after reading.
+func (s *reflectionServer) requestBody(req *http.Request, method string) (io.ReadCloser, error) {
+	maxBytes := s.maxRequestBytes
+	if n, ok := s.methodMaxRequestBytes[method]; ok {
+		maxBytes = n
+	}
+	return twirpruntime.RequestBody(req, maxBytes, s.compression)
+}
+
+// ReflectionPathPrefix is a convenience constant that may identify URL paths.
+// Should be used with caution, it only matches routes generated by Twirp Go clients,
+// with the default "/twirp" prefix and default CamelCase service and method names.
+// More info: https://twitchtv.github.io/twirp/docs/routing.html
+const ReflectionPathPrefix = "/twirp/twirp.reflection.v1.Reflection/"
+
+func (s *reflectionServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if hooks, interceptor, ok := twirpruntime.MuxServerOptions(ctx); ok {
+		// Served by a twirp.Mux: apply the shared hooks and interceptors before the ones from this server
+		shared := *s
+		shared.hooks = twirp.ChainHooks(hooks, s.hooks)
+		shared.interceptor = twirp.ChainInterceptors(interceptor, s.interceptor)
+		s = &shared
+	}
+	ctx = ctxsetters.WithPackageName(ctx, "twirp.reflection.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "Reflection")
+	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	ctx = twirpruntime.WithErrorDetailsAccepted(ctx, req) // only clients that send Twirp-Error-Details get error details
+
+	var err error
+	ctx, err = twirpruntime.CallRequestReceived(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	if req.Method != "POST" {
+		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
+	prefix, pkgService, method := twirpruntime.ParseTwirpPath(req.URL.Path)
+	if pkgService != "twirp.reflection.v1.Reflection" {
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+	if prefix != s.pathPrefix {
+		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	switch method {
+	case "ListServices":
+		s.serveListServices(ctx, resp, req)
+		return
+	case "GetFileDescriptorSet":
+		s.serveGetFileDescriptorSet(ctx, resp, req)
+		return
+	default:
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, twirpruntime.BadRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+}
+
+func (s *reflectionServer) serveListServices(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveListServicesCodec(ctx, resp, req, codec)
+}
+
+func (s *reflectionServer) serveListServicesCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "ListServices")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["ListServices"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "ListServices")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(ListServicesRequest)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	handler := s.Reflection.ListServices
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListServicesRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListServicesRequest) when calling interceptor")
+					}
+					return s.Reflection.ListServices(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListServicesResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListServicesResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ListServicesResponse
+	func() {
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
+		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListServicesResponse and nil error while calling ListServices. nil responses are not supported"))
+		return
+	}
+
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
+
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
+	}
+	twirpruntime.CallResponseSent(ctx, s.hooks)
+}
+
+func (s *reflectionServer) serveGetFileDescriptorSet(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	codec, ok := twirpruntime.RequestCodec(s.codecs, req)
+	if !ok {
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := twirpruntime.BadRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+		return
+	}
+	s.serveGetFileDescriptorSetCodec(ctx, resp, req, codec)
+}
+
+func (s *reflectionServer) serveGetFileDescriptorSetCodec(ctx context.Context, resp http.ResponseWriter, req *http.Request, codec twirp.Codec) {
+	var err error
+	requestTimeout := false
+	if s.requestTimeouts {
+		var cancel context.CancelFunc
+		ctx, cancel, requestTimeout, err = twirpruntime.WithRequestTimeout(ctx, req, s.maxRequestTimeout)
+		defer cancel()
+		if err != nil {
+			s.writeError(ctx, resp, err)
+			return
+		}
+	}
+
+	ctx = ctxsetters.WithMethodName(ctx, "GetFileDescriptorSet")
+	ctx = ctxsetters.WithMethodDescriptor(ctx, s.methodDescriptors["GetFileDescriptorSet"])
+	ctx, err = twirpruntime.CallRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqBody, err := s.requestBody(req, "GetFileDescriptorSet")
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to decompress request body", err)
+		return
+	}
+	defer func() { _ = reqBody.Close() }()
+
+	buf, err := io.ReadAll(reqBody)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, twirpruntime.RequestBodyErrorMessage(codec), err)
+		return
+	}
+	reqContent := new(GetFileDescriptorSetRequest)
+	if err = codec.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, twirpruntime.RequestDecodingError(codec, err))
+		return
+	}
+
+	handler := s.Reflection.GetFileDescriptorSet
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *GetFileDescriptorSetRequest) (*GetFileDescriptorSetResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*GetFileDescriptorSetRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*GetFileDescriptorSetRequest) when calling interceptor")
+					}
+					return s.Reflection.GetFileDescriptorSet(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*GetFileDescriptorSetResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*GetFileDescriptorSetResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *GetFileDescriptorSetResponse
+	func() {
+		defer twirpruntime.EnsurePanicResponses(ctx, resp, s.hooks, s.panicHandler, &err)
+		respContent, err = handler(ctx, reqContent)
+		if requestTimeout {
+			err = twirpruntime.MethodTimeoutError(ctx, err)
+		}
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GetFileDescriptorSetResponse and nil error while calling GetFileDescriptorSet. nil responses are not supported"))
+		return
+	}
+
+	ctx = twirpruntime.CallResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := codec.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to marshal response"))
+		return
+	}
+
+	respBytes, contentEncoding, err := s.compression.CompressResponseBody(req, respBytes)
+	if err != nil {
+		s.writeError(ctx, resp, twirpruntime.WrapInternal(err, "failed to compress response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", codec.ContentType())
+	s.compression.SetResponseHeaders(resp.Header(), contentEncoding)
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = twirpruntime.CallError(ctx, s.hooks, twerr)
+	}
+	twirpruntime.CallResponseSent(ctx, s.hooks)
+}
+
+func (s *reflectionServer) ServiceDescriptor() ([]byte, int) {
+	return twirpFileDescriptor0, 0
+}
+
+func (s *reflectionServer) ProtocGenTwirpVersion() string {
+	return "v8.3.0"
+}
+
+// AppliesMuxServerOptions marks servers that apply the hooks and interceptors shared by a twirp.Mux.
+func (s *reflectionServer) AppliesMuxServerOptions() {}
+
+// PathPrefix returns the base service path, in the form: "/<prefix>/<package>.<Service>/"
+// that is everything in a Twirp route except for the <Method>. This can be used for routing,
+// for example to identify the requests that are targeted to this service in a mux.
+func (s *reflectionServer) PathPrefix() string {
+	return twirpruntime.BaseServicePath(s.pathPrefix, "twirp.reflection.v1", "Reflection")
+}
+
+// =====
+// Utils
+// =====
+
+// HTTPClient is the interface used by generated clients to send HTTP requests.
+// It is fulfilled by *(net/http).Client, which is sufficient for most users.
+// Users can provide their own implementation for special retry policies.
+//
+// HTTPClient implementations should not follow redirects. Redirects are
+// automatically disabled if *(net/http).Client is passed to client
+// constructors. See twirpruntime.WithoutRedirects for more details.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// TwirpServer is the interface generated server structs will support: they're
+// HTTP handlers with additional methods for accessing metadata about the
+// service. Those accessors are a low-level API for building reflection tools.
+// Most people can think of TwirpServers as just http.Handlers.
+type TwirpServer interface {
+	http.Handler
+
+	// ServiceDescriptor returns gzipped bytes describing the .proto file that
+	// this service was generated from. Once unzipped, the bytes can be
+	// unmarshalled as a
+	// google.golang.org/protobuf/types/descriptorpb.FileDescriptorProto.
+	//
+	// The returned integer is the index of this particular service within that
+	// FileDescriptorProto's 'Service' slice of ServiceDescriptorProtos. This is a
+	// low-level field, expected to be used for reflection.
+	ServiceDescriptor() ([]byte, int)
+
+	// ProtocGenTwirpVersion is the semantic version string of the version of
+	// twirp used to generate this file.
+	ProtocGenTwirpVersion() string
+
+	// PathPrefix returns the HTTP URL path prefix for all methods handled by this
+	// service. This can be used with an HTTP mux to route Twirp requests.
+	// The path prefix is in the form: "/<prefix>/<package>.<Service>/"
+	// that is, everything in a Twirp route except for the <Method> at the end.
+	PathPrefix() string
+}
+
+// WriteError writes an HTTP response with a valid Twirp error format (code, msg, meta).
+// Useful outside of the Twirp server (e.g. http middleware), but does not trigger hooks.
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func WriteError(resp http.ResponseWriter, err error) {
+	twirpruntime.WriteError(context.Background(), resp, err, nil)
+}
+
+var twirpFileDescriptor0 = []byte{
+	// 425 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x51, 0x6b, 0xd4, 0x40,
+	0x10, 0x26, 0xb6, 0xb6, 0xde, 0x9c, 0x0f, 0xba, 0xd7, 0x42, 0x48, 0x2b, 0x1e, 0x41, 0xe4, 0x7c,
+	0xd9, 0xd8, 0x13, 0x51, 0x5f, 0x45, 0xec, 0x8b, 0x42, 0x49, 0x8b, 0x0f, 0x22, 0x84, 0x5e, 0x3a,
+	0x49, 0x16, 0x72, 0xd9, 0x75, 0x77, 0x92, 0x5a, 0xfc, 0x77, 0xfe, 0x20, 0x7f, 0x83, 0x64, 0x37,
+	0x4d, 0xcf, 0xba, 0x88, 0xbe, 0x2d, 0xdf, 0x7c, 0xdf, 0xec, 0x7c, 0xdf, 0x0c, 0x3c, 0xd0, 0x58,
+	0xd4, 0x98, 0x93, 0x90, 0x0d, 0x57, 0x5a, 0x92, 0x64, 0x33, 0xba, 0x14, 0x5a, 0xf1, 0x0d, 0xbc,
+	0x3b, 0x8a, 0xe6, 0xa5, 0x94, 0x65, 0x8d, 0x89, 0xa5, 0xac, 0xda, 0x22, 0xb9, 0x40, 0x93, 0x6b,
+	0xa1, 0x48, 0x6a, 0x27, 0x8b, 0xf7, 0x61, 0xf6, 0x41, 0x18, 0x3a, 0x45, 0xdd, 0x89, 0x1c, 0x4d,
+	0x8a, 0x5f, 0x5b, 0x34, 0x14, 0x9f, 0xc0, 0xde, 0xef, 0xb0, 0x51, 0xb2, 0x31, 0xc8, 0x5e, 0xc3,
+	0x3d, 0x33, 0x60, 0x61, 0x30, 0xdf, 0x5a, 0x4c, 0x97, 0x87, 0xdc, 0xf3, 0x31, 0x1f, 0x84, 0xe9,
+	0xc8, 0x8e, 0x7f, 0x04, 0xb0, 0x3b, 0xa0, 0x8c, 0xc1, 0x76, 0x73, 0xbe, 0xc6, 0x30, 0x98, 0x07,
+	0x8b, 0x49, 0x6a, 0xdf, 0xec, 0x31, 0x4c, 0xd5, 0x39, 0x55, 0x99, 0xd2, 0x58, 0x88, 0x6f, 0xe1,
+	0x1d, 0x5b, 0x82, 0x1e, 0x3a, 0xb1, 0x48, 0x2f, 0x2a, 0x44, 0x8d, 0xe1, 0x96, 0x13, 0xf5, 0x6f,
+	0xf6, 0x0a, 0x42, 0x6b, 0x23, 0xcf, 0x4a, 0x6c, 0x32, 0x3b, 0x48, 0xd6, 0xa1, 0x36, 0x42, 0x36,
+	0xe1, 0xb6, 0xe5, 0xed, 0xbb, 0xfa, 0x31, 0x36, 0x67, 0x7d, 0xf5, 0x93, 0x2b, 0xb2, 0x97, 0xb0,
+	0xbb, 0x46, 0xaa, 0xe4, 0x85, 0x09, 0xef, 0x5a, 0x1b, 0x07, 0x5e, 0x1b, 0x1f, 0x2d, 0x27, 0xbd,
+	0xe6, 0xc6, 0x5f, 0x60, 0xc7, 0x41, 0x5e, 0x0b, 0x8f, 0x00, 0x44, 0xa3, 0x5a, 0xca, 0xe8, 0x4a,
+	0xe1, 0xe0, 0x60, 0x62, 0x91, 0xb3, 0x2b, 0x65, 0x1d, 0xca, 0x96, 0xc6, 0xba, 0xf3, 0x01, 0x0e,
+	0xea, 0x09, 0xf1, 0x1b, 0x38, 0x38, 0x46, 0x7a, 0x2f, 0x6a, 0x7c, 0x37, 0xae, 0xe9, 0x14, 0x69,
+	0xd8, 0x09, 0x8b, 0x6e, 0x65, 0x3f, 0xd9, 0x48, 0x57, 0xc3, 0xa1, 0x5f, 0x3a, 0xec, 0x2d, 0x85,
+	0x59, 0x1f, 0x58, 0x76, 0xb3, 0xff, 0xcc, 0x20, 0xd9, 0xe9, 0xa7, 0xcb, 0x98, 0xbb, 0x33, 0xe1,
+	0xd7, 0x67, 0xc2, 0xff, 0x6c, 0xf4, 0xb0, 0xb8, 0x0d, 0x2d, 0x7f, 0x06, 0x00, 0xe9, 0x18, 0x17,
+	0xcb, 0xe1, 0xfe, 0xe6, 0xc9, 0xb0, 0x85, 0x37, 0x51, 0xcf, 0xb1, 0x45, 0xcf, 0xfe, 0x81, 0x39,
+	0xf8, 0xf8, 0x0e, 0x7b, 0x3e, 0x9f, 0xec, 0xb9, 0xb7, 0xc5, 0x5f, 0xd2, 0x8c, 0x8e, 0xfe, 0x43,
+	0xe1, 0x3e, 0x7f, 0xfb, 0xf4, 0xf3, 0x93, 0x52, 0x50, 0xd5, 0xae, 0x78, 0x2e, 0xd7, 0x09, 0x5d,
+	0x0a, 0xca, 0x2b, 0xea, 0x12, 0xdb, 0x27, 0xb9, 0xe9, 0xb3, 0xda, 0xb1, 0x39, 0xbe, 0xf8, 0x35,
+	0x00, 0x65, 0x9b, 0xb3, 0xc0, 0xa5, 0x03, 0x00, 0x00,
+}
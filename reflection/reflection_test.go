@@ -0,0 +1,107 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package reflection_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/internal/twirptest"
+	"github.com/twitchtv/twirp/internal/twirptest/multiple"
+	"github.com/twitchtv/twirp/reflection"
+)
+
+type svc2Impl struct{}
+
+func (svc2Impl) Send(ctx context.Context, in *multiple.Msg2) (*multiple.Msg2, error) { return in, nil }
+func (svc2Impl) SamePackageProtoImport(ctx context.Context, in *multiple.Msg1) (*multiple.Msg1, error) {
+	return in, nil
+}
+
+func TestReflection(t *testing.T) {
+	haberdasher := twirptest.NewHaberdasherServer(twirptest.NoopHatmaker())
+	svc := multiple.NewSvc2Server(svc2Impl{})
+	reflectionService, err := reflection.NewService(svc, haberdasher)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mux, err := twirp.NewMux([]twirp.Server{haberdasher, svc, reflection.NewReflectionServer(reflectionService)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := httptest.NewServer(mux)
+	defer s.Close()
+	client := reflection.NewReflectionJSONClient(s.URL, http.DefaultClient)
+	ctx := context.Background()
+
+	t.Run("ListServices", func(t *testing.T) {
+		resp, err := client.ListServices(ctx, &reflection.ListServicesRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Services) != 2 {
+			t.Fatalf("expected 2 services, have: %v", resp.Services)
+		}
+		hs := resp.Services[0]
+		if hs.Name != "twirp.internal.twirptest.Haberdasher" ||
+			hs.PathPrefix != "/twirp/twirp.internal.twirptest.Haberdasher/" ||
+			hs.File != "service.proto" {
+			t.Errorf("unexpected service: %v", hs)
+		}
+		if len(hs.Methods) != 1 || hs.Methods[0].Name != "MakeHat" ||
+			hs.Methods[0].InputType != "twirp.internal.twirptest.Size" ||
+			hs.Methods[0].OutputType != "twirp.internal.twirptest.Hat" {
+			t.Errorf("unexpected methods: %v", hs.Methods)
+		}
+		if have, want := resp.Services[1].Name, "twirp.internal.twirptest.multiple.Svc2"; have != want {
+			t.Errorf("unexpected service name, have: %q, want: %q", have, want)
+		}
+	})
+
+	t.Run("GetFileDescriptorSet", func(t *testing.T) {
+		resp, err := client.GetFileDescriptorSet(ctx, &reflection.GetFileDescriptorSetRequest{
+			Services: []string{"twirp.internal.twirptest.multiple.Svc2"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var names []string
+		for _, fd := range resp.FileDescriptorSet.File {
+			names = append(names, fd.GetName())
+		}
+		// dependencies first
+		if len(names) != 2 || names[0] != "multiple1.proto" || names[1] != "multiple2.proto" {
+			t.Fatalf("unexpected files: %v", names)
+		}
+		if have, want := resp.FileDescriptorSet.File[0].GetMessageType()[0].GetName(), "Msg1"; have != want {
+			t.Errorf("unexpected message in dependency, have: %q, want: %q", have, want)
+		}
+
+		resp, err = client.GetFileDescriptorSet(ctx, &reflection.GetFileDescriptorSetRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if have := len(resp.FileDescriptorSet.File); have != 3 {
+			t.Errorf("expected 3 files for all the services, have: %d", have)
+		}
+
+		_, err = client.GetFileDescriptorSet(ctx, &reflection.GetFileDescriptorSetRequest{Services: []string{"unknown.Service"}})
+		if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.NotFound {
+			t.Errorf("expected not_found error, have: %v", err)
+		}
+	})
+}
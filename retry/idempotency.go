@@ -0,0 +1,92 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/descriptors"
+	"github.com/twitchtv/twirp/internal/gen/stringutils"
+)
+
+// idempotentMethods caches isIdempotent results by "<package>.<Service>/<Method>".
+var idempotentMethods sync.Map
+
+// isIdempotent returns true if the method called in ctx is marked with
+// idempotency_level = IDEMPOTENT or NO_SIDE_EFFECTS. The method descriptor is set
+// in the context by generated clients. Clients generated by older versions only
+// set the method names, which are CamelCased; the method is then looked up in the
+// global protobuf registry. Unknown methods are not idempotent.
+func isIdempotent(ctx context.Context) bool {
+	if md, ok := descriptors.MethodDescriptorFromContext(ctx); ok {
+		return hasIdempotencyLevel(md)
+	}
+
+	pkg, _ := twirp.PackageName(ctx)
+	service, _ := twirp.ServiceName(ctx)
+	method, ok := twirp.MethodName(ctx)
+	if !ok {
+		return false
+	}
+	key := pkg + "." + service + "/" + method
+	if idempotent, ok := idempotentMethods.Load(key); ok {
+		return idempotent.(bool)
+	}
+
+	idempotent := false
+	if md := findMethod(pkg, service, method); md != nil {
+		idempotent = hasIdempotencyLevel(md)
+	}
+	idempotentMethods.Store(key, idempotent)
+	return idempotent
+}
+
+func hasIdempotencyLevel(md protoreflect.MethodDescriptor) bool {
+	opts, _ := md.Options().(*descriptorpb.MethodOptions)
+	switch opts.GetIdempotencyLevel() {
+	case descriptorpb.MethodOptions_IDEMPOTENT, descriptorpb.MethodOptions_NO_SIDE_EFFECTS:
+		return true
+	}
+	return false
+}
+
+// findMethod finds a method descriptor by package, service and method names,
+// which may be CamelCased versions of the names in the .proto file.
+func findMethod(pkg, service, method string) protoreflect.MethodDescriptor {
+	var found protoreflect.MethodDescriptor
+	protoregistry.GlobalFiles.RangeFilesByPackage(protoreflect.FullName(pkg), func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			sd := services.Get(i)
+			if stringutils.CamelCase(string(sd.Name())) != service {
+				continue
+			}
+			methods := sd.Methods()
+			for j := 0; j < methods.Len(); j++ {
+				if md := methods.Get(j); stringutils.CamelCase(string(md.Name())) == method {
+					found = md
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package retry provides a client interceptor that retries failed Twirp
+// requests with exponential backoff and jitter.
+//
+// Only errors with retryable codes are retried (twirp.Unavailable by default),
+// only methods marked as idempotent in the .proto file are retried unless
+// WithIdempotentOnly(false) is used, and requests are never retried past the
+// deadline of the context. Servers can ask clients to wait before retrying with
+// WithRetryAfter.
+//
+// Usage example:
+//
+//	client := haberdasher.NewHaberdasherProtobufClient(url, http.DefaultClient,
+//		twirp.WithClientInterceptors(retry.NewInterceptor(
+//			retry.WithMaxAttempts(4),
+//			retry.WithRetryableCodes(twirp.Unavailable, twirp.ResourceExhausted),
+//		)),
+//	)
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/twitchtv/twirp"
+)
+
+// RetryAfterMetaKey is the error metadata key used by servers to tell clients
+// how long to wait before retrying. See WithRetryAfter.
+const RetryAfterMetaKey = "retry_after"
+
+// Default values of the interceptor options.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+	DefaultJitter         = 0.2
+)
+
+// Option configures the retry interceptor.
+type Option func(*config)
+
+type config struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         float64
+	retryableCodes map[twirp.ErrorCode]bool
+	idempotentOnly bool
+	maxRetryAfter  time.Duration
+}
+
+// WithMaxAttempts sets the maximum number of attempts for each call, including
+// the first one. Values lower than 1 are ignored. Default is DefaultMaxAttempts.
+func WithMaxAttempts(n int) Option {
+	return func(c *config) {
+		if n >= 1 {
+			c.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the delay before the first retry, which is doubled on each
+// retry up to maxBackoff. Defaults are DefaultInitialBackoff and DefaultMaxBackoff.
+func WithBackoff(initial, max time.Duration) Option {
+	return func(c *config) {
+		c.initialBackoff = initial
+		c.maxBackoff = max
+	}
+}
+
+// WithJitter sets the random variation applied to each backoff delay, as a
+// fraction of the delay: with 0.2, delays are randomly 20% shorter or longer.
+// Jitter avoids retries from many clients happening at the same time.
+// The value is clamped to [0, 1]. Default is DefaultJitter.
+func WithJitter(fraction float64) Option {
+	return func(c *config) {
+		switch {
+		case fraction < 0:
+			fraction = 0
+		case fraction > 1:
+			fraction = 1
+		}
+		c.jitter = fraction
+	}
+}
+
+// WithRetryableCodes sets the error codes that are retried, replacing the
+// default (twirp.Unavailable). Errors that are not a twirp.Error are never
+// retried. Be careful with codes like twirp.Internal, returned by servers
+// that may have already applied the side effects of a request.
+func WithRetryableCodes(codes ...twirp.ErrorCode) Option {
+	return func(c *config) {
+		c.retryableCodes = make(map[twirp.ErrorCode]bool, len(codes))
+		for _, code := range codes {
+			c.retryableCodes[code] = true
+		}
+	}
+}
+
+// WithIdempotentOnly makes the interceptor retry only methods that are safe to
+// retry: those marked with the proto option idempotency_level = IDEMPOTENT or
+// NO_SIDE_EFFECTS. The option is read from the method descriptors set in the
+// context by generated clients. Enabled by default; use WithIdempotentOnly(false)
+// to also retry methods that may not be safe to call twice.
+func WithIdempotentOnly(enabled bool) Option {
+	return func(c *config) {
+		c.idempotentOnly = enabled
+	}
+}
+
+// WithMaxRetryAfter limits the delays requested by servers with WithRetryAfter.
+// If a server asks to wait longer, the call fails without retrying.
+// No limit if 0 (default), but retries never go past the context deadline.
+func WithMaxRetryAfter(max time.Duration) Option {
+	return func(c *config) {
+		c.maxRetryAfter = max
+	}
+}
+
+// NewInterceptor returns a client interceptor that retries failed calls.
+// Install it with twirp.WithClientInterceptors.
+//
+// The attempt number of each request is available in the context with Attempt,
+// for example in twirp.ClientHooks, which are called on every attempt.
+func NewInterceptor(opts ...Option) twirp.Interceptor {
+	c := &config{
+		maxAttempts:    DefaultMaxAttempts,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		jitter:         DefaultJitter,
+		retryableCodes: map[twirp.ErrorCode]bool{twirp.Unavailable: true},
+		idempotentOnly: true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if c.idempotentOnly && !isIdempotent(ctx) {
+				return next(withAttempt(ctx, 1), req)
+			}
+
+			backoff := c.initialBackoff
+			for attempt := 1; ; attempt++ {
+				resp, err := next(withAttempt(ctx, attempt), req)
+				if err == nil || attempt >= c.maxAttempts {
+					return resp, err
+				}
+				var twerr twirp.Error
+				if !errors.As(err, &twerr) || !c.retryableCodes[twerr.Code()] {
+					return resp, err
+				}
+
+				delay := c.withJitter(backoff)
+				if retryAfter, ok := parseRetryAfter(twerr); ok {
+					if c.maxRetryAfter > 0 && retryAfter > c.maxRetryAfter {
+						return resp, err
+					}
+					if retryAfter > delay {
+						delay = retryAfter
+					}
+				}
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+					return resp, err // no time left for another attempt
+				}
+				if !sleep(ctx, delay) {
+					return resp, err
+				}
+
+				backoff *= 2
+				if backoff > c.maxBackoff {
+					backoff = c.maxBackoff
+				}
+			}
+		}
+	}
+}
+
+// withJitter randomly shortens or lengthens the delay by the jitter fraction.
+func (c *config) withJitter(delay time.Duration) time.Duration {
+	if c.jitter == 0 || delay <= 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 + c.jitter*(2*rand.Float64()-1)))
+}
+
+// sleep waits for the delay, and returns false if the context is done first.
+func sleep(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// WithRetryAfter adds a hint to the error, asking clients to wait at least the
+// given delay before retrying. Used by servers, for example when rate limiting:
+//
+//	return nil, retry.WithRetryAfter(twirp.NewError(twirp.ResourceExhausted, "slow down"), 2*time.Second)
+//
+// The hint is sent in the error metadata with the key RetryAfterMetaKey.
+func WithRetryAfter(err twirp.Error, delay time.Duration) twirp.Error {
+	return err.WithMeta(RetryAfterMetaKey, delay.String())
+}
+
+// parseRetryAfter reads the hint added with WithRetryAfter. The value can be a
+// duration (e.g. "1.5s") or a number of seconds, like the HTTP Retry-After header.
+func parseRetryAfter(twerr twirp.Error) (time.Duration, bool) {
+	value := twerr.Meta(RetryAfterMetaKey)
+	if value == "" {
+		return 0, false
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, true
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil && secs >= 0 {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+var attemptKey = new(int)
+
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey, attempt)
+}
+
+// Attempt returns the attempt number of the current request, starting at 1,
+// if the request was made through the retry interceptor. It can be used in
+// twirp.ClientHooks to track retries.
+func Attempt(ctx context.Context) (int, bool) {
+	attempt, ok := ctx.Value(attemptKey).(int)
+	return attempt, ok
+}
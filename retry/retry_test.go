@@ -0,0 +1,252 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package retry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/ctxsetters"
+	"github.com/twitchtv/twirp/internal/twirptest/no_side_effects"
+	"github.com/twitchtv/twirp/retry"
+)
+
+// flakyCatalog fails the first failures calls of each method with err.
+type flakyCatalog struct {
+	mu       sync.Mutex
+	calls    map[string]int
+	failures int
+	err      twirp.Error
+}
+
+func (c *flakyCatalog) call(method string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[method]++
+	if c.calls[method] <= c.failures {
+		return c.err
+	}
+	return nil
+}
+
+func (c *flakyCatalog) numCalls(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+func (c *flakyCatalog) GetItem(ctx context.Context, req *no_side_effects.GetItemReq) (*no_side_effects.Item, error) {
+	if err := c.call("GetItem"); err != nil {
+		return nil, err
+	}
+	return &no_side_effects.Item{Id: req.Id}, nil
+}
+
+func (c *flakyCatalog) UpdateItem(ctx context.Context, req *no_side_effects.Item) (*no_side_effects.Item, error) {
+	if err := c.call("UpdateItem"); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func newClient(t *testing.T, svc *flakyCatalog, opts ...twirp.ClientOption) no_side_effects.Catalog {
+	s := httptest.NewServer(no_side_effects.NewCatalogServer(svc))
+	t.Cleanup(s.Close)
+	return no_side_effects.NewCatalogProtobufClient(s.URL, http.DefaultClient, opts...)
+}
+
+func fastRetries(opts ...retry.Option) twirp.ClientOption {
+	opts = append([]retry.Option{retry.WithBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)
+	return twirp.WithClientInterceptors(retry.NewInterceptor(opts...))
+}
+
+func TestRetry(t *testing.T) {
+	svc := &flakyCatalog{failures: 2, err: twirp.NewError(twirp.Unavailable, "try again")}
+
+	var attempts []int
+	hooks := &twirp.ClientHooks{
+		RequestPrepared: func(ctx context.Context, req *http.Request) (context.Context, error) {
+			attempt, _ := retry.Attempt(ctx)
+			attempts = append(attempts, attempt)
+			return ctx, nil
+		},
+	}
+	client := newClient(t, svc, fastRetries(), twirp.WithClientHooks(hooks))
+
+	if _, err := client.GetItem(context.Background(), &no_side_effects.GetItemReq{Id: "a"}); err != nil {
+		t.Fatalf("GetItem err=%q", err)
+	}
+	if n := svc.numCalls("GetItem"); n != 3 {
+		t.Errorf("unexpected number of calls, have=%d, want=3", n)
+	}
+	if len(attempts) != 3 || attempts[0] != 1 || attempts[1] != 2 || attempts[2] != 3 {
+		t.Errorf("unexpected attempts in hooks %v", attempts)
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	svc := &flakyCatalog{failures: 5, err: twirp.NewError(twirp.Unavailable, "try again")}
+	client := newClient(t, svc, fastRetries(retry.WithMaxAttempts(4)))
+
+	_, err := client.GetItem(context.Background(), &no_side_effects.GetItemReq{Id: "a"})
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.Unavailable {
+		t.Fatalf("expected unavailable error, have %v", err)
+	}
+	if n := svc.numCalls("GetItem"); n != 4 {
+		t.Errorf("unexpected number of calls, have=%d, want=4", n)
+	}
+}
+
+func TestRetryableCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      twirp.ErrorCode
+		opts      []retry.Option
+		wantCalls int
+	}{
+		{"default unavailable", twirp.Unavailable, nil, 2},
+		{"default internal", twirp.Internal, nil, 1},
+		{"default resource_exhausted", twirp.ResourceExhausted, nil, 1},
+		{"configured resource_exhausted", twirp.ResourceExhausted, []retry.Option{retry.WithRetryableCodes(twirp.ResourceExhausted)}, 2},
+		{"configured unavailable", twirp.Unavailable, []retry.Option{retry.WithRetryableCodes(twirp.ResourceExhausted)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &flakyCatalog{failures: 1, err: twirp.NewError(tt.code, "failed")}
+			client := newClient(t, svc, fastRetries(tt.opts...))
+			_, _ = client.GetItem(context.Background(), &no_side_effects.GetItemReq{Id: "a"})
+			if n := svc.numCalls("GetItem"); n != tt.wantCalls {
+				t.Errorf("unexpected number of calls, have=%d, want=%d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryIdempotentOnly(t *testing.T) {
+	svc := &flakyCatalog{failures: 1, err: twirp.NewError(twirp.Unavailable, "try again")}
+	client := newClient(t, svc, fastRetries())
+
+	// GetItem has idempotency_level = NO_SIDE_EFFECTS
+	if _, err := client.GetItem(context.Background(), &no_side_effects.GetItemReq{Id: "a"}); err != nil {
+		t.Fatalf("GetItem err=%q", err)
+	}
+	if n := svc.numCalls("GetItem"); n != 2 {
+		t.Errorf("unexpected number of GetItem calls, have=%d, want=2", n)
+	}
+
+	// UpdateItem has no idempotency_level
+	if _, err := client.UpdateItem(context.Background(), &no_side_effects.Item{Id: "a"}); err == nil {
+		t.Fatal("expected UpdateItem to fail without retries")
+	}
+	if n := svc.numCalls("UpdateItem"); n != 1 {
+		t.Errorf("unexpected number of UpdateItem calls, have=%d, want=1", n)
+	}
+
+	// unless explicitly enabled
+	svc = &flakyCatalog{failures: 1, err: twirp.NewError(twirp.Unavailable, "try again")}
+	client = newClient(t, svc, fastRetries(retry.WithIdempotentOnly(false)))
+	if _, err := client.UpdateItem(context.Background(), &no_side_effects.Item{Id: "a"}); err != nil {
+		t.Fatalf("UpdateItem err=%q", err)
+	}
+	if n := svc.numCalls("UpdateItem"); n != 2 {
+		t.Errorf("unexpected number of UpdateItem calls, have=%d, want=2", n)
+	}
+}
+
+func TestRetryIdempotentOnlyMethodNames(t *testing.T) {
+	// clients generated by older versions only set the method names in the context
+	interceptor := retry.NewInterceptor(retry.WithBackoff(time.Millisecond, 5*time.Millisecond))
+	for method, want := range map[string]int{"GetItem": 2, "UpdateItem": 1} {
+		ctx := ctxsetters.WithPackageName(context.Background(), "")
+		ctx = ctxsetters.WithServiceName(ctx, "Catalog")
+		ctx = ctxsetters.WithMethodName(ctx, method)
+
+		svc := &flakyCatalog{failures: 1, err: twirp.NewError(twirp.Unavailable, "try again")}
+		call := interceptor(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, svc.call(method)
+		})
+		_, _ = call(ctx, nil)
+		if n := svc.numCalls(method); n != want {
+			t.Errorf("unexpected number of %s calls, have=%d, want=%d", method, n, want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for _, retryAfter := range []string{"30ms", "0.03"} {
+		t.Run(retryAfter, func(t *testing.T) {
+			twerr := twirp.NewError(twirp.Unavailable, "slow down").WithMeta(retry.RetryAfterMetaKey, retryAfter)
+			svc := &flakyCatalog{failures: 1, err: twerr}
+			client := newClient(t, svc, fastRetries())
+
+			start := time.Now()
+			if _, err := client.GetItem(context.Background(), &no_side_effects.GetItemReq{Id: "a"}); err != nil {
+				t.Fatalf("GetItem err=%q", err)
+			}
+			if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+				t.Errorf("retried too early, after %s", elapsed)
+			}
+		})
+	}
+
+	t.Run("WithRetryAfter", func(t *testing.T) {
+		twerr := retry.WithRetryAfter(twirp.NewError(twirp.Unavailable, "slow down"), 2*time.Second)
+		if have := twerr.Meta(retry.RetryAfterMetaKey); have != "2s" {
+			t.Errorf("unexpected meta %q", have)
+		}
+	})
+
+	t.Run("over WithMaxRetryAfter", func(t *testing.T) {
+		twerr := retry.WithRetryAfter(twirp.NewError(twirp.Unavailable, "slow down"), time.Hour)
+		svc := &flakyCatalog{failures: 1, err: twerr}
+		client := newClient(t, svc, fastRetries(retry.WithMaxRetryAfter(time.Second)))
+		if _, err := client.GetItem(context.Background(), &no_side_effects.GetItemReq{Id: "a"}); err == nil {
+			t.Fatal("expected GetItem to fail without retries")
+		}
+		if n := svc.numCalls("GetItem"); n != 1 {
+			t.Errorf("unexpected number of calls, have=%d, want=1", n)
+		}
+	})
+}
+
+func TestRetryContextDeadline(t *testing.T) {
+	svc := &flakyCatalog{failures: 5, err: twirp.NewError(twirp.Unavailable, "try again")}
+	client := newClient(t, svc, twirp.WithClientInterceptors(retry.NewInterceptor(
+		retry.WithMaxAttempts(10),
+		retry.WithBackoff(20*time.Millisecond, 20*time.Millisecond),
+		retry.WithJitter(0),
+	)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetItem(ctx, &no_side_effects.GetItemReq{Id: "a"})
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.Unavailable {
+		t.Fatalf("expected the last unavailable error, have %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("retried past the deadline, after %s", elapsed)
+	}
+	if n := svc.numCalls("GetItem"); n < 2 || n > 3 {
+		t.Errorf("unexpected number of calls %d", n)
+	}
+}
@@ -15,6 +15,7 @@ package twirp
 import (
 	"context"
 	"reflect"
+	"time"
 )
 
 // ServerOption is a functional option for extending a Twirp service.
@@ -76,6 +77,147 @@ func WithServerJSONCamelCaseNames(jsonCamelCase bool) ServerOption {
 	}
 }
 
+// WithServerJSONStrict configures JSON deserialization of requests to reject
+// unknown fields, and any data after the JSON object, with a twirp.Malformed
+// error. By default unknown fields are discarded, which allows clients to use
+// a newer version of the schema than the server.
+func WithServerJSONStrict(strict bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.setOpt("jsonStrict", strict)
+	}
+}
+
+// WithServerMaxRequestBytes limits the size of request bodies accepted by
+// the server. Requests with a larger body are rejected with a
+// twirp.ResourceExhausted error before the handler is called, and the Error
+// hook is triggered as with any other error. A value of 0 or less (default)
+// means no limit. The limit can be overridden per method with
+// WithServerMethodMaxRequestBytes.
+func WithServerMaxRequestBytes(maxBytes int64) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.setOpt("maxRequestBytes", maxBytes)
+	}
+}
+
+// WithServerMethodMaxRequestBytes overrides the request body size limit for
+// a single method, identified by the method name as returned by
+// twirp.MethodName(ctx) (e.g. "MakeHat"). This is useful to allow larger
+// uploads on a few methods while keeping a strict default limit with
+// WithServerMaxRequestBytes. A value of 0 or less means no limit for that method.
+func WithServerMethodMaxRequestBytes(method string, maxBytes int64) ServerOption {
+	return func(opts *ServerOptions) {
+		var limits map[string]int64
+		_ = opts.ReadOpt("methodMaxRequestBytes", &limits)
+		copied := make(map[string]int64, len(limits)+1) // copy to avoid sharing maps between servers
+		for k, v := range limits {
+			copied[k] = v
+		}
+		copied[method] = maxBytes
+		opts.setOpt("methodMaxRequestBytes", copied)
+	}
+}
+
+// WithServerCompression enables compression of responses, and decompression
+// of requests, with the given encodings (e.g. "gzip"), in order of preference.
+// Responses are compressed with the first encoding that is also accepted by the
+// client in the Accept-Encoding header, only if they are at least
+// DefaultCompressionMinBytes long (see WithServerCompressionMinBytes).
+// Requests with a Content-Encoding that is not enabled are rejected with a
+// twirp.Malformed error.
+//
+// The encodings must be registered with twirp.RegisterCompressor ("gzip" is
+// registered by default), otherwise this function panics.
+func WithServerCompression(encodings ...string) ServerOption {
+	compressors := mustLookupCompressors("WithServerCompression", encodings)
+	return func(opts *ServerOptions) {
+		opts.setOpt("compressors", compressors)
+	}
+}
+
+// WithServerCompressionMinBytes sets the minimum size of a response body to be
+// compressed, when compression is enabled with WithServerCompression.
+// Smaller responses are sent uncompressed. If not specified,
+// DefaultCompressionMinBytes is used.
+func WithServerCompressionMinBytes(minBytes int) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.setOpt("compressionMinBytes", minBytes)
+	}
+}
+
+// WithServerCodecs enables additional content types on the server, in
+// addition to the built-in "application/protobuf" and "application/json".
+// Requests are decoded, and responses encoded, with the Codec that matches the
+// Content-Type of the request. A Codec with the ContentType of a built-in
+// encoding replaces it, for example to use a faster Protobuf marshaler.
+// Requests with any other Content-Type are rejected with a twirp.BadRoute
+// error. The option can be used multiple times to add more codecs.
+func WithServerCodecs(codecs ...Codec) ServerOption {
+	return func(opts *ServerOptions) {
+		var current []Codec
+		_ = opts.ReadOpt("codecs", &current)
+		copied := make([]Codec, 0, len(current)+len(codecs)) // copy to avoid sharing slices between servers
+		copied = append(copied, current...)
+		copied = append(copied, codecs...)
+		opts.setOpt("codecs", copied)
+	}
+}
+
+// WithServerRequestTimeouts enables (default) or disables applying the
+// timeouts sent by clients in the "Twirp-Timeout" header to the request
+// context. Twirp clients send the header when the request context has a
+// deadline, so servers can stop working on requests that the caller already
+// gave up on. If the timeout expires before the handler returns, the response
+// is a twirp.DeadlineExceeded error, even if the handler ignored the context
+// and returned a response, unless it returned a twirp.Error.
+func WithServerRequestTimeouts(enabled bool) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.setOpt("requestTimeouts", enabled)
+	}
+}
+
+// WithServerMaxRequestTimeout limits the timeouts sent by clients in the
+// "Twirp-Timeout" header: larger timeouts are reduced to maxTimeout. Requests
+// without the header are not affected. A value of 0 or less (default) means
+// no limit.
+func WithServerMaxRequestTimeout(maxTimeout time.Duration) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.setOpt("maxRequestTimeout", maxTimeout)
+	}
+}
+
+// WithServerPanicHandler sets a function that is called when a method panics,
+// with the recovered value and the stack trace of the panic. It returns the
+// error sent to the client, or nil to send the default twirp.Internal error.
+// The Error hook is called with the returned error, as with any other error.
+//
+// By default, the panic is re-raised after the error response is sent, so it
+// can be handled by middleware (or crash the server). With a panic handler,
+// the panic is not re-raised: the handler can log it, and map it to a custom
+// error.
+func WithServerPanicHandler(handler func(ctx context.Context, recovered interface{}, stack []byte) Error) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.setOpt("panicHandler", handler)
+	}
+}
+
+// WithServerErrorTransformer sets a function that maps errors before they are
+// sent to the client and passed to the Error hook. It is called with every
+// error of the server: errors returned by methods, which may not be
+// twirp.Error values (e.g. sql.ErrNoRows), and errors from Twirp itself, like
+// bad_route or malformed errors. It returns the error to send, or nil to keep
+// the default behavior, where non-twirp errors are sent as twirp.Internal
+// errors with the original error message.
+//
+// This can be used to map sentinel errors to error codes, or to hide the
+// messages of internal errors in production. The original error is kept as
+// the cause of the returned error (unless it already has one), so hooks can
+// still log it with errors.Unwrap.
+func WithServerErrorTransformer(transformer func(ctx context.Context, err error) Error) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.setOpt("errorTransformer", transformer)
+	}
+}
+
 // ServerHooks is a container for callbacks that can instrument a
 // Twirp-generated server. These callbacks all accept a context and return a
 // context. They can use this to add to the request context as it threads
@@ -156,3 +156,58 @@ func TestWithJSONSkipDefaults(t *testing.T) {
 		t.Errorf("opts.JSONSkipDefaults expected to be false, but it is true")
 	}
 }
+
+func TestWithServerMethodMaxRequestBytes(t *testing.T) {
+	opts := &ServerOptions{}
+	WithServerMaxRequestBytes(1024)(opts)
+	WithServerMethodMaxRequestBytes("Upload", 1<<20)(opts)
+
+	var maxRequestBytes int64
+	if ok := opts.ReadOpt("maxRequestBytes", &maxRequestBytes); !ok || maxRequestBytes != 1024 {
+		t.Errorf("option 'maxRequestBytes' expected to be 1024, ok: %v, val: %v", ok, maxRequestBytes)
+	}
+
+	var limits map[string]int64
+	opts.ReadOpt("methodMaxRequestBytes", &limits)
+	shared := limits // previously read values should not be modified by new options
+
+	WithServerMethodMaxRequestBytes("Download", 0)(opts)
+	opts.ReadOpt("methodMaxRequestBytes", &limits)
+	want := map[string]int64{"Upload": 1 << 20, "Download": 0}
+	if !reflect.DeepEqual(limits, want) {
+		t.Errorf("option 'methodMaxRequestBytes' has unexpected value, have: %v, want: %v", limits, want)
+	}
+	if _, ok := shared["Download"]; ok {
+		t.Errorf("option 'methodMaxRequestBytes' modified a previously read map")
+	}
+}
+
+// testCodec is a Codec that only has a content type.
+type testCodec string
+
+func (c testCodec) ContentType() string                        { return string(c) }
+func (testCodec) Marshal(msg interface{}) ([]byte, error)      { return nil, nil }
+func (testCodec) Unmarshal(data []byte, msg interface{}) error { return nil }
+
+func TestWithServerCodecs(t *testing.T) {
+	opts := &ServerOptions{}
+	WithServerCodecs(testCodec("application/x-protobuf"))(opts)
+
+	var codecs []Codec
+	opts.ReadOpt("codecs", &codecs)
+	shared := codecs // previously read values should not be modified by new options
+
+	WithServerCodecs(testCodec("Application/X-MsgPack; charset=binary"), testCodec("application/json"))(opts)
+	opts.ReadOpt("codecs", &codecs)
+	want := []Codec{
+		testCodec("application/x-protobuf"),
+		testCodec("Application/X-MsgPack; charset=binary"),
+		testCodec("application/json"),
+	}
+	if !reflect.DeepEqual(codecs, want) {
+		t.Errorf("option 'codecs' has unexpected value, have: %v, want: %v", codecs, want)
+	}
+	if &shared[0] == &codecs[0] {
+		t.Errorf("option 'codecs' modified a previously read slice")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/twitchtv/twirp"
+)
+
+// limitBodyReader returns a reader that fails with a *bodyTooLargeError if more
+// than maxBytes are read from the body. If the contentLength is already known to
+// be larger than maxBytes, the reader fails without reading anything.
+// If maxBytes is 0 or less there is no limit and the body is returned unchanged.
+func limitBodyReader(body io.Reader, contentLength, maxBytes int64) io.Reader {
+	if maxBytes <= 0 {
+		return body
+	}
+	if contentLength > maxBytes {
+		return &maxBytesReader{err: &bodyTooLargeError{maxBytes: maxBytes}}
+	}
+	return &maxBytesReader{r: body, remaining: maxBytes, maxBytes: maxBytes}
+}
+
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+	maxBytes  int64
+	err       error
+}
+
+func (l *maxBytesReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// Read one extra byte to tell apart bodies that are exactly maxBytes long.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) <= l.remaining {
+		l.remaining -= int64(n)
+		return n, err
+	}
+	n = int(l.remaining)
+	l.remaining = 0
+	l.err = &bodyTooLargeError{maxBytes: l.maxBytes}
+	return n, l.err
+}
+
+// bodyTooLargeError is returned by limitBodyReader when the body is over the limit.
+type bodyTooLargeError struct {
+	maxBytes int64
+}
+
+func (e *bodyTooLargeError) Error() string {
+	return fmt.Sprintf("body exceeds the maximum size of %d bytes", e.maxBytes)
+}
+
+// BodyTooLargeTwirpError returns a ResourceExhausted error if err was caused by
+// reading a body over the size limit (see limitBodyReader), or nil otherwise.
+// The msgPrefix identifies the body, e.g. "request " or "response ".
+func BodyTooLargeTwirpError(err error, msgPrefix string) twirp.Error {
+	var tooLarge *bodyTooLargeError
+	if !errors.As(err, &tooLarge) {
+		return nil
+	}
+	twerr := twirp.NewError(twirp.ResourceExhausted, msgPrefix+tooLarge.Error())
+	return twirp.WrapError(twerr, err)
+}
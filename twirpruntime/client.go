@@ -0,0 +1,259 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/ctxsetters"
+)
+
+// HTTPClient is the interface used by generated clients to send HTTP requests.
+// Generated packages declare the same interface, so both are interchangeable.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// SanitizeBaseURL parses the baseURL, and adds the "http" scheme if needed.
+// If the URL is unparsable, the baseURL is returned unchanged.
+func SanitizeBaseURL(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL // invalid URL will fail later when making requests
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+	return u.String()
+}
+
+// getCustomHTTPReqHeaders retrieves a copy of any headers that are set in
+// a context through the twirp.WithHTTPRequestHeaders function.
+// If there are no headers set, or if they have the wrong type, nil is returned.
+func getCustomHTTPReqHeaders(ctx context.Context) http.Header {
+	header, ok := twirp.HTTPRequestHeaders(ctx)
+	if !ok || header == nil {
+		return nil
+	}
+	copied := make(http.Header)
+	for k, vv := range header {
+		if vv == nil {
+			copied[k] = nil
+			continue
+		}
+		copied[k] = make([]string, len(vv))
+		copy(copied[k], vv)
+	}
+	return copied
+}
+
+// newRequest makes an http.Request from a client, adding common headers.
+// The body is compressed if needed, see Compression.compressRequestBody.
+// If httpGet is true, the request is a GET request with the body in the query string
+// (only for methods without side effects, see queryFromRequestBody).
+func newRequest(ctx context.Context, url string, reqBody []byte, contentType string, compression Compression, httpGet bool) (*http.Request, error) {
+	method, contentEncoding := "POST", ""
+	var body io.Reader
+	if httpGet {
+		method = "GET"
+		url += "?" + queryFromRequestBody(reqBody, contentType)
+	} else {
+		var err error
+		reqBody, contentEncoding, err = compression.compressRequestBody(reqBody)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(reqBody)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if customHeader := getCustomHTTPReqHeaders(ctx); customHeader != nil {
+		req.Header = customHeader
+	}
+	req.Header.Set("Accept", contentType)
+	if !httpGet {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Twirp-Version", twirpVersion)
+	req.Header.Set(errorDetailsHeader, "true")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Twirp-Timeout", formatTwirpTimeout(time.Until(deadline)))
+	}
+	if len(compression.compressors) > 0 {
+		req.Header.Set("Accept-Encoding", compression.acceptEncoding())
+	}
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	return req, nil
+}
+
+// hasQueryEncoding returns true if request messages with the content type can be
+// encoded in the query string of HTTP GET requests, see queryFromRequestBody.
+func hasQueryEncoding(contentType string) bool {
+	switch mediaType(contentType) {
+	case "application/protobuf", "application/json":
+		return true
+	default:
+		return false
+	}
+}
+
+// queryFromRequestBody encodes a request message in the query string of an HTTP GET request:
+// "encoding=json&message=<JSON>" or "encoding=protobuf&message=<base64url-encoded protobuf>".
+func queryFromRequestBody(reqBody []byte, contentType string) string {
+	if mediaType(contentType) == "application/protobuf" {
+		return "encoding=protobuf&message=" + base64.RawURLEncoding.EncodeToString(reqBody)
+	}
+	return "encoding=json&message=" + url.QueryEscape(string(reqBody))
+}
+
+// formatTwirpTimeout formats the value of the Twirp-Timeout header, which is the
+// remaining time in milliseconds until the deadline of the client request.
+func formatTwirpTimeout(timeout time.Duration) string {
+	ms := int64((timeout + time.Millisecond - 1) / time.Millisecond) // round up
+	if ms < 1 {
+		ms = 1
+	}
+	return strconv.FormatInt(ms, 10)
+}
+
+// WithoutRedirects makes sure that the POST request can not be redirected.
+// The standard library will, by default, redirect requests (including POSTs) if it gets a 302 or
+// 303 response, and also 301s in go1.8. It redirects by making a second request, changing the
+// method to GET and removing the body. This produces very confusing error messages, so instead we
+// set a redirect policy that always errors. This stops Go from executing the redirect.
+//
+// We have to be a little careful in case the user-provided http.Client has its own CheckRedirect
+// policy - if so, we'll run through that policy first.
+//
+// Because this requires modifying the http.Client, we make a new copy of the client and return it.
+func WithoutRedirects(in *http.Client) *http.Client {
+	copy := *in
+	copy.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if in.CheckRedirect != nil {
+			// Run the input's redirect if it exists, in case it has side effects, but ignore any error it
+			// returns, since we want to use ErrUseLastResponse.
+			err := in.CheckRedirect(req, via)
+			_ = err // Silly, but this makes sure generated code passes errcheck -blank, which some people use.
+		}
+		return http.ErrUseLastResponse
+	}
+	return &copy
+}
+
+// DoRequest makes a request to the remote Twirp service, serialized with the codec.
+// HTTP GET requests are only made if the codec has the content type of a built-in
+// encoding, because the query string can not encode others.
+func DoRequest(ctx context.Context, client HTTPClient, hooks *twirp.ClientHooks, url string, in, out proto.Message, codec twirp.Codec, maxResponseBytes int64, compression Compression, httpGet bool) (context.Context, error) {
+	reqBodyBytes, err := codec.Marshal(in)
+	if err != nil {
+		return ctx, WrapInternal(err, "failed to marshal request")
+	}
+	contentType := codec.ContentType()
+	httpGet = httpGet && hasQueryEncoding(contentType)
+	ctx, respBodyBytes, err := doRequest(ctx, client, hooks, url, reqBodyBytes, contentType, maxResponseBytes, compression, httpGet)
+	if err != nil {
+		return ctx, err
+	}
+	if err = codec.Unmarshal(respBodyBytes, out); err != nil {
+		return ctx, WrapInternal(err, "failed to unmarshal response")
+	}
+	return ctx, nil
+}
+
+// doRequest sends a serialized request message, and returns the body of a successful response.
+func doRequest(ctx context.Context, client HTTPClient, hooks *twirp.ClientHooks, url string, reqBodyBytes []byte, contentType string, maxResponseBytes int64, compression Compression, httpGet bool) (context.Context, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return ctx, nil, WrapInternal(err, "aborted because context was done")
+	}
+
+	req, err := newRequest(ctx, url, reqBodyBytes, contentType, compression, httpGet)
+	if err != nil {
+		return ctx, nil, WrapInternal(err, "could not build request")
+	}
+	ctx, err = CallClientRequestPrepared(ctx, hooks, req)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	req = req.WithContext(ctx)
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx, nil, WrapInternal(err, "failed to do request")
+	}
+	ctx = ctxsetters.WithStatusCode(ctx, resp.StatusCode) // for the hooks; not set if the request failed
+	defer func() { _ = resp.Body.Close() }()
+
+	if err = compression.decompressResponseBody(resp); err != nil {
+		return ctx, nil, WrapInternal(err, "failed to decompress response body")
+	}
+
+	if err = ctx.Err(); err != nil {
+		return ctx, nil, WrapInternal(err, "aborted because context was done")
+	}
+
+	if resp.StatusCode != 200 {
+		return ctx, nil, errorFromResponse(resp, maxResponseBytes)
+	}
+
+	respBodyBytes, err := io.ReadAll(limitBodyReader(resp.Body, resp.ContentLength, maxResponseBytes))
+	if err != nil {
+		if twerr := BodyTooLargeTwirpError(err, "response "); twerr != nil {
+			return ctx, nil, twerr
+		}
+		return ctx, nil, WrapInternal(err, "failed to read response body")
+	}
+	if err = ctx.Err(); err != nil {
+		return ctx, nil, WrapInternal(err, "aborted because context was done")
+	}
+	return ctx, respBodyBytes, nil
+}
+
+// CallClientResponseReceived calls twirp.ClientHooks.ResponseReceived if the hook is available.
+func CallClientResponseReceived(ctx context.Context, h *twirp.ClientHooks) {
+	if h == nil || h.ResponseReceived == nil {
+		return
+	}
+	h.ResponseReceived(ctx)
+}
+
+// CallClientRequestPrepared calls twirp.ClientHooks.RequestPrepared if the hook is available.
+func CallClientRequestPrepared(ctx context.Context, h *twirp.ClientHooks, req *http.Request) (context.Context, error) {
+	if h == nil || h.RequestPrepared == nil {
+		return ctx, nil
+	}
+	return h.RequestPrepared(ctx, req)
+}
+
+// CallClientError calls twirp.ClientHooks.Error if the hook is available.
+func CallClientError(ctx context.Context, h *twirp.ClientHooks, err twirp.Error) {
+	if h == nil || h.Error == nil {
+		return
+	}
+	h.Error(ctx, err)
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+)
+
+// ProtobufCodec is the built-in codec for "application/protobuf".
+var ProtobufCodec twirp.Codec = protobufCodec{}
+
+// JSONCodec is the built-in codec for "application/json" used by clients without
+// JSON options, which sends fields with their original proto names and discards
+// unknown fields in responses.
+var JSONCodec = NewJSONCodec(protojson.MarshalOptions{UseProtoNames: true}, protojson.UnmarshalOptions{DiscardUnknown: true})
+
+// NewJSONCodec returns a codec for "application/json" that serializes messages with
+// the marshaler and unmarshaler.
+func NewJSONCodec(marshaler protojson.MarshalOptions, unmarshaler protojson.UnmarshalOptions) twirp.Codec {
+	return jsonCodec{marshaler: marshaler, unmarshaler: unmarshaler}
+}
+
+// ReadJSONCodec returns the JSON codec configured by the JSON options of a client,
+// see twirp.WithClientJSONCamelCaseNames, twirp.WithClientJSONEmitDefaults,
+// twirp.WithClientJSONEnumNumbers and twirp.WithClientJSONStrict.
+func ReadJSONCodec(opts *twirp.ClientOptions) twirp.Codec {
+	var camelCase, emitDefaults, enumNumbers, strict bool
+	_ = opts.ReadOpt("jsonCamelCase", &camelCase)
+	_ = opts.ReadOpt("jsonEmitDefaults", &emitDefaults)
+	_ = opts.ReadOpt("jsonEnumNumbers", &enumNumbers)
+	_ = opts.ReadOpt("jsonStrict", &strict)
+	return NewJSONCodec(
+		protojson.MarshalOptions{UseProtoNames: !camelCase, EmitUnpopulated: emitDefaults, UseEnumNumbers: enumNumbers},
+		protojson.UnmarshalOptions{DiscardUnknown: !strict},
+	)
+}
+
+// ReadServerCodecs returns the codecs of a server by content type: the built-in codecs
+// for "application/protobuf" and "application/json", configured by the JSON options of
+// the server (see twirp.WithServerJSONSkipDefaults, twirp.WithServerJSONCamelCaseNames
+// and twirp.WithServerJSONStrict), and the custom codecs from twirp.WithServerCodecs,
+// which replace the built-in ones (and earlier custom ones) for the same media type.
+func ReadServerCodecs(opts *twirp.ServerOptions) map[string]twirp.Codec {
+	var skipDefaults, camelCase, strict bool
+	_ = opts.ReadOpt("jsonSkipDefaults", &skipDefaults)
+	_ = opts.ReadOpt("jsonCamelCase", &camelCase)
+	_ = opts.ReadOpt("jsonStrict", &strict)
+	var custom []twirp.Codec
+	_ = opts.ReadOpt("codecs", &custom)
+
+	codecs := make(map[string]twirp.Codec, 2+len(custom))
+	codecs["application/protobuf"] = ProtobufCodec
+	codecs["application/json"] = serverJSONCodec{
+		jsonCodec: jsonCodec{
+			marshaler:   protojson.MarshalOptions{UseProtoNames: !camelCase, EmitUnpopulated: !skipDefaults},
+			unmarshaler: protojson.UnmarshalOptions{DiscardUnknown: !strict},
+		},
+		strict: strict,
+	}
+	for _, codec := range custom {
+		codecs[mediaType(codec.ContentType())] = codec
+	}
+	return codecs
+}
+
+// RequestCodec returns the codec from ReadServerCodecs for the media type of the request
+// Content-Type header, or false if the server has no codec for it.
+func RequestCodec(codecs map[string]twirp.Codec, req *http.Request) (twirp.Codec, bool) {
+	codec, ok := codecs[mediaType(req.Header.Get("Content-Type"))]
+	return codec, ok
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string { return "application/protobuf" }
+
+func (protobufCodec) Marshal(msg interface{}) ([]byte, error) {
+	m, err := protoMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(m)
+}
+
+func (protobufCodec) Unmarshal(data []byte, msg interface{}) error {
+	m, err := protoMessage(msg)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m)
+}
+
+type jsonCodec struct {
+	marshaler   protojson.MarshalOptions
+	unmarshaler protojson.UnmarshalOptions
+}
+
+func (jsonCodec) ContentType() string { return "application/json" }
+
+func (c jsonCodec) Marshal(msg interface{}) ([]byte, error) {
+	m, err := protoMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return c.marshaler.Marshal(m)
+}
+
+func (c jsonCodec) Unmarshal(data []byte, msg interface{}) error {
+	m, err := protoMessage(msg)
+	if err != nil {
+		return err
+	}
+	return c.unmarshaler.Unmarshal(data, m)
+}
+
+// serverJSONCodec is the built-in JSON codec of servers, which reads requests like
+// ReadJSONRequest: data after the JSON object is ignored, unless strict.
+type serverJSONCodec struct {
+	jsonCodec
+	strict bool
+}
+
+func (c serverJSONCodec) Unmarshal(data []byte, msg interface{}) error {
+	m, err := protoMessage(msg)
+	if err != nil {
+		return err
+	}
+	return ReadJSONRequest(bytes.NewReader(data), m, c.strict)
+}
+
+func protoMessage(msg interface{}) (proto.Message, error) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a proto.Message", msg)
+	}
+	return m, nil
+}
+
+// isJSONCodec returns true if the codec encodes JSON, which is streamed with
+// newline-delimited frames instead of length-prefixed frames.
+func isJSONCodec(codec twirp.Codec) bool {
+	return mediaType(codec.ContentType()) == "application/json"
+}
+
+// mediaType returns the media type of a Content-Type, in lowercase and without parameters.
+func mediaType(contentType string) string {
+	if i := strings.Index(contentType, ";"); i != -1 {
+		contentType = contentType[:i]
+	}
+	return strings.TrimSpace(strings.ToLower(contentType))
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/twitchtv/twirp"
+)
+
+// Compression holds the compressors enabled with twirp.WithServerCompression
+// or twirp.WithClientCompression, in order of preference, and the minimum size
+// of a body to be compressed.
+type Compression struct {
+	compressors []twirp.Compressor
+	minBytes    int
+}
+
+// ReadCompression reads the compression options from *twirp.ServerOptions or *twirp.ClientOptions.
+func ReadCompression(opts interface {
+	ReadOpt(key string, out interface{}) bool
+}) Compression {
+	c := Compression{minBytes: twirp.DefaultCompressionMinBytes}
+	_ = opts.ReadOpt("compressors", &c.compressors)
+	_ = opts.ReadOpt("compressionMinBytes", &c.minBytes)
+	return c
+}
+
+// lookup returns the enabled compressor for the encoding, or nil if it is not enabled.
+func (c Compression) lookup(encoding string) twirp.Compressor {
+	encoding = strings.TrimSpace(encoding)
+	for _, compressor := range c.compressors {
+		if strings.EqualFold(compressor.Name(), encoding) {
+			return compressor
+		}
+	}
+	return nil
+}
+
+// acceptEncoding returns the Accept-Encoding header value with all the enabled compressors.
+func (c Compression) acceptEncoding() string {
+	names := make([]string, len(c.compressors))
+	for i, compressor := range c.compressors {
+		names[i] = compressor.Name()
+	}
+	return strings.Join(names, ", ")
+}
+
+// compressRequestBody compresses a request body with the preferred compressor, if the
+// body is at least minBytes long. Returns the body and the Content-Encoding that was used,
+// which is empty if the body was not compressed.
+func (c Compression) compressRequestBody(body []byte) ([]byte, string, error) {
+	if len(c.compressors) == 0 || c.minBytes < 0 || len(body) < c.minBytes {
+		return body, "", nil
+	}
+	compressed, err := compressBytes(c.compressors[0], body)
+	if err != nil {
+		return nil, "", err
+	}
+	return compressed, c.compressors[0].Name(), nil
+}
+
+// CompressResponseBody compresses a response body with the first enabled compressor that is
+// accepted by the client in the Accept-Encoding header, if the body is at least minBytes long.
+// Returns the body and the Content-Encoding that was used, which is empty if the body was not compressed.
+func (c Compression) CompressResponseBody(req *http.Request, body []byte) ([]byte, string, error) {
+	if len(c.compressors) == 0 || len(body) < c.minBytes {
+		return body, "", nil
+	}
+	acceptEncoding := req.Header.Get("Accept-Encoding")
+	for _, compressor := range c.compressors {
+		if acceptsEncoding(acceptEncoding, compressor.Name()) {
+			compressed, err := compressBytes(compressor, body)
+			if err != nil {
+				return nil, "", err
+			}
+			return compressed, compressor.Name(), nil
+		}
+	}
+	return body, "", nil
+}
+
+// SetResponseHeaders sets the Content-Encoding of a compressed response, and the Vary header
+// if the response could have been compressed, so HTTP caches can tell the encodings apart.
+func (c Compression) SetResponseHeaders(header http.Header, contentEncoding string) {
+	if len(c.compressors) > 0 {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if contentEncoding != "" {
+		header.Set("Content-Encoding", contentEncoding)
+	}
+}
+
+// decompressResponseBody replaces the body of a response that has a Content-Encoding with
+// the decompressed body. Responses with encodings that are not enabled are left unchanged.
+func (c Compression) decompressResponseBody(resp *http.Response) error {
+	compressor := c.lookup(resp.Header.Get("Content-Encoding"))
+	if compressor == nil {
+		return nil
+	}
+	decompressed, err := compressor.Decompress(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body = readCloser{Reader: decompressed, Closer: closers{decompressed, resp.Body}}
+	resp.ContentLength = -1 // the decompressed length is unknown
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	return nil
+}
+
+// compressBytes compresses the body with the compressor.
+func compressBytes(compressor twirp.Compressor, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := compressor.Compress(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(body); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isIdentityEncoding checks if a Content-Encoding header value means no encoding.
+func isIdentityEncoding(contentEncoding string) bool {
+	contentEncoding = strings.TrimSpace(contentEncoding)
+	return contentEncoding == "" || strings.EqualFold(contentEncoding, "identity")
+}
+
+// acceptsEncoding checks if the encoding is acceptable according to an Accept-Encoding header
+// value, e.g. "gzip, deflate;q=0.5". Encodings with quality "q=0" are not acceptable, and the
+// wildcard "*" matches any encoding that is not explicitly listed.
+func acceptsEncoding(acceptEncoding, encoding string) bool {
+	wildcard := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params := part, ""
+		if i := strings.Index(part, ";"); i >= 0 {
+			name, params = part[:i], part[i+1:]
+		}
+		name = strings.TrimSpace(name)
+		acceptable := !isZeroQuality(params)
+		if strings.EqualFold(name, encoding) {
+			return acceptable
+		}
+		if name == "*" {
+			wildcard = acceptable
+		}
+	}
+	return wildcard
+}
+
+// isZeroQuality checks if the parameters of an Accept-Encoding item have "q=0".
+func isZeroQuality(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		param = strings.TrimSpace(param)
+		if strings.HasPrefix(param, "q=") || strings.HasPrefix(param, "Q=") {
+			q, err := strconv.ParseFloat(param[2:], 64)
+			return err == nil && q == 0
+		}
+	}
+	return false
+}
+
+// readCloser combines a Reader and a Closer into an io.ReadCloser.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// closers closes all of its elements, returning the first error.
+type closers []io.Closer
+
+func (cs closers) Close() error {
+	var err error
+	for _, c := range cs {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/twitchtv/twirp"
+)
+
+func TestAcceptsEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		want           bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"GZIP", true},
+		{"deflate, gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0.0", false},
+		{"*", true},
+		{"*, gzip;q=0", false},
+		{"*;q=0", false},
+		{"deflate", false},
+	}
+	for _, tt := range tests {
+		if have := acceptsEncoding(tt.acceptEncoding, "gzip"); have != tt.want {
+			t.Errorf("acceptsEncoding(%q, gzip) = %v, want %v", tt.acceptEncoding, have, tt.want)
+		}
+	}
+}
+
+func TestCompressResponseBody(t *testing.T) {
+	gzip, _ := twirp.LookupCompressor("gzip")
+	c := Compression{compressors: []twirp.Compressor{gzip}, minBytes: 10}
+	req, _ := http.NewRequest("POST", "http://example.com", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	body := []byte(strings.Repeat("hat", 100))
+	compressed, contentEncoding, err := c.CompressResponseBody(req, body)
+	if err != nil || contentEncoding != "gzip" || len(compressed) >= len(body) {
+		t.Fatalf("expected a gzip compressed body, have Content-Encoding=%q, len=%d, err=%v", contentEncoding, len(compressed), err)
+	}
+
+	resp := &http.Response{Header: http.Header{"Content-Encoding": {"gzip"}}, Body: readCloser{Reader: strings.NewReader(string(compressed)), Closer: http.NoBody}}
+	if err := c.decompressResponseBody(resp); err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := io.ReadAll(resp.Body)
+	if err != nil || string(decompressed) != string(body) {
+		t.Fatalf("unexpected decompressed body %q, err=%v", decompressed, err)
+	}
+
+	_, contentEncoding, _ = c.CompressResponseBody(req, []byte("small"))
+	if contentEncoding != "" {
+		t.Errorf("bodies smaller than minBytes are not expected to be compressed, have Content-Encoding=%q", contentEncoding)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"sync"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/twitchtv/twirp/descriptors"
+)
+
+// MethodDescriptors are the method descriptors of a generated service, by the
+// method names defined in the .proto file. Generated servers and clients add them
+// to request contexts, for descriptors.MethodDescriptorFromContext.
+type MethodDescriptors map[string]protoreflect.MethodDescriptor
+
+// methodDescriptorsCache avoids resolving the same service on every constructor
+// call. Keys are methodDescriptorsKey, the gzipped descriptors are package
+// variables in generated code.
+var methodDescriptorsCache sync.Map
+
+type methodDescriptorsKey struct {
+	gz    *byte
+	index int
+}
+
+// NewMethodDescriptors returns the method descriptors of a generated service,
+// from the gzipped FileDescriptorProto and service index returned by its
+// ServiceDescriptor method. It returns nil if the service descriptor can not be
+// resolved, then request contexts have no method descriptors.
+func NewMethodDescriptors(gz []byte, index int) MethodDescriptors {
+	if len(gz) == 0 {
+		return nil
+	}
+	key := methodDescriptorsKey{gz: &gz[0], index: index}
+	if mds, ok := methodDescriptorsCache.Load(key); ok {
+		return mds.(MethodDescriptors)
+	}
+
+	sd, err := descriptors.ServiceDescriptor(describedService{gz: gz, index: index})
+	if err != nil {
+		return nil
+	}
+	methods := sd.Methods()
+	mds := make(MethodDescriptors, methods.Len())
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		mds[string(md.Name())] = md
+	}
+	methodDescriptorsCache.Store(key, mds)
+	return mds
+}
+
+// describedService implements descriptors.DescribableService.
+type describedService struct {
+	gz    []byte
+	index int
+}
+
+func (s describedService) ServiceDescriptor() ([]byte, int) {
+	return s.gz, s.index
+}
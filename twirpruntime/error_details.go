@@ -0,0 +1,76 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// errorDetailsHeader is sent by clients that can read the "details" field of error
+// responses. Servers only send details to those clients, clients generated before
+// v8.3.0 reject error responses with unknown fields.
+const errorDetailsHeader = "Twirp-Error-Details"
+
+type errorDetailsKey struct{}
+
+// WithErrorDetailsAccepted records in the context whether the client of the request
+// can read error details (Twirp-Error-Details header), for WriteError.
+func WithErrorDetailsAccepted(ctx context.Context, req *http.Request) context.Context {
+	accepted, _ := strconv.ParseBool(req.Header.Get(errorDetailsHeader))
+	return context.WithValue(ctx, errorDetailsKey{}, accepted)
+}
+
+func errorDetailsAccepted(ctx context.Context) bool {
+	accepted, _ := ctx.Value(errorDetailsKey{}).(bool)
+	return accepted
+}
+
+// marshalErrorDetail serializes an error detail as a google.protobuf.Any message in the JSON format.
+// Details that are not protobuf messages must implement json.Marshaler.
+func marshalErrorDetail(detail interface{}) ([]byte, error) {
+	switch d := detail.(type) {
+	case json.Marshaler:
+		return d.MarshalJSON()
+	case proto.Message:
+		anyMsg, err := anypb.New(d)
+		if err != nil {
+			return nil, err
+		}
+		return protojson.Marshal(anyMsg)
+	default:
+		return nil, fmt.Errorf("unsupported error detail of type %T, expected a proto.Message", detail)
+	}
+}
+
+// unmarshalErrorDetail decodes an error detail serialized as a google.protobuf.Any message in the
+// JSON format. Details of unknown types (not linked into the binary) are returned as json.RawMessage.
+func unmarshalErrorDetail(detailJSON json.RawMessage) interface{} {
+	anyMsg := new(anypb.Any)
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(detailJSON, anyMsg); err != nil {
+		return detailJSON
+	}
+	msg, err := anyMsg.UnmarshalNew()
+	if err != nil {
+		return detailJSON
+	}
+	return msg
+}
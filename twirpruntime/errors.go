@@ -0,0 +1,299 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/ctxsetters"
+)
+
+// WriteError writes Twirp errors in the response and triggers hooks.
+func WriteError(ctx context.Context, resp http.ResponseWriter, err error, hooks *twirp.ServerHooks) {
+	twerr := asTwirpError(ctx, err)
+	statusCode := twirp.ServerHTTPStatusFromErrorCode(twerr.Code())
+	ctx = ctxsetters.WithStatusCode(ctx, statusCode)
+	ctx = CallError(ctx, hooks, twerr)
+
+	respBody := marshalErrorToJSON(twerr, errorDetailsAccepted(ctx))
+
+	resp.Header().Set("Content-Type", "application/json") // Error responses are always JSON
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBody)))
+	resp.WriteHeader(statusCode) // set HTTP status code and send response
+
+	_, writeErr := resp.Write(respBody)
+	if writeErr != nil {
+		// We have three options here. We could log the error, call the Error
+		// hook, or just silently ignore the error.
+		//
+		// Logging is unacceptable because we don't have a user-controlled
+		// logger; writing out to stderr without permission is too rude.
+		//
+		// Calling the Error hook would confuse users: it would mean the Error
+		// hook got called twice for one request, which is likely to lead to
+		// duplicated log messages and metrics, no matter how well we document
+		// the behavior.
+		//
+		// Silently ignoring the error is our least-bad option. It's highly
+		// likely that the connection is broken and the original 'err' says
+		// so anyway.
+		_ = writeErr
+	}
+
+	CallResponseSent(ctx, hooks)
+}
+
+// TransformError maps an error with the error transformer of a server, if any (see
+// twirp.WithServerErrorTransformer). The original error is kept as the cause of the
+// returned error, so it is still available to hooks.
+func TransformError(ctx context.Context, err error, transformer func(context.Context, error) twirp.Error) error {
+	if transformer == nil || err == nil {
+		return err
+	}
+	twerr := transformer(ctx, err)
+	if twerr == nil {
+		return err // not mapped
+	}
+	if error(twerr) != err && errors.Unwrap(twerr) == nil {
+		return twirp.WrapError(twerr, err)
+	}
+	return twerr
+}
+
+// asTwirpError converts errors returned by service methods to twirp.Error.
+// Non-twirp errors are converted to internal errors.
+func asTwirpError(ctx context.Context, err error) twirp.Error {
+	var twerr twirp.Error
+	if errors.As(err, &twerr) {
+		return twerr
+	}
+	if ctx.Err() == context.DeadlineExceeded && errors.Is(err, context.DeadlineExceeded) {
+		// The request timed out (i.e. the timeout sent by the client), it's not an internal failure.
+		return twirp.WrapError(twirp.NewError(twirp.DeadlineExceeded, err.Error()), err)
+	}
+	return twirp.InternalErrorWith(err)
+}
+
+// JSON serialization for errors
+type twerrJSON struct {
+	Code    string            `json:"code"`
+	Msg     string            `json:"msg"`
+	Meta    map[string]string `json:"meta,omitempty"`
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// marshalErrorToJSON returns JSON from a twirp.Error, that can be used as HTTP error response body.
+// Error details are only included if withDetails is true (see WithErrorDetailsAccepted).
+// If serialization fails, it will use a descriptive Internal error instead.
+func marshalErrorToJSON(twerr twirp.Error, withDetails bool) []byte {
+	// make sure that msg is not too large
+	msg := twerr.Msg()
+	if len(msg) > 1e6 {
+		msg = msg[:1e6]
+	}
+
+	tj := twerrJSON{
+		Code: string(twerr.Code()),
+		Msg:  msg,
+		Meta: twerr.MetaMap(),
+	}
+	if withDetails {
+		for _, detail := range twirp.ErrorDetails(twerr) {
+			detailJSON, err := marshalErrorDetail(detail)
+			if err != nil {
+				continue // skip details that can not be serialized, code and msg are still useful
+			}
+			tj.Details = append(tj.Details, detailJSON)
+		}
+	}
+
+	buf, err := json.Marshal(&tj)
+	if err != nil {
+		buf = []byte("{\"type\": \"" + twirp.Internal + "\", \"msg\": \"There was an error but it could not be serialized into JSON\"}") // fallback
+	}
+
+	return buf
+}
+
+// errorFromResponse builds a twirp.Error from a non-200 HTTP response.
+// If the response has a valid serialized Twirp error, then it's returned.
+// If not, the response status code is used to generate a similar twirp
+// error. See twirpErrorFromIntermediary for more info on intermediary errors.
+func errorFromResponse(resp *http.Response, maxResponseBytes int64) twirp.Error {
+	statusCode := resp.StatusCode
+	statusText := http.StatusText(statusCode)
+
+	if isHTTPRedirect(statusCode) {
+		// Unexpected redirect: it must be an error from an intermediary.
+		// Twirp clients don't follow redirects automatically, Twirp only handles
+		// POST requests, redirects should only happen on GET and HEAD requests.
+		location := resp.Header.Get("Location")
+		msg := fmt.Sprintf("unexpected HTTP status code %d %q received, Location=%q", statusCode, statusText, location)
+		return twirpErrorFromIntermediary(statusCode, msg, location)
+	}
+
+	respBodyBytes, err := io.ReadAll(limitBodyReader(resp.Body, resp.ContentLength, maxResponseBytes))
+	if err != nil {
+		if twerr := BodyTooLargeTwirpError(err, "error response "); twerr != nil {
+			return twerr
+		}
+		return WrapInternal(err, "failed to read server error response body")
+	}
+
+	var tj twerrJSON
+	if isIntermediaryErrorBody(respBodyBytes, &tj) {
+		// Not a Twirp error response; it must be an error from an intermediary.
+		msg := fmt.Sprintf("Error from intermediary with HTTP status code %d %q", statusCode, statusText)
+		return twirpErrorFromIntermediary(statusCode, msg, string(respBodyBytes))
+	}
+
+	return errorFromTwerrJSON(tj, respBodyBytes)
+}
+
+// isIntermediaryErrorBody decodes an error response body into tj, and returns true if it
+// is not a Twirp error: invalid JSON, no code, or an invalid code with fields that Twirp
+// errors don't have, like {"code":"rate_limited","message":"slow down"} from a proxy.
+// Unknown fields are accepted with valid codes, so newer servers can add fields.
+func isIntermediaryErrorBody(body []byte, tj *twerrJSON) bool {
+	if err := json.Unmarshal(body, tj); err != nil || tj.Code == "" {
+		return true
+	}
+	if twirp.IsValidErrorCode(twirp.ErrorCode(tj.Code)) {
+		return false
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	return dec.Decode(&twerrJSON{}) != nil
+}
+
+// errorFromTwerrJSON builds a twirp.Error from a decoded error response. The raw body
+// is added as metadata if the error code is invalid.
+func errorFromTwerrJSON(tj twerrJSON, body []byte) twirp.Error {
+	errorCode := twirp.ErrorCode(tj.Code)
+	if !twirp.IsValidErrorCode(errorCode) {
+		msg := "invalid type returned from server error response: " + tj.Code
+		return twirp.InternalError(msg).WithMeta("body", string(body))
+	}
+
+	twerr := twirp.NewError(errorCode, tj.Msg)
+	for k, v := range tj.Meta {
+		twerr = twerr.WithMeta(k, v)
+	}
+	if len(tj.Details) > 0 {
+		details := make([]interface{}, len(tj.Details))
+		for i, detailJSON := range tj.Details {
+			details[i] = unmarshalErrorDetail(detailJSON)
+		}
+		twerr = twirp.WithErrorDetails(twerr, details...)
+	}
+	return twerr
+}
+
+// twirpErrorFromIntermediary maps HTTP errors from non-twirp sources to twirp errors.
+// The mapping is similar to gRPC: https://github.com/grpc/grpc/blob/master/doc/http-grpc-status-mapping.md.
+// Returned twirp Errors have some additional metadata for inspection.
+func twirpErrorFromIntermediary(status int, msg string, bodyOrLocation string) twirp.Error {
+	var code twirp.ErrorCode
+	if isHTTPRedirect(status) { // 3xx
+		code = twirp.Internal
+	} else {
+		switch status {
+		case 400: // Bad Request
+			code = twirp.Internal
+		case 401: // Unauthorized
+			code = twirp.Unauthenticated
+		case 403: // Forbidden
+			code = twirp.PermissionDenied
+		case 404: // Not Found
+			code = twirp.BadRoute
+		case 429: // Too Many Requests
+			code = twirp.ResourceExhausted
+		case 502, 503, 504: // Bad Gateway, Service Unavailable, Gateway Timeout
+			code = twirp.Unavailable
+		default: // All other codes
+			code = twirp.Unknown
+		}
+	}
+
+	twerr := twirp.NewError(code, msg)
+	twerr = twerr.WithMeta("http_error_from_intermediary", "true") // to easily know if this error was from intermediary
+	twerr = twerr.WithMeta("status_code", strconv.Itoa(status))
+	if isHTTPRedirect(status) {
+		twerr = twerr.WithMeta("location", bodyOrLocation)
+	} else {
+		twerr = twerr.WithMeta("body", bodyOrLocation)
+	}
+	return twerr
+}
+
+func isHTTPRedirect(status int) bool {
+	return status >= 300 && status <= 399
+}
+
+// WrapInternal wraps an error with a prefix as an Internal error.
+// The original error cause is accessible by github.com/pkg/errors.Cause.
+func WrapInternal(err error, prefix string) twirp.Error {
+	return twirp.InternalErrorWith(&wrappedError{prefix: prefix, cause: err})
+}
+
+type wrappedError struct {
+	prefix string
+	cause  error
+}
+
+func (e *wrappedError) Error() string { return e.prefix + ": " + e.cause.Error() }
+func (e *wrappedError) Unwrap() error { return e.cause } // for go1.13 + errors.Is/As
+func (e *wrappedError) Cause() error  { return e.cause } // for github.com/pkg/errors
+
+// MalformedRequestError is used when the twirp server cannot unmarshal a request
+func MalformedRequestError(msg string) twirp.Error {
+	return twirp.NewError(twirp.Malformed, msg)
+}
+
+// RequestDecodingError is used when the codec of the twirp server cannot unmarshal a request
+func RequestDecodingError(codec twirp.Codec, err error) twirp.Error {
+	name := codec.ContentType()
+	switch mediaType(name) {
+	case "application/json":
+		name = "json"
+	case "application/protobuf":
+		name = "protobuf"
+	}
+	return twirp.WrapError(MalformedRequestError("the "+name+" request could not be decoded"), err)
+}
+
+// RequestBodyErrorMessage is the message of errors from handleRequestBodyError when the
+// twirp server cannot read the request body: JSON requests keep the message they had
+// when they were decoded while reading the body.
+func RequestBodyErrorMessage(codec twirp.Codec) string {
+	if isJSONCodec(codec) {
+		return "the json request could not be decoded"
+	}
+	return "failed to read request body"
+}
+
+// BadRouteError is used when the twirp server cannot route a request
+func BadRouteError(msg string, method, url string) twirp.Error {
+	err := twirp.NewError(twirp.BadRoute, msg)
+	err = err.WithMeta("twirp_invalid_route", method+" "+url)
+	return err
+}
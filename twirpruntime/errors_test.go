@@ -0,0 +1,99 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/twitchtv/twirp"
+)
+
+func TestErrorFromResponse(t *testing.T) {
+	newResponse := func(status int, body string) *http.Response {
+		return &http.Response{
+			StatusCode:    status,
+			Header:        make(http.Header),
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		wantCode twirp.ErrorCode
+		wantMeta map[string]string
+	}{
+		{
+			name:     "twirp error",
+			resp:     newResponse(404, `{"code":"not_found","msg":"no hat","meta":{"size":"12"}}`),
+			wantCode: twirp.NotFound,
+			wantMeta: map[string]string{"size": "12"},
+		},
+		{
+			name:     "invalid code",
+			resp:     newResponse(500, `{"code":"oops","msg":"no hat"}`),
+			wantCode: twirp.Internal,
+		},
+		{
+			name:     "intermediary",
+			resp:     newResponse(503, `<html>Service Unavailable</html>`),
+			wantCode: twirp.Unavailable,
+			wantMeta: map[string]string{"http_error_from_intermediary": "true", "status_code": "503", "body": "<html>Service Unavailable</html>"},
+		},
+		{
+			name:     "intermediary with unknown fields",
+			resp:     newResponse(429, `{"code":"rate_limited","message":"slow down"}`),
+			wantCode: twirp.ResourceExhausted,
+			wantMeta: map[string]string{"http_error_from_intermediary": "true", "status_code": "429"},
+		},
+		{
+			name:     "unknown fields",
+			resp:     newResponse(404, `{"code":"not_found","msg":"no hat","retry":{"after":"1s"}}`),
+			wantCode: twirp.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twerr := errorFromResponse(tt.resp, 0)
+			if twerr.Code() != tt.wantCode {
+				t.Errorf("unexpected error code %q, want %q (error: %v)", twerr.Code(), tt.wantCode, twerr)
+			}
+			for k, v := range tt.wantMeta {
+				if have := twerr.Meta(k); have != v {
+					t.Errorf("unexpected meta %q: %q, want %q", k, have, v)
+				}
+			}
+		})
+	}
+
+	t.Run("redirect", func(t *testing.T) {
+		resp := newResponse(302, "")
+		resp.Header.Set("Location", "http://example.com/elsewhere")
+		twerr := errorFromResponse(resp, 0)
+		if twerr.Code() != twirp.Internal || twerr.Meta("location") != "http://example.com/elsewhere" {
+			t.Errorf("unexpected error for redirect: %v, meta: %v", twerr, twerr.MetaMap())
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		twerr := errorFromResponse(newResponse(500, `{"code":"internal","msg":"a long message"}`), 10)
+		if twerr.Code() != twirp.ResourceExhausted {
+			t.Errorf("unexpected error for a body over the limit: %v", twerr)
+		}
+	})
+}
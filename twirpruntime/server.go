@@ -0,0 +1,299 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/internal/contextkeys"
+)
+
+// NewServerOptions applies the options passed to a generated server constructor.
+// Options can be twirp.ServerOption, or *twirp.ServerHooks for backwards compatibility.
+func NewServerOptions(opts []interface{}) *twirp.ServerOptions {
+	serverOpts := &twirp.ServerOptions{}
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case twirp.ServerOption:
+			o(serverOpts)
+		case *twirp.ServerHooks: // backwards compatibility, allow to specify hooks as an argument
+			twirp.WithServerHooks(o)(serverOpts)
+		case nil: // backwards compatibility, allow nil value for the argument
+			continue
+		default:
+			panic(fmt.Sprintf("Invalid option type %T, please use a twirp.ServerOption", o))
+		}
+	}
+	return serverOpts
+}
+
+// MuxServerOptions returns the hooks and interceptor that a twirp.Mux shares with
+// all its servers, if the request context comes from a Mux. Generated servers apply
+// them before their own hooks and interceptors.
+func MuxServerOptions(ctx context.Context) (hooks *twirp.ServerHooks, interceptor twirp.Interceptor, ok bool) {
+	hooks, ok = ctx.Value(contextkeys.MuxServerHooksKey).(*twirp.ServerHooks)
+	if !ok {
+		return nil, nil, false
+	}
+	interceptor, _ = ctx.Value(contextkeys.MuxInterceptorKey).(twirp.Interceptor)
+	return hooks, interceptor, true
+}
+
+// ParseTwirpPath extracts path components form a valid Twirp route.
+// Expected format: "[<prefix>]/<package>.<Service>/<Method>"
+// e.g.: prefix, pkgService, method := ParseTwirpPath("/twirp/pkg.Svc/MakeHat")
+func ParseTwirpPath(path string) (string, string, string) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return "", "", ""
+	}
+	method := parts[len(parts)-1]
+	pkgService := parts[len(parts)-2]
+	prefix := strings.Join(parts[0:len(parts)-2], "/")
+	return prefix, pkgService, method
+}
+
+// RequestFromQuery converts an HTTP GET request for a method without side effects into the
+// equivalent POST request, with the request message from the query string (see queryFromRequestBody).
+// The message is empty if the query string has no message.
+func RequestFromQuery(req *http.Request) (*http.Request, error) {
+	query := req.URL.Query()
+	message := query.Get("message")
+	var body []byte
+	var contentType string
+	switch encoding := query.Get("encoding"); encoding {
+	case "", "json":
+		contentType = "application/json"
+		body = []byte(message)
+		if message == "" {
+			body = []byte("{}")
+		}
+	case "protobuf":
+		contentType = "application/protobuf"
+		var err error
+		body, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(message, "="))
+		if err != nil {
+			return nil, MalformedRequestError("the message query parameter is not valid base64url: " + err.Error())
+		}
+	default:
+		return nil, MalformedRequestError(fmt.Sprintf("unsupported encoding %q in query string, expected \"json\" or \"protobuf\"", encoding))
+	}
+
+	postReq := req.Clone(req.Context())
+	postReq.Body = io.NopCloser(bytes.NewReader(body))
+	postReq.ContentLength = int64(len(body))
+	postReq.Header.Set("Content-Type", contentType)
+	postReq.Header.Del("Content-Encoding")
+	return postReq, nil
+}
+
+// WithRequestTimeout applies the timeout sent by the client in the Twirp-Timeout header
+// to the request context, reduced to maxTimeout if it is larger (and maxTimeout is positive).
+// It reports whether a timeout was applied, which is false if the request has no Twirp-Timeout
+// header. The returned cancel function must always be called to release resources.
+func WithRequestTimeout(ctx context.Context, req *http.Request, maxTimeout time.Duration) (context.Context, context.CancelFunc, bool, error) {
+	header := req.Header.Get("Twirp-Timeout")
+	if header == "" {
+		return ctx, func() {}, false, nil
+	}
+	ms, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || ms <= 0 {
+		return ctx, func() {}, false, MalformedRequestError(fmt.Sprintf("invalid Twirp-Timeout header %q, expected a positive number of milliseconds", header))
+	}
+	if maxMs := int64(1<<63-1) / int64(time.Millisecond); ms > maxMs {
+		ms = maxMs // avoid overflow on very large values
+	}
+	timeout := time.Duration(ms) * time.Millisecond
+	if maxTimeout > 0 && timeout > maxTimeout {
+		timeout = maxTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, true, nil
+}
+
+// MethodTimeoutError enforces the timeout of a method, declared with the option
+// (twirp.method).timeout or sent by the client in the Twirp-Timeout header: if ctx
+// expired while calling the method, it returns a twirp.DeadlineExceeded error, even
+// if the method did not fail. Errors returned by the method are kept if they are a
+// twirp.Error. It must only be called if one of those timeouts was applied to ctx, so
+// deadlines set by other means (like a http.TimeoutHandler) don't replace responses.
+func MethodTimeoutError(ctx context.Context, err error) error {
+	var twerr twirp.Error
+	if ctx.Err() != context.DeadlineExceeded || errors.As(err, &twerr) {
+		return err
+	}
+	if err == nil {
+		return twirp.NewError(twirp.DeadlineExceeded, "method timeout expired")
+	}
+	return twirp.WrapError(twirp.NewError(twirp.DeadlineExceeded, err.Error()), err)
+}
+
+// RequestBody returns the request body, decompressed if it has a Content-Encoding, and
+// limited to maxBytes (no limit if 0 or less). It must be closed after reading.
+func RequestBody(req *http.Request, maxBytes int64, compression Compression) (io.ReadCloser, error) {
+	contentEncoding := req.Header.Get("Content-Encoding")
+	if isIdentityEncoding(contentEncoding) {
+		return io.NopCloser(limitBodyReader(req.Body, req.ContentLength, maxBytes)), nil
+	}
+	compressor := compression.lookup(contentEncoding)
+	if compressor == nil {
+		return nil, MalformedRequestError(fmt.Sprintf("unsupported Content-Encoding %q", contentEncoding))
+	}
+	decompressed, err := compressor.Decompress(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	// The size limit applies to the decompressed body, which has an unknown length.
+	return readCloser{Reader: limitBodyReader(decompressed, -1, maxBytes), Closer: decompressed}, nil
+}
+
+// ReadJSONRequest reads a JSON request message from the request body. Unknown fields are
+// discarded, unless strict is true (see twirp.WithServerJSONStrict), which rejects unknown
+// fields and any data after the JSON object.
+func ReadJSONRequest(body io.Reader, msg proto.Message, strict bool) error {
+	d := json.NewDecoder(body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		return err
+	}
+	if strict {
+		if _, err := d.Token(); err != io.EOF {
+			if err == nil {
+				err = errors.New("unexpected data after the JSON object")
+			}
+			return err
+		}
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: !strict}.Unmarshal(rawReqBody, msg)
+}
+
+// EnsurePanicResponses makes sure that rpc methods causing a panic still result in a Twirp Internal
+// error response (status 500), and error hooks are properly called with the panic wrapped as an error.
+// The panic is re-raised so it can be handled normally with middleware. If the server has a panic
+// handler (see twirp.WithServerPanicHandler), the panic is not re-raised: the error is set in errp
+// to be written by the caller like any other error.
+func EnsurePanicResponses(ctx context.Context, resp http.ResponseWriter, hooks *twirp.ServerHooks, panicHandler func(context.Context, interface{}, []byte) twirp.Error, errp *error) {
+	if r := recover(); r != nil {
+		twerr, handled := panicError(ctx, r, panicHandler)
+		if handled {
+			*errp = twerr
+			return
+		}
+		// Actually write the error
+		WriteError(ctx, resp, twerr, hooks)
+		// If possible, flush the error to the wire.
+		f, ok := resp.(http.Flusher)
+		if ok {
+			f.Flush()
+		}
+
+		panic(r)
+	}
+}
+
+// panicError returns the error response for a recovered panic, and whether the panic was handled
+// by the panic handler, which can return the error response, or nil to use the default Internal error.
+func panicError(ctx context.Context, r interface{}, panicHandler func(context.Context, interface{}, []byte) twirp.Error) (twirp.Error, bool) {
+	// Wrap the panic as an error so it can be passed to error hooks.
+	// The original error is accessible from error hooks, but not visible in the response.
+	twerr := twirp.Error(&internalWithCause{msg: "Internal service panic", cause: errFromPanic(r)})
+	if panicHandler == nil {
+		return twerr, false
+	}
+	// Called while panicking, so the stack includes the function that panicked
+	if handled := panicHandler(ctx, r, debug.Stack()); handled != nil {
+		twerr = handled
+	}
+	return twerr, true
+}
+
+// errFromPanic returns the typed error if the recovered panic is an error, otherwise formats as error.
+func errFromPanic(p interface{}) error {
+	if err, ok := p.(error); ok {
+		return err
+	}
+	return fmt.Errorf("panic: %v", p)
+}
+
+// internalWithCause is a Twirp Internal error wrapping an original error cause,
+// but the original error message is not exposed on Msg(). The original error
+// can be checked with go1.13+ errors.Is/As, and also by (github.com/pkg/errors).Unwrap
+type internalWithCause struct {
+	msg   string
+	cause error
+}
+
+func (e *internalWithCause) Unwrap() error                               { return e.cause } // for go1.13 + errors.Is/As
+func (e *internalWithCause) Cause() error                                { return e.cause } // for github.com/pkg/errors
+func (e *internalWithCause) Error() string                               { return e.msg + ": " + e.cause.Error() }
+func (e *internalWithCause) Code() twirp.ErrorCode                       { return twirp.Internal }
+func (e *internalWithCause) Msg() string                                 { return e.msg }
+func (e *internalWithCause) Meta(key string) string                      { return "" }
+func (e *internalWithCause) MetaMap() map[string]string                  { return nil }
+func (e *internalWithCause) WithMeta(key string, val string) twirp.Error { return e }
+
+// CallRequestReceived calls twirp.ServerHooks.RequestReceived if the hook is available.
+func CallRequestReceived(ctx context.Context, h *twirp.ServerHooks) (context.Context, error) {
+	if h == nil || h.RequestReceived == nil {
+		return ctx, nil
+	}
+	return h.RequestReceived(ctx)
+}
+
+// CallRequestRouted calls twirp.ServerHooks.RequestRouted if the hook is available.
+func CallRequestRouted(ctx context.Context, h *twirp.ServerHooks) (context.Context, error) {
+	if h == nil || h.RequestRouted == nil {
+		return ctx, nil
+	}
+	return h.RequestRouted(ctx)
+}
+
+// CallResponsePrepared calls twirp.ServerHooks.ResponsePrepared if the hook is available.
+func CallResponsePrepared(ctx context.Context, h *twirp.ServerHooks) context.Context {
+	if h == nil || h.ResponsePrepared == nil {
+		return ctx
+	}
+	return h.ResponsePrepared(ctx)
+}
+
+// CallResponseSent calls twirp.ServerHooks.ResponseSent if the hook is available.
+func CallResponseSent(ctx context.Context, h *twirp.ServerHooks) {
+	if h == nil || h.ResponseSent == nil {
+		return
+	}
+	h.ResponseSent(ctx)
+}
+
+// CallError calls twirp.ServerHooks.Error if the hook is available.
+func CallError(ctx context.Context, h *twirp.ServerHooks, err twirp.Error) context.Context {
+	if h == nil || h.Error == nil {
+		return ctx
+	}
+	return h.Error(ctx, err)
+}
@@ -0,0 +1,407 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/ctxsetters"
+)
+
+// Frame types of server-streaming responses.
+const (
+	streamFrameMessage byte = 0 // a response message
+	streamFrameError   byte = 1 // a terminal Twirp error, as JSON
+	streamFrameEnd     byte = 2 // the stream ended successfully
+)
+
+// ServerStream writes the frames of a server-streaming response. Response headers
+// are sent with the first frame, so errors returned before any message is sent
+// are regular Twirp error responses, and errors after that are error frames.
+type ServerStream struct {
+	ctx              context.Context
+	resp             http.ResponseWriter
+	hooks            *twirp.ServerHooks
+	panicHandler     func(context.Context, interface{}, []byte) twirp.Error
+	errorTransformer func(context.Context, error) twirp.Error
+	codec            twirp.Codec
+	json             bool // newline-delimited JSON frames instead of length-prefixed frames
+
+	started  bool        // response headers were sent
+	finished bool        // the method returned, no more messages can be sent
+	writeErr twirp.Error // the stream is broken and no more frames can be written
+}
+
+// NewServerStream returns the stream for a call to a server-streaming method, with messages
+// serialized by the codec. Frames are newline-delimited JSON objects if the codec encodes
+// JSON, or length-prefixed messages otherwise.
+func NewServerStream(ctx context.Context, resp http.ResponseWriter, hooks *twirp.ServerHooks, panicHandler func(context.Context, interface{}, []byte) twirp.Error, errorTransformer func(context.Context, error) twirp.Error, codec twirp.Codec) *ServerStream {
+	return &ServerStream{
+		ctx:              ctx,
+		resp:             resp,
+		hooks:            hooks,
+		panicHandler:     panicHandler,
+		errorTransformer: errorTransformer,
+		codec:            codec,
+		json:             isJSONCodec(codec),
+	}
+}
+
+// Send writes a response message, sending the response headers first if needed.
+func (s *ServerStream) Send(msg proto.Message) error {
+	if s.finished {
+		return twirp.InternalError("stream message sent after the method returned")
+	}
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	payload, err := s.codec.Marshal(msg)
+	if err != nil {
+		return WrapInternal(err, "failed to marshal stream message")
+	}
+	s.start()
+	return s.writeFrame(streamFrameMessage, payload)
+}
+
+// start sends the response headers, once.
+func (s *ServerStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	s.ctx = CallResponsePrepared(s.ctx, s.hooks)
+	s.ctx = ctxsetters.WithStatusCode(s.ctx, http.StatusOK)
+	s.resp.Header().Set("Content-Type", s.codec.ContentType())
+	s.resp.WriteHeader(http.StatusOK)
+}
+
+// writeFrame writes a frame and flushes it to the client.
+// Protobuf frames are prefixed with the frame type (1 byte) and payload length (4 bytes, big-endian).
+// JSON frames are objects followed by a newline: {"message":<msg>}, {"error":<twirp error>} or {"end":true}.
+// JSON payloads are compacted first, so codecs that emit newlines don't break the framing.
+func (s *ServerStream) writeFrame(frameType byte, payload []byte) twirp.Error {
+	var frame []byte
+	if s.json {
+		if len(payload) > 0 {
+			compacted := &bytes.Buffer{}
+			if err := json.Compact(compacted, payload); err != nil {
+				return WrapInternal(err, "failed to compact stream message")
+			}
+			payload = compacted.Bytes()
+		}
+		switch frameType {
+		case streamFrameMessage:
+			frame = append(append([]byte(`{"message":`), payload...), "}\n"...)
+		case streamFrameError:
+			frame = append(append([]byte(`{"error":`), payload...), "}\n"...)
+		case streamFrameEnd:
+			frame = []byte(`{"end":true}` + "\n")
+		}
+	} else {
+		if uint64(len(payload)) > 1<<32-1 {
+			return twirp.InternalError("stream message is too large")
+		}
+		frame = make([]byte, 5+len(payload))
+		frame[0] = frameType
+		binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+		copy(frame[5:], payload)
+	}
+
+	if n, err := s.resp.Write(frame); err != nil {
+		msg := fmt.Sprintf("failed to write stream frame, %d of %d bytes written: %s", n, len(frame), err.Error())
+		s.writeErr = twirp.NewError(twirp.Unknown, msg)
+		return s.writeErr
+	}
+	if f, ok := s.resp.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// Finish ends the stream after the method returned, with an error frame if err is not nil,
+// or an end frame otherwise, and triggers hooks.
+func (s *ServerStream) Finish(err error) {
+	s.finished = true
+	err = TransformError(s.ctx, err, s.errorTransformer)
+	if !s.started && err != nil {
+		WriteError(s.ctx, s.resp, err, s.hooks) // nothing was sent, use a regular error response
+		return
+	}
+	s.start()
+	switch {
+	case s.writeErr != nil:
+		s.ctx = CallError(s.ctx, s.hooks, s.writeErr)
+	case err != nil:
+		twerr := asTwirpError(s.ctx, err)
+		s.ctx = CallError(s.ctx, s.hooks, twerr)
+		_ = s.writeFrame(streamFrameError, marshalErrorToJSON(twerr, errorDetailsAccepted(s.ctx)))
+	default:
+		if writeErr := s.writeFrame(streamFrameEnd, nil); writeErr != nil {
+			s.ctx = CallError(s.ctx, s.hooks, writeErr)
+		}
+	}
+	CallResponseSent(s.ctx, s.hooks)
+}
+
+// EnsurePanicResponses makes sure that streaming methods causing a panic still end the
+// stream with a Twirp Internal error, like the EnsurePanicResponses function.
+func (s *ServerStream) EnsurePanicResponses(errp *error) {
+	if r := recover(); r != nil {
+		twerr, handled := panicError(s.ctx, r, s.panicHandler)
+		if handled {
+			*errp = twerr
+			return
+		}
+		s.Finish(twerr)
+		panic(r)
+	}
+}
+
+// DoStreamRequest makes a request to a server-streaming method of the remote Twirp service,
+// and returns the stream to read the response messages, serialized with the codec.
+func DoStreamRequest(ctx context.Context, client HTTPClient, hooks *twirp.ClientHooks, url string, in proto.Message, codec twirp.Codec, maxResponseBytes int64, compression Compression, httpGet bool) (context.Context, *ClientStream, error) {
+	reqBodyBytes, err := codec.Marshal(in)
+	if err != nil {
+		return ctx, nil, WrapInternal(err, "failed to marshal request")
+	}
+	contentType := codec.ContentType()
+	httpGet = httpGet && hasQueryEncoding(contentType)
+	if err = ctx.Err(); err != nil {
+		return ctx, nil, WrapInternal(err, "aborted because context was done")
+	}
+
+	req, err := newRequest(ctx, url, reqBodyBytes, contentType, compression, httpGet)
+	if err != nil {
+		return ctx, nil, WrapInternal(err, "could not build request")
+	}
+	ctx, err = CallClientRequestPrepared(ctx, hooks, req)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	req = req.WithContext(ctx)
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx, nil, WrapInternal(err, "failed to do request")
+	}
+	ctx = ctxsetters.WithStatusCode(ctx, resp.StatusCode) // for the hooks; not set if the request failed
+	if err = compression.decompressResponseBody(resp); err != nil {
+		_ = resp.Body.Close()
+		return ctx, nil, WrapInternal(err, "failed to decompress response body")
+	}
+	if resp.StatusCode != 200 {
+		defer func() { _ = resp.Body.Close() }()
+		return ctx, nil, errorFromResponse(resp, maxResponseBytes)
+	}
+
+	return ctx, &ClientStream{
+		ctx:             ctx,
+		hooks:           hooks,
+		body:            resp.Body,
+		r:               bufio.NewReader(resp.Body),
+		codec:           codec,
+		json:            isJSONCodec(codec),
+		maxMessageBytes: maxResponseBytes,
+	}, nil
+}
+
+// ClientStream reads the frames of a server-streaming response, see ServerStream.
+type ClientStream struct {
+	ctx             context.Context
+	hooks           *twirp.ClientHooks
+	body            io.Closer
+	r               *bufio.Reader
+	codec           twirp.Codec
+	json            bool
+	maxMessageBytes int64              // limit for each message, no limit if 0 or less
+	cancel          context.CancelFunc // optional, called when the stream is done
+
+	done bool
+	err  error
+}
+
+// Recv reads the next message into msg. It returns false when the stream is over,
+// either with an end frame or with an error (see Err), and triggers hooks.
+func (s *ClientStream) Recv(msg proto.Message) bool {
+	if s.done {
+		return false
+	}
+	err := s.readMessage(msg)
+	if err == nil {
+		return true
+	}
+	s.finish()
+	if err == io.EOF {
+		CallClientResponseReceived(s.ctx, s.hooks)
+		return false
+	}
+	twerr, ok := err.(twirp.Error)
+	if !ok {
+		twerr = twirp.InternalErrorWith(err)
+	}
+	s.err = twerr
+	CallClientError(s.ctx, s.hooks, twerr)
+	return false
+}
+
+// Err returns the error that ended the stream, or nil if it ended successfully.
+func (s *ClientStream) Err() error {
+	return s.err
+}
+
+// Close stops reading the stream before the end.
+func (s *ClientStream) Close() error {
+	if s.done {
+		return nil
+	}
+	err := s.finish()
+	CallClientResponseReceived(s.ctx, s.hooks)
+	return err
+}
+
+// SetCancel sets a function to release the context of the stream, which is called when
+// the stream is over or closed.
+func (s *ClientStream) SetCancel(cancel context.CancelFunc) {
+	s.cancel = cancel
+}
+
+// finish marks the stream as done, and closes the response body.
+func (s *ClientStream) finish() error {
+	s.done = true
+	err := s.body.Close()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return err
+}
+
+// readMessage reads the next frame. It returns io.EOF on the end frame, and a twirp.Error
+// on error frames or if the stream can not be read.
+func (s *ClientStream) readMessage(msg proto.Message) error {
+	frameType, payload, err := s.readFrame()
+	if err != nil {
+		if ctxErr := s.ctx.Err(); ctxErr != nil {
+			return WrapInternal(ctxErr, "aborted because context was done")
+		}
+		if twerr := BodyTooLargeTwirpError(err, "stream message "); twerr != nil {
+			return twerr
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF // the stream must end with an end or error frame
+		}
+		return WrapInternal(err, "failed to read stream frame")
+	}
+
+	switch frameType {
+	case streamFrameMessage:
+		if err := s.codec.Unmarshal(payload, msg); err != nil {
+			return WrapInternal(err, "failed to unmarshal stream message")
+		}
+		return nil
+	case streamFrameError:
+		var tj twerrJSON
+		if err := json.Unmarshal(payload, &tj); err != nil || tj.Code == "" {
+			return twirp.InternalError("invalid error frame in stream").WithMeta("body", string(payload))
+		}
+		return errorFromTwerrJSON(tj, payload)
+	case streamFrameEnd:
+		return io.EOF
+	default:
+		return twirp.InternalError(fmt.Sprintf("unknown stream frame type %d", frameType))
+	}
+}
+
+// readFrame reads the type and payload of the next frame, see ServerStream.writeFrame.
+func (s *ClientStream) readFrame() (byte, []byte, error) {
+	if s.json {
+		line, err := readStreamLine(s.r, s.maxMessageBytes)
+		if err != nil {
+			return 0, nil, err
+		}
+		var frame struct {
+			Message json.RawMessage `json:"message"`
+			Error   json.RawMessage `json:"error"`
+			End     bool            `json:"end"`
+		}
+		if err := json.Unmarshal(line, &frame); err != nil {
+			return 0, nil, err
+		}
+		switch {
+		case frame.Message != nil:
+			return streamFrameMessage, frame.Message, nil
+		case frame.Error != nil:
+			return streamFrameError, frame.Error, nil
+		case frame.End:
+			return streamFrameEnd, nil, nil
+		default:
+			return 0, nil, errors.New("invalid JSON stream frame: " + string(line))
+		}
+	}
+
+	var header [5]byte
+	if _, err := io.ReadFull(s.r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := int64(binary.BigEndian.Uint32(header[1:]))
+	if s.maxMessageBytes > 0 && size > s.maxMessageBytes {
+		return 0, nil, &bodyTooLargeError{maxBytes: s.maxMessageBytes}
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(s.r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+// readStreamLine reads a newline-terminated line, failing with a *bodyTooLargeError if it
+// is longer than maxBytes (no limit if 0 or less). Empty lines are skipped.
+func readStreamLine(r *bufio.Reader, maxBytes int64) ([]byte, error) {
+	var line []byte
+	for {
+		part, err := r.ReadSlice('\n')
+		line = append(line, part...)
+		if maxBytes > 0 && int64(len(line)) > maxBytes+1 { // +1 for the newline
+			return nil, &bodyTooLargeError{maxBytes: maxBytes}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			return line, nil
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package twirpruntime is an implementation detail for twirp generated code,
+// with the utilities shared by all generated clients and servers. Keeping them
+// out of the generated files means that fixes ship with library upgrades
+// instead of regenerating code, and that binaries only include them once.
+//
+// The generated code asserts the minimum version of the twirp package that
+// includes the runtime it needs, see twirp.TwirpPackageMinVersion_8_3_0.
+//
+// Do not use twirpruntime outside of twirp's generated code.
+package twirpruntime
+
+import (
+	"path"
+)
+
+// twirpVersion is the Twirp version sent by clients in the Twirp-Version header.
+const twirpVersion = "v8.3.0"
+
+// BaseServicePath composes the path prefix for the service (without <Method>).
+// e.g.: BaseServicePath("/twirp", "my.pkg", "MyService")
+//
+//	returns => "/twirp/my.pkg.MyService/"
+//
+// e.g.: BaseServicePath("", "", "MyService")
+//
+//	returns => "/MyService/"
+func BaseServicePath(prefix, pkg, service string) string {
+	fullServiceName := service
+	if pkg != "" {
+		fullServiceName = pkg + "." + service
+	}
+	return path.Join("/", prefix, fullServiceName) + "/"
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirpruntime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/internal/gen"
+)
+
+func TestVersion(t *testing.T) {
+	if twirpVersion != gen.Version {
+		t.Errorf("twirpVersion %q is expected to be the same as the protoc-gen-twirp version %q", twirpVersion, gen.Version)
+	}
+}
+
+func TestBaseServicePath(t *testing.T) {
+	tests := []struct {
+		prefix, pkg, service string
+		want                 string
+	}{
+		{"/twirp", "my.pkg", "MyService", "/twirp/my.pkg.MyService/"},
+		{"twirp/", "my.pkg", "MyService", "/twirp/my.pkg.MyService/"},
+		{"", "", "MyService", "/MyService/"},
+		{"/", "my.pkg", "MyService", "/my.pkg.MyService/"},
+	}
+	for _, tt := range tests {
+		if have := BaseServicePath(tt.prefix, tt.pkg, tt.service); have != tt.want {
+			t.Errorf("BaseServicePath(%q, %q, %q) = %q, want %q", tt.prefix, tt.pkg, tt.service, have, tt.want)
+		}
+	}
+}
+
+func TestQueryFromRequestBody(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"application/protobuf", "encoding=protobuf&message=AQI"},
+		{"application/protobuf; charset=binary", "encoding=protobuf&message=AQI"},
+		{"Application/Protobuf", "encoding=protobuf&message=AQI"},
+		{"application/json", "encoding=json&message=%01%02"},
+	}
+	for _, tt := range tests {
+		if have := queryFromRequestBody([]byte{1, 2}, tt.contentType); have != tt.want {
+			t.Errorf("queryFromRequestBody(%q) = %q, want %q", tt.contentType, have, tt.want)
+		}
+	}
+}
+
+func TestReadServerCodecs(t *testing.T) {
+	codecs := ReadServerCodecs(NewServerOptions(nil))
+	if codecs["application/protobuf"] != ProtobufCodec {
+		t.Errorf("expected the built-in protobuf codec, have %v", codecs["application/protobuf"])
+	}
+	msg := &wrapperspb.StringValue{}
+	if err := codecs["application/json"].Unmarshal([]byte(`"hat" trailing data`), msg); err != nil || msg.Value != "hat" {
+		t.Errorf("expected data after the JSON value to be ignored, have msg=%v, err=%v", msg, err)
+	}
+
+	codecs = ReadServerCodecs(NewServerOptions([]interface{}{twirp.WithServerJSONStrict(true)}))
+	if err := codecs["application/json"].Unmarshal([]byte(`"hat" trailing data`), msg); err == nil {
+		t.Error("expected strict JSON codec to reject data after the JSON value")
+	}
+
+	custom := NewJSONCodec(protojson.MarshalOptions{}, protojson.UnmarshalOptions{})
+	codecs = ReadServerCodecs(NewServerOptions([]interface{}{twirp.WithServerCodecs(custom)}))
+	if codecs["application/json"] != custom {
+		t.Errorf("expected the custom codec to replace the built-in JSON codec, have %v", codecs["application/json"])
+	}
+
+	msgpack := NewJSONCodec(protojson.MarshalOptions{}, protojson.UnmarshalOptions{}) // any codec, keyed by its media type
+	codecs = ReadServerCodecs(NewServerOptions([]interface{}{twirp.WithServerCodecs(mediaTypeCodec{msgpack, "Application/X-MsgPack; charset=binary"})}))
+	if _, ok := codecs["application/x-msgpack"]; !ok {
+		t.Errorf("expected the custom codec to be keyed by its media type, have %v", codecs)
+	}
+}
+
+func TestRequestCodec(t *testing.T) {
+	codecs := ReadServerCodecs(NewServerOptions(nil))
+	tests := []struct {
+		contentType string
+		want        twirp.Codec
+	}{
+		{"application/protobuf", ProtobufCodec},
+		{"Application/Protobuf; charset=utf-8", ProtobufCodec},
+		{" application/json ;charset=utf-8", codecs["application/json"]},
+		{"application/x-msgpack", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("POST", "http://example.com", nil)
+		req.Header.Set("Content-Type", tt.contentType)
+		codec, ok := RequestCodec(codecs, req)
+		if ok != (tt.want != nil) || codec != tt.want {
+			t.Errorf("RequestCodec(%q) = %v, %v, want %v", tt.contentType, codec, ok, tt.want)
+		}
+	}
+}
+
+// mediaTypeCodec overrides the content type of a codec.
+type mediaTypeCodec struct {
+	twirp.Codec
+	contentType string
+}
+
+func (c mediaTypeCodec) ContentType() string { return c.contentType }
+
+func TestParseTwirpPath(t *testing.T) {
+	tests := []struct {
+		path                       string
+		prefix, pkgService, method string
+	}{
+		{"/twirp/pkg.Svc/MakeHat", "/twirp", "pkg.Svc", "MakeHat"},
+		{"/Svc/MakeHat", "", "Svc", "MakeHat"},
+		{"/a/b/pkg.Svc/MakeHat", "/a/b", "pkg.Svc", "MakeHat"},
+		{"MakeHat", "", "", ""},
+	}
+	for _, tt := range tests {
+		prefix, pkgService, method := ParseTwirpPath(tt.path)
+		if prefix != tt.prefix || pkgService != tt.pkgService || method != tt.method {
+			t.Errorf("ParseTwirpPath(%q) = %q, %q, %q, want %q, %q, %q", tt.path, prefix, pkgService, method, tt.prefix, tt.pkgService, tt.method)
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	// The client rounds up to milliseconds, the server reads the same timeout
+	if have := formatTwirpTimeout(1500 * time.Microsecond); have != "2" {
+		t.Errorf("formatTwirpTimeout(1.5ms) = %q, want %q", have, "2")
+	}
+	if have := formatTwirpTimeout(-time.Second); have != "1" {
+		t.Errorf("formatTwirpTimeout(-1s) = %q, want %q", have, "1")
+	}
+
+	tests := []struct {
+		header     string
+		maxTimeout time.Duration
+		want       time.Duration // 0 if there is no deadline
+		wantErr    bool
+	}{
+		{"", 0, 0, false},
+		{"5000", 0, 5 * time.Second, false},
+		{"5000", time.Second, time.Second, false},
+		{"0", 0, 0, true},
+		{"abc", 0, 0, true},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("POST", "http://example.com", nil)
+		if tt.header != "" {
+			req.Header.Set("Twirp-Timeout", tt.header)
+		}
+		ctx, cancel, applied, err := WithRequestTimeout(context.Background(), req, tt.maxTimeout)
+		cancel()
+		if tt.wantErr {
+			var twerr twirp.Error
+			if err == nil || !errors.As(err, &twerr) || twerr.Code() != twirp.Malformed {
+				t.Errorf("header %q: expected a malformed error, have %v", tt.header, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("header %q: unexpected error %v", tt.header, err)
+			continue
+		}
+		deadline, ok := ctx.Deadline()
+		if applied != (tt.want != 0) {
+			t.Errorf("header %q: WithRequestTimeout reported applied=%v, want timeout %v", tt.header, applied, tt.want)
+		}
+		if ok != (tt.want != 0) {
+			t.Errorf("header %q: unexpected deadline %v, want timeout %v", tt.header, deadline, tt.want)
+			continue
+		}
+		if ok {
+			if left := time.Until(deadline); left > tt.want || left < tt.want-time.Second {
+				t.Errorf("header %q: unexpected timeout %v, want %v", tt.header, left, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirptest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/twitchtv/twirp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Call is a request recorded by a Client, with the response of the handler.
+type Call struct {
+	// Request is the request received by the handler. Its body was already read,
+	// use RequestBody instead.
+	Request *http.Request
+	// RequestBody is the request body sent by the client, compressed if the
+	// request has a Content-Encoding header.
+	RequestBody []byte
+
+	// StatusCode is the HTTP status of the response, or 0 if the handler
+	// panicked before writing the headers.
+	StatusCode int
+	// ResponseHeader has the headers of the response, at the time they were written.
+	ResponseHeader http.Header
+	// ResponseBody is the full response body written by the handler, compressed
+	// if the response has a Content-Encoding header.
+	ResponseBody []byte
+
+	// Panic is the error made from the value recovered from a handler panic,
+	// or nil if the handler did not panic.
+	Panic error
+}
+
+// Service returns the fully qualified name of the service from the request
+// path, e.g. "twirp.example.haberdasher.Haberdasher".
+func (c *Call) Service() string {
+	service, _ := c.route()
+	return service
+}
+
+// Method returns the name of the method from the request path, e.g. "MakeHat".
+func (c *Call) Method() string {
+	_, method := c.route()
+	return method
+}
+
+// route splits the request path in the service and the method name. Twirp
+// paths are "[<prefix>]/<package>.<Service>/<Method>".
+func (c *Call) route() (service, method string) {
+	parts := strings.Split(c.Request.URL.Path, "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
+
+// Error returns the Twirp error from an error response, or nil if the response
+// is successful or not a Twirp error.
+func (c *Call) Error() twirp.Error {
+	if c.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, err := decompress(c.ResponseHeader, c.ResponseBody)
+	if err != nil {
+		return nil
+	}
+	var tj struct {
+		Code string            `json:"code"`
+		Msg  string            `json:"msg"`
+		Meta map[string]string `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &tj); err != nil {
+		return nil
+	}
+	code := twirp.ErrorCode(tj.Code)
+	if !twirp.IsValidErrorCode(code) {
+		return nil
+	}
+	twerr := twirp.NewError(code, tj.Msg)
+	for k, v := range tj.Meta {
+		twerr = twerr.WithMeta(k, v)
+	}
+	return twerr
+}
+
+// UnmarshalRequest decodes the request message into msg, from Protobuf or JSON
+// depending on the request Content-Type. Requests made with HTTP GET are
+// decoded from the query string.
+func (c *Call) UnmarshalRequest(msg proto.Message) error {
+	if c.Request.Method == http.MethodGet {
+		query := c.Request.URL.Query()
+		message := query.Get("message")
+		if query.Get("encoding") == "protobuf" {
+			body, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(message, "="))
+			if err != nil {
+				return err
+			}
+			return proto.Unmarshal(body, msg)
+		}
+		if message == "" {
+			message = "{}"
+		}
+		return unmarshal("application/json", []byte(message), msg)
+	}
+
+	body, err := decompress(c.Request.Header, c.RequestBody)
+	if err != nil {
+		return err
+	}
+	return unmarshal(c.Request.Header.Get("Content-Type"), body, msg)
+}
+
+// UnmarshalResponse decodes the response message of a successful unary call
+// into msg, from Protobuf or JSON depending on the response Content-Type.
+func (c *Call) UnmarshalResponse(msg proto.Message) error {
+	if c.StatusCode != http.StatusOK {
+		return errors.New("twirptest: can not unmarshal an error response")
+	}
+	body, err := decompress(c.ResponseHeader, c.ResponseBody)
+	if err != nil {
+		return err
+	}
+	return unmarshal(c.ResponseHeader.Get("Content-Type"), body, msg)
+}
+
+func unmarshal(contentType string, body []byte, msg proto.Message) error {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	switch strings.TrimSpace(strings.ToLower(contentType)) {
+	case "application/protobuf":
+		return proto.Unmarshal(body, msg)
+	case "application/json":
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, msg)
+	default:
+		return errors.New("twirptest: unexpected Content-Type " + contentType)
+	}
+}
+
+// decompress decodes the body with the compressor registered for its
+// Content-Encoding, if any.
+func decompress(header http.Header, body []byte) ([]byte, error) {
+	encoding := header.Get("Content-Encoding")
+	if encoding == "" || strings.EqualFold(encoding, "identity") {
+		return body, nil
+	}
+	c, ok := twirp.LookupCompressor(encoding)
+	if !ok {
+		return nil, errors.New("twirptest: unknown Content-Encoding " + encoding)
+	}
+	r, err := c.Decompress(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
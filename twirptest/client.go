@@ -0,0 +1,302 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package twirptest provides utilities to test Twirp services and clients
+// without network listeners.
+//
+// A Client implements the HTTPClient interface of generated clients, sending
+// each request directly to an http.Handler in the same process, usually a
+// generated Twirp server. Requests and responses are fully serialized, like
+// over the network, and every call is recorded to make assertions in tests.
+//
+// Usage example:
+//
+//	server := haberdasher.NewHaberdasherServer(svc)
+//	httpClient := twirptest.NewClient(server)
+//	client := haberdasher.NewHaberdasherProtobufClient("http://svc", httpClient)
+//
+//	hat, err := client.MakeHat(ctx, &haberdasher.Size{Inches: 12})
+//	...
+//	call, _ := httpClient.LastCall()
+//	if call.Method() != "MakeHat" || call.StatusCode != 200 { ... }
+package twirptest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RemoteAddr is the remote address of requests received by the handler,
+// in the same reserved range used by net/http/httptest.
+const RemoteAddr = "192.0.2.1:1234"
+
+// Client is an HTTPClient that sends requests to an http.Handler in-process.
+// Headers, status codes and bodies are sent as they are, and the context of
+// the request is canceled on the server if it is canceled on the client.
+//
+// Calls are recorded and can be inspected with Calls and LastCall.
+// A Client is safe for concurrent use.
+type Client struct {
+	handler http.Handler
+
+	mu    sync.Mutex
+	calls []*Call
+}
+
+// NewClient returns a Client that sends requests to the handler.
+func NewClient(handler http.Handler) *Client {
+	return &Client{handler: handler}
+}
+
+// Do sends the request to the handler and returns the response as soon as the
+// handler writes the response headers. The response body is streamed from the
+// handler, so it must be closed by the caller, like with an http.Client.
+//
+// An error is returned if the request context is done before the headers are
+// written, or if the handler panics before that.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if err := ctx.Err(); err != nil {
+		return nil, urlError(req, err)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, urlError(req, err)
+		}
+	}
+
+	serverCtx, cancel := context.WithCancel(ctx)
+	serverReq := newServerRequest(serverCtx, req, reqBody)
+	call := &Call{Request: serverReq, RequestBody: reqBody}
+
+	pr, pw := io.Pipe()
+	w := &responseWriter{
+		header: make(http.Header),
+		body:   pw,
+		ready:  make(chan struct{}),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer cancel() // like net/http, the request context is canceled after the handler returns
+		panicErr := serve(c.handler, w, serverReq)
+		w.finish(panicErr)
+
+		call.StatusCode = w.status
+		call.ResponseHeader = w.sentHeader
+		call.ResponseBody = w.recorded.Bytes()
+		call.Panic = panicErr
+		c.record(call)
+
+		if panicErr != nil {
+			_ = pw.CloseWithError(panicErr)
+		} else {
+			_ = pw.Close()
+		}
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = pr.CloseWithError(ctx.Err())
+		case <-done:
+		}
+	}()
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		cancel()
+		_ = pr.CloseWithError(ctx.Err())
+		return nil, urlError(req, ctx.Err())
+	}
+	if w.status == 0 { // the handler panicked before writing the headers
+		return nil, urlError(req, w.err)
+	}
+
+	resp := &http.Response{
+		Status:        strconv.Itoa(w.status) + " " + http.StatusText(w.status),
+		StatusCode:    w.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.sentHeader.Clone(),
+		Body:          &responseBody{PipeReader: pr, cancel: cancel},
+		ContentLength: -1,
+		Request:       req,
+	}
+	if cl, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+		resp.ContentLength = cl
+	}
+	return resp, nil
+}
+
+// Calls returns the recorded calls, in the order they were completed.
+// Calls are recorded when the handler returns.
+func (c *Client) Calls() []*Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := make([]*Call, len(c.calls))
+	copy(calls, c.calls)
+	return calls
+}
+
+// LastCall returns the last recorded call, or false if there are none.
+func (c *Client) LastCall() (*Call, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.calls) == 0 {
+		return nil, false
+	}
+	return c.calls[len(c.calls)-1], true
+}
+
+// Reset forgets the recorded calls.
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = nil
+}
+
+func (c *Client) record(call *Call) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, call)
+}
+
+// newServerRequest builds the request received by the handler, as it would
+// be parsed by an HTTP server.
+func newServerRequest(ctx context.Context, req *http.Request, body []byte) *http.Request {
+	serverReq := req.Clone(ctx)
+	serverReq.Proto, serverReq.ProtoMajor, serverReq.ProtoMinor = "HTTP/1.1", 1, 1
+	serverReq.URL = &url.URL{Path: req.URL.Path, RawPath: req.URL.RawPath, RawQuery: req.URL.RawQuery}
+	serverReq.RequestURI = req.URL.RequestURI()
+	serverReq.RemoteAddr = RemoteAddr
+	if serverReq.Host == "" {
+		serverReq.Host = req.URL.Host
+	}
+	serverReq.Body = io.NopCloser(bytes.NewReader(body))
+	serverReq.ContentLength = int64(len(body))
+	serverReq.GetBody = nil
+	return serverReq
+}
+
+// serve calls the handler, returning an error if it panics. Like net/http,
+// panics with http.ErrAbortHandler only abort the response.
+func serve(handler http.Handler, w http.ResponseWriter, req *http.Request) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if r == http.ErrAbortHandler {
+				err = http.ErrAbortHandler
+				return
+			}
+			err = fmt.Errorf("twirptest: panic serving %s: %v", req.URL.Path, r)
+		}
+	}()
+	handler.ServeHTTP(w, req)
+	return nil
+}
+
+// urlError wraps errors like the net/http client does.
+func urlError(req *http.Request, err error) error {
+	op := "Get"
+	if req.Method != "" && req.Method != http.MethodGet {
+		op = req.Method[:1] + strings.ToLower(req.Method[1:])
+	}
+	return &url.Error{Op: op, URL: req.URL.String(), Err: err}
+}
+
+// responseWriter streams the response to the client through a pipe, and
+// records it for the Call.
+type responseWriter struct {
+	header http.Header
+	body   *io.PipeWriter
+
+	mu         sync.Mutex
+	status     int // 0 until the headers are written
+	sentHeader http.Header
+	ready      chan struct{}
+	err        error // handler panic, if the headers were not written
+	recorded   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.writeHeader(status)
+}
+
+func (w *responseWriter) writeHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	w.sentHeader = w.header.Clone()
+	close(w.ready)
+}
+
+// finish is called when the handler returns. The headers are written with a
+// 200 status if the handler did not write them, unless it panicked.
+func (w *responseWriter) finish(panicErr error) {
+	if panicErr == nil {
+		w.writeHeader(http.StatusOK)
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		w.err = panicErr
+		close(w.ready)
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.writeHeader(http.StatusOK)
+	w.mu.Lock()
+	w.recorded.Write(b)
+	w.mu.Unlock()
+	return w.body.Write(b)
+}
+
+// Flush implements http.Flusher. Writes are not buffered, so it only sends
+// the headers if they were not sent yet.
+func (w *responseWriter) Flush() {
+	w.writeHeader(http.StatusOK)
+}
+
+// responseBody cancels the request context when the response body is closed,
+// so handlers stop writing responses that will not be read.
+type responseBody struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (b *responseBody) Close() error {
+	b.cancel()
+	return b.PipeReader.Close()
+}
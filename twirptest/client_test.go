@@ -0,0 +1,182 @@
+// Copyright 2026 Twitch Interactive, Inc.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the License is
+// located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package twirptest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/twitchtv/twirp"
+	haberdasher "github.com/twitchtv/twirp/internal/twirptest"
+)
+
+type hatmaker func(ctx context.Context, s *haberdasher.Size) (*haberdasher.Hat, error)
+
+func (h hatmaker) MakeHat(ctx context.Context, s *haberdasher.Size) (*haberdasher.Hat, error) {
+	return h(ctx, s)
+}
+
+func TestClient(t *testing.T) {
+	h := hatmaker(func(ctx context.Context, s *haberdasher.Size) (*haberdasher.Hat, error) {
+		reqHeader, _ := twirp.HTTPRequestHeaders(ctx)
+		if err := twirp.SetHTTPResponseHeader(ctx, "X-Echo", reqHeader.Get("X-Custom")); err != nil {
+			return nil, err
+		}
+		return &haberdasher.Hat{Size: s.Inches, Color: "blue"}, nil
+	})
+	httpClient := NewClient(haberdasher.NewHaberdasherServer(h))
+
+	clients := map[string]haberdasher.Haberdasher{
+		"protobuf": haberdasher.NewHaberdasherProtobufClient("http://svc", httpClient),
+		"json":     haberdasher.NewHaberdasherJSONClient("http://svc", httpClient),
+	}
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			header := make(http.Header)
+			header.Set("X-Custom", "custom value")
+			ctx, err := twirp.WithHTTPRequestHeaders(context.Background(), header)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hat, err := client.MakeHat(ctx, &haberdasher.Size{Inches: 8})
+			if err != nil {
+				t.Fatalf("MakeHat err=%v", err)
+			}
+			if hat.Size != 8 || hat.Color != "blue" {
+				t.Errorf("unexpected hat %v", hat)
+			}
+
+			call, ok := httpClient.LastCall()
+			if !ok {
+				t.Fatal("no calls recorded")
+			}
+			if call.Service() != "twirp.internal.twirptest.Haberdasher" || call.Method() != "MakeHat" {
+				t.Errorf("recorded call to %s/%s", call.Service(), call.Method())
+			}
+			if call.StatusCode != http.StatusOK || call.Panic != nil || call.Error() != nil {
+				t.Errorf("recorded status %d, panic %v, error %v", call.StatusCode, call.Panic, call.Error())
+			}
+			if call.Request.RemoteAddr != RemoteAddr {
+				t.Errorf("request RemoteAddr=%q", call.Request.RemoteAddr)
+			}
+			if echo := call.ResponseHeader.Get("X-Echo"); echo != "custom value" {
+				t.Errorf("response header X-Echo=%q, want the request header", echo)
+			}
+
+			var size haberdasher.Size
+			if err := call.UnmarshalRequest(&size); err != nil || size.Inches != 8 {
+				t.Errorf("UnmarshalRequest size=%v err=%v", &size, err)
+			}
+			var recorded haberdasher.Hat
+			if err := call.UnmarshalResponse(&recorded); err != nil || recorded.Color != "blue" {
+				t.Errorf("UnmarshalResponse hat=%v err=%v", &recorded, err)
+			}
+		})
+	}
+
+	if calls := httpClient.Calls(); len(calls) != 2 {
+		t.Errorf("recorded %d calls, want 2", len(calls))
+	}
+	httpClient.Reset()
+	if _, ok := httpClient.LastCall(); ok {
+		t.Error("calls recorded after Reset")
+	}
+}
+
+func TestClientError(t *testing.T) {
+	h := hatmaker(func(ctx context.Context, s *haberdasher.Size) (*haberdasher.Hat, error) {
+		return nil, twirp.NotFoundError("no hats").WithMeta("retryable", "false")
+	})
+	httpClient := NewClient(haberdasher.NewHaberdasherServer(h))
+	client := haberdasher.NewHaberdasherProtobufClient("http://svc", httpClient)
+
+	_, err := client.MakeHat(context.Background(), &haberdasher.Size{Inches: 8})
+	var twerr twirp.Error
+	if !errors.As(err, &twerr) || twerr.Code() != twirp.NotFound {
+		t.Fatalf("MakeHat err=%v, want not_found", err)
+	}
+
+	call, _ := httpClient.LastCall()
+	if call.StatusCode != http.StatusNotFound {
+		t.Errorf("recorded status %d", call.StatusCode)
+	}
+	recorded := call.Error()
+	if recorded == nil || recorded.Code() != twirp.NotFound || recorded.Msg() != "no hats" || recorded.Meta("retryable") != "false" {
+		t.Errorf("recorded error %v", recorded)
+	}
+	if err := call.UnmarshalResponse(&haberdasher.Hat{}); err == nil {
+		t.Error("UnmarshalResponse of an error response succeeded")
+	}
+}
+
+func TestClientContextCanceled(t *testing.T) {
+	serverCanceled := make(chan struct{})
+	h := hatmaker(func(ctx context.Context, s *haberdasher.Size) (*haberdasher.Hat, error) {
+		<-ctx.Done()
+		close(serverCanceled)
+		return nil, ctx.Err()
+	})
+	httpClient := NewClient(haberdasher.NewHaberdasherServer(h))
+	client := haberdasher.NewHaberdasherProtobufClient("http://svc", httpClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.MakeHat(ctx, &haberdasher.Size{Inches: 8})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("MakeHat err=%v, want context.DeadlineExceeded", err)
+	}
+
+	select {
+	case <-serverCanceled:
+	case <-time.After(time.Second):
+		t.Fatal("the request context was not canceled on the server")
+	}
+}
+
+func TestClientPanic(t *testing.T) {
+	httpClient := NewClient(haberdasher.NewHaberdasherServer(haberdasher.PanickyHatmaker("oops")))
+	client := haberdasher.NewHaberdasherProtobufClient("http://svc", httpClient)
+
+	_, err := client.MakeHat(context.Background(), &haberdasher.Size{Inches: 8})
+	var twerr twirp.Error
+	if !errors.As(err, &twerr) || twerr.Code() != twirp.Internal {
+		t.Fatalf("MakeHat err=%v, want internal", err)
+	}
+
+	call, _ := httpClient.LastCall()
+	if call.Panic == nil {
+		t.Error("the panic was not recorded")
+	}
+}
+
+func TestClientHandlerPanicBeforeHeaders(t *testing.T) {
+	httpClient := NewClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("oops")
+	}))
+	req, err := http.NewRequest("POST", "http://svc/twirp/pkg.Svc/Method", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := httpClient.Do(req); err == nil {
+		t.Fatal("expected an error")
+	}
+	call, _ := httpClient.LastCall()
+	if call.StatusCode != 0 || call.Panic == nil {
+		t.Errorf("recorded status %d, panic %v", call.StatusCode, call.Panic)
+	}
+}
@@ -20,3 +20,8 @@ const TwirpPackageIsVersion7 = true
 // TwirpPackageMinVersion_8_1_0 is required from generated code to
 // assert version compatibility at compile time.
 const TwirpPackageMinVersion_8_1_0 = true
+
+// TwirpPackageMinVersion_8_3_0 is required from generated code to
+// assert version compatibility at compile time. Generated code since
+// this version depends on the github.com/twitchtv/twirp/twirpruntime package.
+const TwirpPackageMinVersion_8_3_0 = true